@@ -6,7 +6,10 @@ import (
 	"log/slog"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/datacraft/catalyst/core/internal/adapter/llm"
 	"github.com/datacraft/catalyst/core/internal/adapter/mqtt"
@@ -14,6 +17,8 @@ import (
 	"github.com/datacraft/catalyst/core/internal/adapter/web"
 	"github.com/datacraft/catalyst/core/internal/domain"
 	"github.com/datacraft/catalyst/core/internal/service"
+	"github.com/datacraft/catalyst/core/internal/service/chat"
+	"github.com/datacraft/catalyst/core/internal/service/state"
 	"github.com/point-unknown/catalyst/pkg/env"
 	"github.com/point-unknown/catalyst/pkg/logger"
 	"github.com/point-unknown/catalyst/pkg/mcp"
@@ -27,6 +32,11 @@ var (
 )
 
 func main() {
+	// CLI mode: `server validate <file>` (e.g. in CI)
+	if len(os.Args) > 1 && os.Args[1] == "validate" {
+		os.Exit(runValidate(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	Log = logger.New("core-service")
 	Log.Info("Starting Catalyst Core Service (Phase 2)...")
 
@@ -61,15 +71,6 @@ func main() {
 		// 1.5.1 Initialize Workspace Manager (Context Resolver)
 		// Workspace Root is parent of current dir (d:\Datacraft\Catalyst -> d:\Datacraft)
 		workspaceMgr = service.NewWorkspaceManager(pgStore, "..")
-
-		// 1.5.2 Start HTTP API (Web Adapter)
-		webServer = web.NewServer(pgStore, workspaceMgr)
-		go func() {
-			apiAddr := env.Get("API_ADDR", ":8080") // Default port 8080
-			if err := webServer.Run(apiAddr); err != nil {
-				Log.Error("HTTP API Failed", "error", err)
-			}
-		}()
 	}
 
 	// 1.6 LLM Provider (The "Brain")
@@ -77,6 +78,8 @@ func main() {
 		Endpoint:    env.Get("LLM_ENDPOINT", "http://localhost:11434/v1"),
 		Model:       env.Get("LLM_MODEL", "qwen2.5-coder:7b-instruct"),
 		PrivateMode: env.Get("PRIVATE_MODE", "true") != "false",
+		// Context window of the default model (qwen2.5-coder: 32k)
+		MaxPromptTokens: envInt("LLM_MAX_PROMPT_TOKENS", 32768),
 	}
 
 	llmProvider, err := llm.NewOpenAIAdapter(llmCfg)
@@ -105,20 +108,27 @@ func main() {
 	})
 	Log.Info("✅ [MCP] Local Registry Initialized", "tools", 1)
 
-	// B. Register Standard Agents (Dynamic Loader)
+	// B. Agent Builder (Dynamic Loader)
 	configFile := env.Get("CATALYST_CONFIG_PATH", "config/agents.yaml")
 
-	// Pass vector store and workspace manager (as resolver) to loader
-	// If workspaceMgr is nil (store failed), agents relying on it will likely fail or fallback safely
-	loadedAgents, loadedMissions, err := service.LoadAgents(configFile, llmProvider, registry, pgStore.Vector, workspaceMgr)
-	if err != nil {
-		Log.Warn("⚠️ [LOADER] Failed to load agents.yaml. Running without agents.", "error", err)
-	} else {
-		for _, a := range loadedAgents {
-			// Register agent to the AgentRegistry Service
-			agentRegistryService.Register(a)
-			Log.Info("Registered Agent", "id", a.ID(), "type", a.Type())
-		}
+	// Pass vector store and workspace manager (as resolver) to the factory
+	// If the store failed, they stay nil and agents relying on them fall back safely
+	// Custom agent types register themselves: add a blank import of their package here
+	// Agent state lives in memory and, with a database, is written through to agent_state
+	deps := domain.AgentDeps{Tools: registry, State: state.NewManager(nil)}
+	if llmProvider != nil {
+		// Only when the adapter is up: a nil *OpenAIAdapter would be a non-nil LLMProvider
+		deps.LLM = llmProvider
+	}
+	if pgStore != nil {
+		deps.Vectors = pgStore.Vector
+		deps.State = state.NewManager(pgStore)
+	}
+	if workspaceMgr != nil {
+		deps.Resolver = workspaceMgr
+	}
+	buildAgent := func(cfg domain.AgentConfig) (domain.Agent, error) {
+		return service.AgentFactory(cfg, deps)
 	}
 
 	// ==========================================
@@ -142,25 +152,144 @@ func main() {
 		}
 	}
 	missionMgr := service.NewMissionManager(agentRegistryService, publisher)
+	if pgStore != nil {
+		missionMgr.SetRunStore(pgStore)
+
+		// Durable queue: runs survive restarts and are shared across replicas
+		if env.Get("MISSION_QUEUE", "memory") == "postgres" {
+			missionMgr.SetQueue(pgStore)
+			Log.Info("✅ [MISSION] Using durable Postgres queue")
+		}
+	}
+
+	// Alert lifecycle (dedup, silences, ack) and sensor time-series need the store
+	var alertSvc *service.AlertService
+	var sensorSeries *service.SensorSeries
+	if pgStore != nil {
+		alertSvc = service.NewAlertService(pgStore, publisher)
+		sensorSeries = service.NewSensorSeries(pgStore, service.SensorRetention{
+			Raw:    envDuration("SENSOR_RETENTION_RAW", service.DefaultSensorRetention.Raw),
+			Minute: envDuration("SENSOR_RETENTION_1M", service.DefaultSensorRetention.Minute),
+			Hour:   envDuration("SENSOR_RETENTION_1H", service.DefaultSensorRetention.Hour),
+		})
+	}
+
+	// Group chat: user messages wake roster agents on agent/<id>/wake (see mission-chat)
+	// Sessions are persisted to chat_sessions/chat_messages when the store is up
+	var chatStore domain.ChatStore
+	if pgStore != nil {
+		chatStore = pgStore
+	}
+	chatLLM := deps.LLM
+	chatSelector, err := chat.NewSelector(env.Get("CHAT_SELECTION", chat.SelectRoundRobin), chatLLM)
+	if err != nil {
+		Log.Warn("Invalid CHAT_SELECTION, using round robin", "error", err)
+		chatSelector, _ = chat.NewSelector(chat.SelectRoundRobin, nil)
+	}
+	var chatSummarizer chat.Summarizer
+	if chatLLM != nil {
+		// Histories longer than the prompt limit are summarized in chunks
+		chatSummarizer = &chat.LLMSummarizer{LLM: chatLLM, MaxPromptTokens: llmCfg.MaxPromptTokens}
+	}
+	chatMgr := chat.NewGroupChatManager(logger.New("chat"), publisher, chat.Config{
+		Roster:           envList("CHAT_ROSTER", chat.DefaultSpeaker),
+		Selector:         chatSelector,
+		Describe:         agentRegistryService.Description,
+		MaxTurns:         envInt("CHAT_MAX_TURNS", chat.DefaultMaxTurns),
+		TerminateKeyword: env.Get("CHAT_TERMINATE", chat.DefaultTerminateKeyword),
+		IdleTimeout:      envDuration("CHAT_IDLE_TIMEOUT", chat.DefaultIdleTimeout),
+		Store:            chatStore,
+		// Wakes carry a rolling LLM summary plus the recent turns within each agent's budget
+		HistoryTokens:      envInt("CHAT_HISTORY_TOKENS", chat.DefaultHistoryTokens),
+		AgentHistoryTokens: envIntMap("CHAT_AGENT_HISTORY_TOKENS"), // e.g. "SystemArchitect=4000,Liaison=1500"
+		Summarizer:         chatSummarizer,
+	})
+
+	// D. Load Agents & Missions (Configuration, hot-reloaded on change)
+	// With a store, the missions table is authoritative: agents.yaml only writes the missions
+	// it adds, changes or removes since it was last applied (also across restarts).
+	rootCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+
+	reloader := service.NewConfigReloader(configFile, buildAgent, agentRegistryService, missionMgr)
+	var catalog *service.MissionCatalog
+	if pgStore != nil {
+		catalog = service.NewMissionCatalog(pgStore, missionMgr, agentRegistryService)
+		reloader.SetCatalog(catalog)
+	}
+
+	if _, err := reloader.Reload(rootCtx); err != nil {
+		Log.Warn("⚠️ [LOADER] Failed to load agents.yaml. Running without agents.", "error", err)
+	}
+	for _, a := range agentRegistryService.List() {
+		Log.Info("Registered Agent", "id", a.ID(), "type", a.Type())
+	}
+
+	if catalog != nil {
+		if err := catalog.Refresh(rootCtx); err != nil {
+			Log.Warn("Some missions failed to load", "error", err)
+		}
+
+		syncInterval, err := time.ParseDuration(env.Get("MISSION_SYNC_INTERVAL", "10s"))
+		if err != nil {
+			Log.Warn("Invalid MISSION_SYNC_INTERVAL, using default", "error", err)
+			syncInterval = service.DefaultMissionSyncInterval
+		}
+		go catalog.Run(rootCtx, syncInterval)
+	}
+
+	watchInterval, err := time.ParseDuration(env.Get("CONFIG_WATCH_INTERVAL", "2s"))
+	if err != nil {
+		Log.Warn("Invalid CONFIG_WATCH_INTERVAL, using default", "error", err)
+		watchInterval = service.DefaultConfigWatchInterval
+	}
+	go reloader.Watch(rootCtx, watchInterval)
+
+	// Agent health on agent/<id>/heartbeat
+	heartbeatInterval, err := time.ParseDuration(env.Get("HEARTBEAT_INTERVAL", "30s"))
+	if err != nil {
+		Log.Warn("Invalid HEARTBEAT_INTERVAL, using default", "error", err)
+		heartbeatInterval = service.DefaultHeartbeatInterval
+	}
+	go agentRegistryService.RunHeartbeats(rootCtx, heartbeatInterval, publisher)
+
+	// Sensor rollups and retention
+	if sensorSeries != nil {
+		go sensorSeries.Run(rootCtx, envDuration("SENSOR_ROLLUP_INTERVAL", service.DefaultSensorRollupInterval))
+	}
 
-	// D. Load Missions (Configuration)
-	for _, m := range loadedMissions {
-		missionMgr.LoadMission(m)
+	// Alert ingestion (off the MQTT callbacks: dedup and silences hit the store)
+	if alertSvc != nil {
+		go alertSvc.Run(rootCtx)
+	}
+
+	// E. Start HTTP API (Web Adapter, requires the store)
+	if pgStore != nil {
+		webServer = web.NewServer(pgStore, workspaceMgr, agentRegistryService, missionMgr, catalog, reloader, alertSvc, sensorSeries, chatMgr)
+		go func() {
+			apiAddr := env.Get("API_ADDR", ":8080") // Default port 8080
+			if err := webServer.Run(apiAddr); err != nil {
+				Log.Error("HTTP API Failed", "error", err)
+			}
+		}()
 	}
 
 	// B. Subscribe Routes
 	// Route all sensor data to the Mission Manager
-	err = mqttClient.Subscribe("sensor/#", func(event domain.CloudEvent) {
+	err = mqttClient.Subscribe("sensor/#", func(topic string, event domain.CloudEvent) {
 		// 1. Persist (Best Effort)
 		if pgStore != nil {
 			go func() {
 				if err := pgStore.SaveEvent(context.Background(), event); err != nil {
 					Log.Warn("Failed to save event", "error", err)
 				}
+				if err := sensorSeries.Record(context.Background(), event); err != nil {
+					Log.Warn("Failed to record sensor reading", "error", err)
+				}
 			}()
 		}
 		// 2. Process
-		missionMgr.ProcessEvent(event)
+		missionMgr.ProcessEvent(topic, event)
 	})
 	if err != nil {
 		Log.Error("Failed to subscribe to sensors", "error", err)
@@ -168,20 +297,72 @@ func main() {
 	}
 
 	// Route Agent Inter-comms to Mission Manager
-	err = mqttClient.Subscribe("agent/#", func(event domain.CloudEvent) {
-		Log.Info("Agent Event Received", "type", event.Type, "source", event.Source)
-		missionMgr.ProcessEvent(event)
+	err = mqttClient.Subscribe("agent/#", func(topic string, event domain.CloudEvent) {
+		if event.Type != domain.HeartbeatEventType {
+			Log.Info("Agent Event Received", "topic", topic, "type", event.Type, "source", event.Source)
+		}
+		if alertSvc != nil && alertSvc.Handles(event.Type) {
+			alertSvc.Submit(event)
+		}
+		// Replies to the group chat
+		// (off the MQTT callback: the reply is written to the chat store)
+		if event.Type == domain.ChatMessageEventType && strings.HasSuffix(topic, "/output") {
+			go func() {
+				if err := chatMgr.ProcessAgentResponse(rootCtx, event); err != nil {
+					Log.Warn("Failed to process chat reply", "topic", topic, "error", err)
+				}
+			}()
+		}
+		missionMgr.ProcessEvent(topic, event)
 	})
 	if err != nil {
 		Log.Error("Failed to subscribe to agent events", "error", err)
 		os.Exit(1)
 	}
 
+	// Route user chat messages to the group chat
+	err = mqttClient.Subscribe(chat.UserMessageTopic, func(topic string, event domain.CloudEvent) {
+		go func() {
+			if err := chatMgr.HandleUserEvent(rootCtx, event); err != nil {
+				Log.Warn("Failed to handle chat message", "error", err)
+			}
+		}()
+	})
+	if err != nil {
+		Log.Error("Failed to subscribe to chat messages", "error", err)
+		os.Exit(1)
+	}
+
+	// A tool call is the turn of a chat agent that decided to act instead of answering
+	err = mqttClient.Subscribe("tool/call", func(topic string, event domain.CloudEvent) {
+		if event.Type != domain.ToolCallEventType {
+			return
+		}
+		go func() {
+			if err := chatMgr.ProcessToolCall(rootCtx, event); err != nil {
+				Log.Warn("Failed to process chat tool call", "error", err)
+			}
+		}()
+	})
+	if err != nil {
+		Log.Error("Failed to subscribe to tool calls", "error", err)
+		os.Exit(1)
+	}
+
+	// Route alert state changes (alert/<id>/opened|acknowledged|resolved) to missions
+	err = mqttClient.Subscribe("alert/#", func(topic string, event domain.CloudEvent) {
+		missionMgr.ProcessEvent(topic, event)
+	})
+	if err != nil {
+		Log.Error("Failed to subscribe to alert events", "error", err)
+		os.Exit(1)
+	}
+
 	// Route Repo Events (Indexing)
-	err = mqttClient.Subscribe("repo/#", func(event domain.CloudEvent) {
+	err = mqttClient.Subscribe("repo/#", func(topic string, event domain.CloudEvent) {
 		if event.Type == "repo.content" {
 			Log.Info("🧠 Indexing Code...", "size", len(event.Data))
-			if pgStore != nil && pgStore.Vector != nil {
+			if pgStore != nil && pgStore.Vector != nil && llmProvider != nil {
 				go func() {
 					ctx := context.Background()
 
@@ -225,7 +406,7 @@ func main() {
 			}
 		} else {
 			// Normal Repo Events (PR, Push) -> Mission Manager
-			missionMgr.ProcessEvent(event)
+			missionMgr.ProcessEvent(topic, event)
 		}
 	})
 
@@ -237,4 +418,63 @@ func main() {
 	<-sigChan
 
 	Log.Info("Shutting down Core Service...")
+	stopBackground()
+
+	// Drain in-flight missions before the store and broker connections close
+	drainCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := missionMgr.Shutdown(drainCtx); err != nil {
+		Log.Warn("Mission queues not fully drained", "error", err)
+	}
+
+	// Stop agents (lifecycle hooks, e.g. out-of-process agents)
+	if err := agentRegistryService.Shutdown(drainCtx); err != nil {
+		Log.Warn("Agents did not stop cleanly", "error", err)
+	}
+}
+
+// envDuration reads a duration from the environment, falling back to def if unset or invalid.
+func envDuration(key string, def time.Duration) time.Duration {
+	d, err := time.ParseDuration(env.Get(key, def.String()))
+	if err != nil {
+		Log.Warn("Invalid duration, using default", "key", key, "default", def, "error", err)
+		return def
+	}
+	return d
+}
+
+// envInt reads an integer from the environment, falling back to def if unset or invalid.
+func envInt(key string, def int) int {
+	n, err := strconv.Atoi(env.Get(key, strconv.Itoa(def)))
+	if err != nil {
+		Log.Warn("Invalid integer, using default", "key", key, "default", def, "error", err)
+		return def
+	}
+	return n
+}
+
+// envList reads a comma-separated list from the environment, skipping empty items.
+func envList(key, def string) []string {
+	var items []string
+	for _, item := range strings.Split(env.Get(key, def), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// envIntMap reads comma-separated key=integer pairs from the environment, skipping invalid ones.
+func envIntMap(key string) map[string]int {
+	values := make(map[string]int)
+	for _, item := range envList(key, "") {
+		k, v, _ := strings.Cut(item, "=")
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil || strings.TrimSpace(k) == "" {
+			Log.Warn("Invalid key=integer pair, ignoring it", "key", key, "item", item)
+			continue
+		}
+		values[strings.TrimSpace(k)] = n
+	}
+	return values
 }
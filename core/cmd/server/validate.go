@@ -0,0 +1,43 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/datacraft/catalyst/core/internal/service"
+)
+
+// runValidate implements `server validate <file>`: it checks an agents.yaml without
+// connecting to anything and returns the process exit code (0 valid, 1 invalid, 2 usage).
+func runValidate(args []string, stdout, stderr io.Writer) int {
+	if len(args) != 1 {
+		fmt.Fprintln(stderr, "usage: server validate <agents.yaml>")
+		return 2
+	}
+	path := args[0]
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", path, err)
+		return 1
+	}
+
+	cfg, err := service.ParseConfig(data)
+	if err != nil {
+		var cfgErrs service.ConfigErrors
+		if errors.As(err, &cfgErrs) {
+			// file:line: field: message (clickable in editors and CI logs)
+			for _, e := range cfgErrs {
+				fmt.Fprintf(stderr, "%s:%d: %s: %s\n", path, e.Line, e.Field, e.Message)
+			}
+		} else {
+			fmt.Fprintf(stderr, "%s: %v\n", path, err)
+		}
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "%s: OK (%d agents, %d missions)\n", path, len(cfg.Agents), len(cfg.Missions))
+	return 0
+}
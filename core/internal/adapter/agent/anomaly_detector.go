@@ -0,0 +1,333 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+)
+
+// Detector rule kinds.
+const (
+	RuleMean   = "mean"   // Moving average of the window outside [min, max]
+	RuleEWMA   = "ewma"   // Exponentially weighted moving average outside [min, max]
+	RuleZScore = "zscore" // Reading more than `limit` standard deviations from the window mean
+	RuleRate   = "rate"   // Change per second larger than `limit` (either direction)
+)
+
+// zscoreMinSamples is the window size below which z-scores are not evaluated.
+const zscoreMinSamples = 5
+
+// DetectorRule is one rule of an anomaly-detector. A reading is anomalous when any rule fires.
+// While an alert is open, the rule keeps firing until the reading is back inside its bounds
+// (or limit) by Hysteresis, so a value hovering at the threshold does not flap.
+type DetectorRule struct {
+	Kind       string   `yaml:"kind"`       // mean | ewma | zscore | rate
+	Min        *float64 `yaml:"min"`        // mean, ewma: lower bound
+	Max        *float64 `yaml:"max"`        // mean, ewma: upper bound
+	Alpha      float64  `yaml:"alpha"`      // ewma: smoothing factor in (0, 1] (default 0.3)
+	Limit      float64  `yaml:"limit"`      // zscore: |z| limit (default 3); rate: |change| per second
+	Hysteresis float64  `yaml:"hysteresis"` // Clear band inside the bounds or limit, in the same unit (default 0)
+}
+
+// AnomalyDetectorConfig is the `config:` block of an anomaly-detector agent.
+type AnomalyDetectorConfig struct {
+	Types      []string       `yaml:"types"`       // Event type patterns (default ["sensor.*"])
+	Window     int            `yaml:"window"`      // Readings kept per source for mean and zscore (default 20)
+	Rules      []DetectorRule `yaml:"rules"`       // At least one
+	AlertAfter int            `yaml:"alert_after"` // Consecutive anomalous readings before alerting (default 1)
+	ClearAfter int            `yaml:"clear_after"` // Consecutive normal readings before recovering (default 3)
+	Severity   string         `yaml:"severity"`    // Severity of the alerts (default "warning")
+}
+
+func (c *AnomalyDetectorConfig) Validate() error {
+	if len(c.Types) == 0 {
+		return fmt.Errorf("types must not be empty")
+	}
+	for _, p := range c.Types {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("types: bad pattern %q", p)
+		}
+	}
+	if c.Window < 2 {
+		return fmt.Errorf("window must be at least 2, got %d", c.Window)
+	}
+	if c.AlertAfter < 1 {
+		return fmt.Errorf("alert_after must be at least 1, got %d", c.AlertAfter)
+	}
+	if c.ClearAfter < 1 {
+		return fmt.Errorf("clear_after must be at least 1, got %d", c.ClearAfter)
+	}
+	if c.Severity == "" {
+		return fmt.Errorf("severity must not be empty")
+	}
+	if len(c.Rules) == 0 {
+		return fmt.Errorf("at least one rule is required")
+	}
+	for i := range c.Rules {
+		if err := c.Rules[i].validate(); err != nil {
+			return fmt.Errorf("rules[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validate checks the rule and fills in its defaults.
+func (r *DetectorRule) validate() error {
+	if r.Hysteresis < 0 {
+		return fmt.Errorf("hysteresis must not be negative, got %v", r.Hysteresis)
+	}
+	switch r.Kind {
+	case RuleMean, RuleEWMA:
+		if r.Min == nil && r.Max == nil {
+			return fmt.Errorf("%s needs min and/or max", r.Kind)
+		}
+		if r.Min != nil && r.Max != nil && *r.Min >= *r.Max {
+			return fmt.Errorf("min must be below max")
+		}
+		if r.Min != nil && r.Max != nil && *r.Min+2*r.Hysteresis >= *r.Max {
+			return fmt.Errorf("hysteresis leaves no normal range between min and max")
+		}
+		if r.Kind == RuleEWMA {
+			if r.Alpha == 0 {
+				r.Alpha = 0.3
+			}
+			if r.Alpha < 0 || r.Alpha > 1 {
+				return fmt.Errorf("alpha must be in (0, 1], got %v", r.Alpha)
+			}
+		}
+	case RuleZScore:
+		if r.Limit == 0 {
+			r.Limit = 3
+		}
+		if r.Limit < 0 {
+			return fmt.Errorf("limit must be positive, got %v", r.Limit)
+		}
+		if r.Hysteresis >= r.Limit {
+			return fmt.Errorf("hysteresis must be below limit")
+		}
+	case RuleRate:
+		if r.Limit <= 0 {
+			return fmt.Errorf("rate needs a positive limit")
+		}
+		if r.Hysteresis >= r.Limit {
+			return fmt.Errorf("hysteresis must be below limit")
+		}
+	case "":
+		return fmt.Errorf("kind is required")
+	default:
+		return fmt.Errorf("unknown kind %q (want %s, %s, %s or %s)", r.Kind, RuleMean, RuleEWMA, RuleZScore, RuleRate)
+	}
+	return nil
+}
+
+// AnomalyDetector is a "Communicator" agent that watches sensor readings for anomalies.
+// Every source keeps its own window and alert state in the agent state, so detection
+// survives restarts. Alerts are debounced: AnomalyEventType is emitted after alert_after
+// consecutive anomalous readings and RecoveryEventType after clear_after normal ones, where
+// readings only count as normal once they are past each rule's hysteresis band.
+type AnomalyDetector struct {
+	id    string
+	cfg   AnomalyDetectorConfig
+	state domain.AgentState
+}
+
+// detectorState is the persisted state of one source and metric.
+type detectorState struct {
+	Readings  []float64 `json:"readings"`        // Window, oldest first
+	EWMA      []float64 `json:"ewma,omitempty"`  // Per rule index, seeded with the first reading
+	LastValue *float64  `json:"last_value"`      // Previous reading, for rate rules
+	LastTime  time.Time `json:"last_time"`       // Time of the previous reading
+	Anomalous int       `json:"anomalous"`       // Consecutive anomalous readings
+	Normal    int       `json:"normal"`          // Consecutive normal readings
+	Alerting  bool      `json:"alerting"`        // An alert is open
+	Since     time.Time `json:"since,omitempty"` // When the open alert started
+}
+
+// NewAnomalyDetector creates an AnomalyDetector from a validated config, keeping its
+// windows in st.
+func NewAnomalyDetector(id string, cfg AnomalyDetectorConfig, st domain.AgentState) *AnomalyDetector {
+	return &AnomalyDetector{id: id, cfg: cfg, state: st}
+}
+
+func (a *AnomalyDetector) ID() string {
+	return a.id
+}
+
+func (a *AnomalyDetector) Type() domain.AgentType {
+	return domain.AgentTypeCommunicator
+}
+
+func (a *AnomalyDetector) Execute(ctx context.Context, input domain.CloudEvent) (*domain.CloudEvent, error) {
+	if !a.matches(input.Type) {
+		return nil, nil
+	}
+	reading, ok := domain.ParseSensorReading(input)
+	if !ok {
+		return nil, nil
+	}
+	value, at := reading.Value, reading.Time
+
+	var (
+		violations []string
+		eventType  string
+		since      time.Time
+	)
+	err := domain.UpdateState(ctx, a.state, input.Type+"@"+input.Source, func(st *detectorState) error {
+		violations = a.evaluate(st, value, at)
+
+		if len(violations) > 0 {
+			st.Anomalous++
+			st.Normal = 0
+		} else {
+			st.Normal++
+			st.Anomalous = 0
+		}
+
+		switch {
+		case !st.Alerting && st.Anomalous >= a.cfg.AlertAfter:
+			st.Alerting = true
+			st.Since = at
+			eventType = domain.AnomalyEventType
+		case st.Alerting && st.Normal >= a.cfg.ClearAfter:
+			st.Alerting = false
+			eventType = domain.RecoveryEventType
+		}
+		since = st.Since
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update state for %q: %w", input.Source, err)
+	}
+	if eventType == "" {
+		return nil, nil
+	}
+
+	report := domain.AnomalyReport{
+		Agent:      a.id,
+		Source:     input.Source,
+		Metric:     input.Type,
+		Value:      value,
+		Severity:   a.cfg.Severity,
+		Violations: violations,
+		Since:      since,
+	}
+	if eventType == domain.AnomalyEventType {
+		report.Message = fmt.Sprintf("%s from %s is anomalous: %s", input.Type, input.Source, strings.Join(violations, ", "))
+		log.Printf("  !!! [AGENT:%s] %s", a.id, report.Message)
+	} else {
+		report.Message = fmt.Sprintf("%s from %s is back to normal (%.2f)", input.Type, input.Source, value)
+		log.Printf("  >>> [AGENT:%s] %s", a.id, report.Message)
+	}
+
+	evt, err := domain.NewEvent("agent/"+a.id, eventType, report)
+	if err != nil {
+		return nil, err
+	}
+	return &evt, nil
+}
+
+func (a *AnomalyDetector) matches(eventType string) bool {
+	for _, p := range a.cfg.Types {
+		if ok, _ := path.Match(p, eventType); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// evaluate applies every rule to the reading, then adds it to the state.
+// Z-scores and rates compare against the readings before this one. While alerting,
+// the thresholds are tightened by each rule's hysteresis.
+func (a *AnomalyDetector) evaluate(st *detectorState, value float64, at time.Time) []string {
+	var violations []string
+
+	previous := st.Readings
+	st.Readings = append(st.Readings, value)
+	if len(st.Readings) > a.cfg.Window {
+		st.Readings = st.Readings[len(st.Readings)-a.cfg.Window:]
+	}
+	if len(st.EWMA) != len(a.cfg.Rules) {
+		st.EWMA = make([]float64, len(a.cfg.Rules))
+		for i := range st.EWMA {
+			st.EWMA[i] = value
+		}
+	}
+
+	for i, r := range a.cfg.Rules {
+		margin := 0.0
+		if st.Alerting {
+			margin = r.Hysteresis
+		}
+		switch r.Kind {
+		case RuleMean:
+			if v := outOfBounds("mean", mean(st.Readings), r, margin); v != "" {
+				violations = append(violations, v)
+			}
+		case RuleEWMA:
+			st.EWMA[i] = r.Alpha*value + (1-r.Alpha)*st.EWMA[i]
+			if v := outOfBounds("ewma", st.EWMA[i], r, margin); v != "" {
+				violations = append(violations, v)
+			}
+		case RuleZScore:
+			if len(previous) < zscoreMinSamples {
+				continue
+			}
+			m := mean(previous)
+			sd := stddev(previous, m)
+			if sd == 0 {
+				continue
+			}
+			if z := (value - m) / sd; math.Abs(z) > r.Limit-margin {
+				violations = append(violations, fmt.Sprintf("zscore %.2f beyond ±%g", z, r.Limit-margin))
+			}
+		case RuleRate:
+			if st.LastValue == nil {
+				continue
+			}
+			dt := at.Sub(st.LastTime).Seconds()
+			if dt <= 0 {
+				continue
+			}
+			if rate := (value - *st.LastValue) / dt; math.Abs(rate) > r.Limit-margin {
+				violations = append(violations, fmt.Sprintf("rate %.2f/s beyond ±%g", rate, r.Limit-margin))
+			}
+		}
+	}
+
+	st.LastValue = &value
+	st.LastTime = at
+	return violations
+}
+
+// outOfBounds checks v against the rule's bounds, narrowed by margin on both sides.
+func outOfBounds(name string, v float64, r DetectorRule, margin float64) string {
+	if r.Max != nil && v > *r.Max-margin {
+		return fmt.Sprintf("%s %.2f > %g", name, v, *r.Max-margin)
+	}
+	if r.Min != nil && v < *r.Min+margin {
+		return fmt.Sprintf("%s %.2f < %g", name, v, *r.Min+margin)
+	}
+	return ""
+}
+
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+func stddev(values []float64, m float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += (v - m) * (v - m)
+	}
+	return math.Sqrt(sum / float64(len(values)))
+}
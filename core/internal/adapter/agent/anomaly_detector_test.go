@@ -0,0 +1,187 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+)
+
+// newDetector builds an anomaly-detector the way agents.yaml does (defaults + validation).
+func newDetector(t *testing.T, raw map[string]interface{}) *AnomalyDetector {
+	t.Helper()
+	spec, ok := domain.LookupAgentType("anomaly-detector")
+	if !ok {
+		t.Fatal("anomaly-detector is not registered")
+	}
+	agent, err := spec.Build(domain.AgentConfig{ID: "Detector", Type: "anomaly-detector", Config: raw}, mustDecode(t, spec, raw), domain.AgentDeps{State: memoryStates{}})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	return agent.(*AnomalyDetector)
+}
+
+func mustDecode(t *testing.T, spec domain.AgentTypeSpec, raw map[string]interface{}) interface{} {
+	t.Helper()
+	typed, err := spec.DecodeConfig(raw)
+	if err != nil {
+		t.Fatalf("invalid config: %v", err)
+	}
+	return typed
+}
+
+// feed sends one reading and returns the emitted event type ("" for none).
+func feed(t *testing.T, a *AnomalyDetector, source, eventType string, value float64, at time.Time) string {
+	t.Helper()
+	data, _ := json.Marshal(map[string]interface{}{"value": value})
+	out, err := a.Execute(context.Background(), domain.CloudEvent{Source: source, Type: eventType, Data: data, Time: at})
+	if err != nil {
+		t.Fatalf("Execution failed: %v", err)
+	}
+	if out == nil {
+		return ""
+	}
+	return out.Type
+}
+
+func TestAnomalyDetector_HysteresisAndRecovery(t *testing.T) {
+	a := newDetector(t, map[string]interface{}{
+		"rules":       []interface{}{map[string]interface{}{"kind": "ewma", "max": 80.0, "alpha": 1.0}},
+		"alert_after": 2,
+		"clear_after": 2,
+	})
+	now := time.Now()
+	steps := []struct {
+		value float64
+		want  string
+	}{
+		{50, ""},
+		{90, ""}, // First anomalous reading: not yet
+		{95, domain.AnomalyEventType},
+		{99, ""}, // Already alerting
+		{50, ""}, // First normal reading: not yet
+		{91, ""}, // Flap resets the normal count
+		{50, ""},
+		{50, domain.RecoveryEventType},
+		{50, ""},
+	}
+	for i, s := range steps {
+		if got := feed(t, a, "sensor/cpu", "sensor.cpu.temp", s.value, now.Add(time.Duration(i)*time.Second)); got != s.want {
+			t.Errorf("step %d (%.0f): expected %q, got %q", i, s.value, s.want, got)
+		}
+	}
+}
+
+func TestAnomalyDetector_ClearBand(t *testing.T) {
+	a := newDetector(t, map[string]interface{}{
+		"rules":       []interface{}{map[string]interface{}{"kind": "ewma", "max": 80.0, "alpha": 1.0, "hysteresis": 5.0}},
+		"clear_after": 2,
+	})
+	now := time.Now()
+	steps := []struct {
+		value float64
+		want  string
+	}{
+		{81, domain.AnomalyEventType},
+		{79, ""}, // Below max but inside the clear band: still anomalous
+		{79, ""},
+		{79, ""},
+		{74, ""}, // Past the band: first normal reading
+		{74, domain.RecoveryEventType},
+		{79, ""}, // Not alerting: only max applies
+	}
+	for i, s := range steps {
+		if got := feed(t, a, "sensor/cpu", "sensor.cpu.temp", s.value, now.Add(time.Duration(i)*time.Second)); got != s.want {
+			t.Errorf("step %d (%.0f): expected %q, got %q", i, s.value, s.want, got)
+		}
+	}
+}
+
+func TestAnomalyDetector_PerSourceAndTypes(t *testing.T) {
+	a := newDetector(t, map[string]interface{}{
+		"rules": []interface{}{map[string]interface{}{"kind": "mean", "max": 80.0}},
+	})
+	now := time.Now()
+
+	if got := feed(t, a, "sensor/a", "sensor.disk.temp", 1000, now); got != domain.AnomalyEventType {
+		t.Errorf("expected alert for sensor/a, got %q", got)
+	}
+	if got := feed(t, a, "sensor/b", "sensor.disk.temp", 50, now); got != "" {
+		t.Errorf("expected sensor/b to have its own window, got %q", got)
+	}
+	if got := feed(t, a, "sensor/a", "metrics.disk.temp", 1000, now); got != "" {
+		t.Errorf("expected non-sensor types to be ignored, got %q", got)
+	}
+}
+
+func TestAnomalyDetector_ZScore(t *testing.T) {
+	a := newDetector(t, map[string]interface{}{
+		"rules": []interface{}{map[string]interface{}{"kind": "zscore", "limit": 3.0}},
+	})
+	now := time.Now()
+	for i, v := range []float64{50, 51, 49, 50, 51, 49} {
+		if got := feed(t, a, "s", "sensor.load", v, now); got != "" {
+			t.Fatalf("reading %d: unexpected %q", i, got)
+		}
+	}
+	if got := feed(t, a, "s", "sensor.load", 70, now); got != domain.AnomalyEventType {
+		t.Errorf("expected z-score alert, got %q", got)
+	}
+}
+
+func TestAnomalyDetector_RateOfChange(t *testing.T) {
+	a := newDetector(t, map[string]interface{}{
+		"rules": []interface{}{map[string]interface{}{"kind": "rate", "limit": 2.0}},
+	})
+	now := time.Now()
+	feed(t, a, "s", "sensor.pressure", 10, now)
+	if got := feed(t, a, "s", "sensor.pressure", 25, now.Add(10*time.Second)); got != "" {
+		t.Errorf("1.5/s should be within limit, got %q", got)
+	}
+	if got := feed(t, a, "s", "sensor.pressure", 5, now.Add(15*time.Second)); got != domain.AnomalyEventType {
+		t.Errorf("-4/s should alert, got %q", got)
+	}
+}
+
+func TestAnomalyDetector_ReportData(t *testing.T) {
+	a := newDetector(t, map[string]interface{}{
+		"rules":    []interface{}{map[string]interface{}{"kind": "mean", "max": 80.0}},
+		"severity": "critical",
+	})
+	data, _ := json.Marshal(map[string]interface{}{"value": 100.0})
+	out, err := a.Execute(context.Background(), domain.CloudEvent{Source: "sensor/cpu", Type: "sensor.cpu.temp", Data: data})
+	if err != nil || out == nil {
+		t.Fatalf("expected an alert, got %v, %v", out, err)
+	}
+
+	var report domain.AnomalyReport
+	if err := json.Unmarshal(out.Data, &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Severity != "critical" || report.Source != "sensor/cpu" || report.Metric != "sensor.cpu.temp" || report.Value != 100 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	if len(report.Violations) != 1 || !strings.HasPrefix(report.Violations[0], "mean 100.00 > 80") {
+		t.Errorf("unexpected violations: %v", report.Violations)
+	}
+}
+
+func TestAnomalyDetectorConfig_Invalid(t *testing.T) {
+	spec, _ := domain.LookupAgentType("anomaly-detector")
+	cases := map[string]map[string]interface{}{
+		"no rules":      {},
+		"unknown kind":  {"rules": []interface{}{map[string]interface{}{"kind": "median"}}},
+		"bounds needed": {"rules": []interface{}{map[string]interface{}{"kind": "ewma"}}},
+		"rate limit":    {"rules": []interface{}{map[string]interface{}{"kind": "rate"}}},
+		"wide band":     {"rules": []interface{}{map[string]interface{}{"kind": "mean", "min": 10.0, "max": 20.0, "hysteresis": 5.0}}},
+		"bad pattern":   {"types": []interface{}{"sensor.["}, "rules": []interface{}{map[string]interface{}{"kind": "zscore"}}},
+	}
+	for name, raw := range cases {
+		if _, err := spec.DecodeConfig(raw); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,362 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+)
+
+// ExecConfig is the `config:` block of an exec agent.
+type ExecConfig struct {
+	Command     string            `yaml:"command"`      // Program to launch
+	Args        []string          `yaml:"args"`         // Program arguments
+	Env         map[string]string `yaml:"env"`          // Extra environment variables
+	Dir         string            `yaml:"dir"`          // Working directory (default: core's)
+	CallTimeout domain.Duration   `yaml:"call_timeout"` // Per-call limit when the caller sets none (default 30s)
+}
+
+func (c *ExecConfig) Validate() error {
+	if c.Command == "" {
+		return fmt.Errorf("command is required")
+	}
+	if c.CallTimeout < 0 {
+		return fmt.Errorf("call_timeout must not be negative")
+	}
+	return nil
+}
+
+const (
+	execDefaultCallTimeout = 30 * time.Second
+	execMaxLine            = 16 << 20 // Largest accepted response line
+)
+
+// ExecAgent runs an agent out of process. The program speaks line-delimited JSON-RPC 2.0
+// over stdin/stdout (one JSON object per line; stderr is forwarded to the log):
+//
+//	describe                     -> {"name": "...", "type": "reporter|communicator|expressor", "description": "..."}
+//	execute  {"event": <event>}  -> {"event": <event> | null}
+//	shutdown                     -> null, then the program exits
+//
+// The program is started by Start (see domain.Lifecycle) and restarted on the next call if it exits.
+type ExecAgent struct {
+	id  string
+	cfg ExecConfig
+
+	mu     sync.Mutex // Serializes calls; guards proc and nextID
+	proc   *execProcess
+	nextID int64
+
+	state sync.Mutex // Guards kind and live, so Type and Health never wait for a call
+	kind  domain.AgentType
+	live  *execProcess
+}
+
+// ExecDescription is the result of the describe method.
+type ExecDescription struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+}
+
+type rpcRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      int64       `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      int64           `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// execProcess is one running instance of the program.
+type execProcess struct {
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	responses chan rpcResponse
+	exited    chan struct{} // Closed once the program has exited
+	err       error         // Exit error, set before exited is closed
+}
+
+func NewExecAgent(id string, cfg ExecConfig) *ExecAgent {
+	return &ExecAgent{id: id, cfg: cfg, kind: domain.AgentTypeCommunicator}
+}
+
+func (a *ExecAgent) ID() string {
+	return a.id
+}
+
+func (a *ExecAgent) Type() domain.AgentType {
+	a.state.Lock()
+	defer a.state.Unlock()
+	return a.kind
+}
+
+func (a *ExecAgent) Execute(ctx context.Context, input domain.CloudEvent) (*domain.CloudEvent, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.callTimeout())
+		defer cancel()
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	proc, err := a.ensureStarted(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Event *domain.CloudEvent `json:"event"`
+	}
+	if err := a.call(ctx, proc, "execute", map[string]interface{}{"event": input}, &result); err != nil {
+		return nil, err
+	}
+	return result.Event, nil
+}
+
+// Start implements domain.Lifecycle: it launches the program and calls describe,
+// so a broken program fails the config load instead of the first mission.
+func (a *ExecAgent) Start(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	_, err := a.ensureStarted(ctx)
+	return err
+}
+
+// Stop implements domain.Lifecycle: it asks the program to shut down and kills it
+// if it does not exit before ctx expires.
+func (a *ExecAgent) Stop(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	proc := a.proc
+	a.setProc(nil)
+	if proc == nil {
+		return nil
+	}
+
+	if err := a.call(ctx, proc, "shutdown", nil, nil); err != nil {
+		log.Printf("[EXEC:%s] Shutdown request failed: %v", a.id, err)
+	}
+	proc.stdin.Close()
+
+	select {
+	case <-proc.exited:
+	case <-ctx.Done():
+		proc.cmd.Process.Kill()
+		<-proc.exited
+	}
+	return nil
+}
+
+// Health implements domain.Lifecycle.
+func (a *ExecAgent) Health(ctx context.Context) domain.AgentHealth {
+	a.state.Lock()
+	proc := a.live
+	a.state.Unlock()
+
+	now := time.Now().UTC()
+	if proc == nil {
+		return domain.AgentHealth{Status: domain.HealthDown, Message: "not started", CheckedAt: now}
+	}
+	select {
+	case <-proc.exited:
+		return domain.AgentHealth{Status: domain.HealthDegraded, Message: fmt.Sprintf("program exited (%v), restarts on next call", proc.err), CheckedAt: now}
+	default:
+		return domain.AgentHealth{Status: domain.HealthOK, CheckedAt: now}
+	}
+}
+
+// ensureStarted returns the running process, (re)starting it if needed. Callers hold a.mu.
+func (a *ExecAgent) ensureStarted(ctx context.Context) (*execProcess, error) {
+	if a.proc != nil {
+		select {
+		case <-a.proc.exited:
+			log.Printf("[EXEC:%s] Program exited (%v), restarting", a.id, a.proc.err)
+			a.setProc(nil)
+		default:
+			return a.proc, nil
+		}
+	}
+
+	proc, err := a.spawn()
+	if err != nil {
+		return nil, err
+	}
+
+	var desc ExecDescription
+	if err := a.call(ctx, proc, "describe", nil, &desc); err != nil {
+		proc.cmd.Process.Kill()
+		return nil, fmt.Errorf("describe failed: %w", err)
+	}
+	switch kind := domain.AgentType(desc.Type); kind {
+	case domain.AgentTypeReporter, domain.AgentTypeCommunicator, domain.AgentTypeExpressor:
+		a.state.Lock()
+		a.kind = kind
+		a.state.Unlock()
+	case "":
+	default:
+		log.Printf("[EXEC:%s] Unknown agent type %q from describe, using %s", a.id, desc.Type, a.Type())
+	}
+
+	log.Printf("[EXEC:%s] Started %s (%s): %s", a.id, a.cfg.Command, desc.Name, desc.Description)
+	a.setProc(proc)
+	return proc, nil
+}
+
+// setProc records the running process. Callers hold a.mu.
+func (a *ExecAgent) setProc(proc *execProcess) {
+	a.proc = proc
+	a.state.Lock()
+	a.live = proc
+	a.state.Unlock()
+}
+
+func (a *ExecAgent) spawn() (*execProcess, error) {
+	cmd := exec.Command(a.cfg.Command, a.cfg.Args...)
+	cmd.Dir = a.cfg.Dir
+	cmd.Env = os.Environ()
+	for k, v := range a.cfg.Env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", a.cfg.Command, err)
+	}
+
+	proc := &execProcess{
+		cmd:       cmd,
+		stdin:     stdin,
+		responses: make(chan rpcResponse, 16),
+		exited:    make(chan struct{}),
+	}
+
+	var pipes sync.WaitGroup
+	pipes.Add(2)
+	go func() {
+		defer pipes.Done()
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 64*1024), execMaxLine)
+		for scanner.Scan() {
+			var resp rpcResponse
+			if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+				log.Printf("[EXEC:%s] Ignoring invalid response line: %v", a.id, err)
+				continue
+			}
+			// Never block: a stuck reader would keep the process from being reaped
+			select {
+			case proc.responses <- resp:
+			default:
+				log.Printf("[EXEC:%s] Dropping unsolicited response %d", a.id, resp.ID)
+			}
+		}
+	}()
+	go func() {
+		defer pipes.Done()
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			log.Printf("[EXEC:%s] %s", a.id, scanner.Text())
+		}
+	}()
+	go func() {
+		pipes.Wait() // Wait must follow the reads from the pipes
+		proc.err = cmd.Wait()
+		close(proc.exited)
+	}()
+
+	return proc, nil
+}
+
+// call sends one request and waits for the matching response. Stale responses
+// (from calls that timed out earlier) are discarded. Callers hold a.mu.
+func (a *ExecAgent) call(ctx context.Context, proc *execProcess, method string, params, result interface{}) error {
+	// Discard late responses to earlier calls that timed out
+	for len(proc.responses) > 0 {
+		<-proc.responses
+	}
+
+	a.nextID++
+	req := rpcRequest{JSONRPC: "2.0", ID: a.nextID, Method: method, Params: params}
+	line, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	// A program that stops reading stdin blocks the write: give up with ctx and kill it,
+	// since a partly written request leaves the stream unusable (it restarts on the next call)
+	written := make(chan error, 1)
+	go func() {
+		_, err := proc.stdin.Write(append(line, '\n'))
+		written <- err
+	}()
+	select {
+	case err := <-written:
+		if err != nil {
+			return fmt.Errorf("%s: failed to write request: %w", method, err)
+		}
+	case <-ctx.Done():
+		proc.cmd.Process.Kill()
+		<-proc.exited
+		return fmt.Errorf("%s: failed to write request: %w", method, ctx.Err())
+	}
+
+	for {
+		select {
+		case resp := <-proc.responses:
+			if resp.ID != req.ID {
+				continue
+			}
+			if resp.Error != nil {
+				return fmt.Errorf("%s: %s (code %d)", method, resp.Error.Message, resp.Error.Code)
+			}
+			if result == nil || len(resp.Result) == 0 {
+				return nil
+			}
+			if err := json.Unmarshal(resp.Result, result); err != nil {
+				return fmt.Errorf("%s: invalid result: %w", method, err)
+			}
+			return nil
+		case <-proc.exited:
+			return fmt.Errorf("%s: program exited: %v", method, proc.err)
+		case <-ctx.Done():
+			return fmt.Errorf("%s: %w", method, ctx.Err())
+		}
+	}
+}
+
+func (a *ExecAgent) callTimeout() time.Duration {
+	if a.cfg.CallTimeout > 0 {
+		return time.Duration(a.cfg.CallTimeout)
+	}
+	return execDefaultCallTimeout
+}
@@ -0,0 +1,174 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+)
+
+// TestExecHelperProcess is not a real test: it is the external agent program,
+// launched by the tests below as a subprocess of the test binary.
+func TestExecHelperProcess(t *testing.T) {
+	if os.Getenv("CATALYST_EXEC_HELPER") != "1" {
+		return
+	}
+	defer os.Exit(0)
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var req struct {
+			ID     int64  `json:"id"`
+			Method string `json:"method"`
+			Params struct {
+				Event domain.CloudEvent `json:"event"`
+			} `json:"params"`
+		}
+		json.Unmarshal(scanner.Bytes(), &req)
+
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		switch req.Method {
+		case "describe":
+			resp["result"] = map[string]string{"name": "helper", "type": "expressor", "description": "echoes events"}
+		case "execute":
+			switch req.Params.Event.Type {
+			case "test.fail":
+				resp["error"] = map[string]interface{}{"code": -32000, "message": "boom"}
+			case "test.crash":
+				os.Exit(3)
+			case "test.sleep":
+				time.Sleep(time.Second)
+				resp["result"] = map[string]interface{}{"event": nil}
+			case "test.stall":
+				// Stop reading stdin
+				time.Sleep(time.Minute)
+			default:
+				out, _ := domain.NewEvent("helper", "echo."+req.Params.Event.Type, nil)
+				resp["result"] = map[string]interface{}{"event": out}
+			}
+		case "shutdown":
+			resp["result"] = nil
+			line, _ := json.Marshal(resp)
+			fmt.Println(string(line))
+			return
+		default:
+			resp["error"] = map[string]interface{}{"code": -32601, "message": "method not found"}
+		}
+		line, _ := json.Marshal(resp)
+		fmt.Println(string(line))
+	}
+}
+
+func newHelperAgent(t *testing.T) *ExecAgent {
+	t.Helper()
+	a := NewExecAgent("Helper", ExecConfig{
+		Command: os.Args[0],
+		Args:    []string{"-test.run=TestExecHelperProcess"},
+		Env:     map[string]string{"CATALYST_EXEC_HELPER": "1"},
+	})
+	if err := a.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	t.Cleanup(func() { a.Stop(context.Background()) })
+	return a
+}
+
+func TestExecAgent_Protocol(t *testing.T) {
+	a := newHelperAgent(t)
+	if a.Type() != domain.AgentTypeExpressor {
+		t.Errorf("Expected type from describe, got %s", a.Type())
+	}
+
+	in, _ := domain.NewEvent("test", "sensor.cpu", nil)
+	out, err := a.Execute(context.Background(), in)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if out == nil || out.Type != "echo.sensor.cpu" {
+		t.Errorf("Unexpected output: %+v", out)
+	}
+
+	in.Type = "test.fail"
+	if _, err := a.Execute(context.Background(), in); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("Expected RPC error, got %v", err)
+	}
+
+	// A timed-out call does not desynchronize later calls
+	in.Type = "test.sleep"
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := a.Execute(ctx, in); err == nil {
+		t.Errorf("Expected timeout")
+	}
+	in.Type = "sensor.mem"
+	if out, err := a.Execute(context.Background(), in); err != nil || out.Type != "echo.sensor.mem" {
+		t.Errorf("Expected call after timeout to succeed, got %+v, %v", out, err)
+	}
+}
+
+func TestExecAgent_RestartsAfterCrash(t *testing.T) {
+	a := newHelperAgent(t)
+
+	in, _ := domain.NewEvent("test", "test.crash", nil)
+	if _, err := a.Execute(context.Background(), in); err == nil {
+		t.Fatalf("Expected error when the program exits")
+	}
+	if h := a.Health(context.Background()); h.Status != domain.HealthDegraded {
+		t.Errorf("Expected degraded after crash, got %+v", h)
+	}
+
+	in.Type = "sensor.cpu"
+	if out, err := a.Execute(context.Background(), in); err != nil || out.Type != "echo.sensor.cpu" {
+		t.Errorf("Expected restart on next call, got %+v, %v", out, err)
+	}
+}
+
+func TestExecAgent_StalledProgram(t *testing.T) {
+	a := newHelperAgent(t)
+
+	in, _ := domain.NewEvent("test", "test.stall", nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := a.Execute(ctx, in); err == nil {
+		t.Fatalf("Expected timeout")
+	}
+
+	// The program no longer reads stdin: a request larger than the pipe buffer must not block past ctx
+	big, _ := domain.NewEvent("test", "sensor.cpu", map[string]string{"blob": strings.Repeat("x", 1<<20)})
+	ctx, cancel = context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		_, err := a.Execute(ctx, big)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatalf("Expected write timeout")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Execute blocked on a program that stopped reading stdin")
+	}
+
+	in.Type = "sensor.mem"
+	if out, err := a.Execute(context.Background(), in); err != nil || out.Type != "echo.sensor.mem" {
+		t.Errorf("Expected restart after the stalled program was killed, got %+v, %v", out, err)
+	}
+}
+
+func TestExecAgent_StartFailure(t *testing.T) {
+	a := NewExecAgent("Missing", ExecConfig{Command: "/nonexistent/agent"})
+	if err := a.Start(context.Background()); err == nil {
+		t.Errorf("Expected start failure")
+	}
+	if h := a.Health(context.Background()); h.Status != domain.HealthDown {
+		t.Errorf("Expected down, got %+v", h)
+	}
+}
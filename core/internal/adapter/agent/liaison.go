@@ -5,26 +5,61 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
 
 	"github.com/datacraft/catalyst/core/internal/domain"
+	"github.com/google/uuid"
 	"github.com/point-unknown/catalyst/pkg/logger"
 	"github.com/point-unknown/catalyst/pkg/mcp"
 	"github.com/point-unknown/catalyst/pkg/vector"
 )
 
+// LiaisonConfig is the `config:` block of a liaison agent.
+type LiaisonConfig struct {
+	Memories      int     `yaml:"memories"`       // Documents recalled per request (default 5)
+	ContextTokens int     `yaml:"context_tokens"` // Token budget of the retrieved context (default 1500)
+	MinScore      float32 `yaml:"min_score"`      // Ignore recalled documents scoring below this
+}
+
+func (c *LiaisonConfig) Validate() error {
+	if c.Memories < 1 {
+		return fmt.Errorf("memories must be at least 1, got %d", c.Memories)
+	}
+	if c.ContextTokens < minChunkTokens {
+		return fmt.Errorf("context_tokens must be at least %d, got %d", minChunkTokens, c.ContextTokens)
+	}
+	return nil
+}
+
+// DefaultLiaisonConfig is used for liaisons without a config block.
+var DefaultLiaisonConfig = LiaisonConfig{Memories: 5, ContextTokens: 1500}
+
 // LiaisonAgent is the Human-Swarm Interface.
-// It translates user chat into structured commands (Git Issues).
+// It answers chat messages and, when a request calls for it, translates it into a
+// tool call (e.g. a Git issue). The LLM decides which, based on the conversation,
+// recalled code and the tools in the registry. Replies cite the recalled code as [n].
 type LiaisonAgent struct {
 	id       string
+	cfg      LiaisonConfig
 	llm      domain.LLMProvider
 	logger   *slog.Logger
 	registry mcp.Registry
 	vector   vector.Store
 }
 
-func NewLiaisonAgent(id string, llm domain.LLMProvider, reg mcp.Registry, vec vector.Store) *LiaisonAgent {
+// liaisonDecision is the structured answer the LLM is asked for.
+type liaisonDecision struct {
+	Action    string                 `json:"action"` // "tool" or "reply"
+	Tool      string                 `json:"tool,omitempty"`
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+	Reply     string                 `json:"reply,omitempty"`
+}
+
+func NewLiaisonAgent(id string, llm domain.LLMProvider, reg mcp.Registry, vec vector.Store, cfg LiaisonConfig) *LiaisonAgent {
 	return &LiaisonAgent{
 		id:       id,
+		cfg:      cfg,
 		llm:      llm,
 		logger:   logger.New(fmt.Sprintf("agent-%s", id)),
 		registry: reg,
@@ -41,91 +76,197 @@ func (a *LiaisonAgent) Type() domain.AgentType {
 }
 
 func (a *LiaisonAgent) Execute(ctx context.Context, input domain.CloudEvent) (*domain.CloudEvent, error) {
-	// Trigger: "agent/{id}/wake" from GroupChatManager
-	if input.Type != fmt.Sprintf("agent/%s/wake", a.id) {
+	// Trigger: "chat.wake" on agent/{id}/wake from GroupChatManager
+	if input.Type != domain.ChatWakeEventType {
 		return nil, nil
 	}
 
-	// Unmarshal context data
-	var data map[string]interface{}
+	var wake domain.ChatWake
 	if len(input.Data) > 0 {
-		if err := json.Unmarshal(input.Data, &data); err != nil {
+		if err := json.Unmarshal(input.Data, &wake); err != nil {
 			a.logger.Warn("Failed to unmarshal wake data", "error", err)
-			data = make(map[string]interface{})
-		}
-	} else {
-		data = make(map[string]interface{})
-	}
-
-	a.logger.Info("Liaison woke up. Listening to chat context...", "context_len", data["context_len"])
-
-	// 0. Vibe Engine (RAG) retrieval
-	// We allow the 'user_input' to drive the search.
-	if userInput, ok := data["user_input"].(string); ok && userInput != "" && a.vector != nil {
-		a.logger.Info("🤔 Recalling memories...", "query", userInput)
-
-		// A. Embed
-		embedding, err := a.llm.Embed(ctx, userInput)
-		if err != nil {
-			a.logger.Warn("Failed to embed user input", "error", err)
-		} else {
-			// B. Search
-			results, err := a.vector.Search(ctx, embedding, 3)
-			if err != nil {
-				a.logger.Warn("Failed to search memories", "error", err)
-			} else {
-				a.logger.Info("found memories", "count", len(results))
-				for _, r := range results {
-					a.logger.Info("Memory", "score", r.Score, "preview", r.Content[:min(len(r.Content), 50)]+"...")
-				}
-				// TODO: Append results to LLM Context
-			}
 		}
 	}
+	if wake.TargetAgent != "" && wake.TargetAgent != a.id {
+		return nil, nil // Someone else's turn
+	}
+
+	a.logger.Info("Liaison woke up. Listening to chat context...", "history", len(wake.History))
+	if a.llm == nil {
+		return a.reply(wake.Session, "I'm listening, but no language model is configured.", nil)
+	}
+
+	// 0. Vibe Engine (RAG) retrieval, driven by the user's input
+	retrieved := buildRetrievedContext(a.recall(ctx, wake.UserInput), a.cfg.ContextTokens, a.cfg.MinScore)
 
 	// 1. List Available Tools
-	tools := a.registry.ListTools()
-	toolsListJSON, _ := json.Marshal(tools)
-	// Log the tools for debugging, but use the underscore to avoid "unused variable" error if we don't use it elsewhere yet.
-	_ = toolsListJSON
+	var tools []mcp.Tool
+	if a.registry != nil {
+		tools = a.registry.ListTools()
+	}
 	a.logger.Info("Available Tools", "count", len(tools))
 
-	// 2. "Think" (Simulate LLM tool selection)
-	// In reality, we pass 'toolsListJSON' to the LLM system prompt.
-	// For MVP, if input context implies "Refactor", we pick 'git_create_issue'.
-
-	// Simulated Decision:
-	triggerTool := true // Logic would go here
-
-	if triggerTool {
-		toolName := "git_create_issue"
-		// Check if tool exists
-		if _, ok := a.registry.GetTool(toolName); ok {
-			a.logger.Info("Decided to call tool", "tool", toolName)
-
-			// Emit Tool Call
-			toolCall := mcp.ToolCall{
-				ID:       "call_12345",
-				ToolName: toolName,
-				Arguments: map[string]interface{}{
-					"title": "User Request: Refactor Login",
-					"body":  "As requested in ChatOps session.",
-				},
-			}
-
-			// We return the Tool Call event.
-			// Type: "tool.call"
-			evt, _ := domain.NewEvent(a.id, "tool.call", toolCall)
-
-			return &evt, nil
+	// 2. Think: let the LLM pick a tool or answer
+	response, err := a.llm.GenerateCode(ctx, a.prompt(wake, retrieved, tools))
+	if err != nil {
+		a.logger.Warn("LLM request failed", "error", err)
+		return a.reply(wake.Session, "Sorry, I can't think right now (language model unavailable). Please try again.", nil)
+	}
+
+	decision, ok := parseDecision(response)
+	if !ok {
+		// Not the JSON we asked for: treat the whole answer as a chat reply
+		return a.reply(wake.Session, strings.TrimSpace(response), retrieved.Sources)
+	}
+	if decision.Action != "tool" {
+		return a.reply(wake.Session, decision.Reply, retrieved.Sources)
+	}
+
+	// 3. Act: validate the tool call before emitting it
+	call, err := a.toolCall(decision)
+	if err != nil {
+		a.logger.Warn("Rejected tool call", "tool", decision.Tool, "error", err)
+		msg := fmt.Sprintf("I wanted to use %q but couldn't: %v.", decision.Tool, err)
+		if decision.Reply != "" {
+			msg = decision.Reply + "\n\n" + msg
 		}
+		return a.reply(wake.Session, msg, nil)
+	}
+
+	// The note is the chat reply for this turn, so the user sees what is being done
+	a.logger.Info("Decided to call tool", "tool", call.ToolName)
+	reply := strings.TrimSpace(decision.Reply)
+	if reply == "" {
+		reply = fmt.Sprintf("Running %s.", call.ToolName)
 	}
+	evt, err := domain.NewEvent(a.id, domain.ToolCallEventType, domain.ChatToolCall{ToolCall: call, Session: wake.Session, Reply: reply})
+	if err != nil {
+		return nil, err
+	}
+	return &evt, nil
+}
+
+// recall returns memories related to the query, or nil if there is no vector store.
+func (a *LiaisonAgent) recall(ctx context.Context, query string) []vector.SearchResult {
+	if query == "" || a.vector == nil {
+		return nil
+	}
+	a.logger.Info("🤔 Recalling memories...", "query", query)
 
-	responseMsg := "I'm listening, but I didn't see a need to open a ticket."
-	reply, _ := domain.NewEvent(a.id, "chat.message", map[string]string{
-		"content": responseMsg,
-		"sender":  "Liaison",
+	embedding, err := a.llm.Embed(ctx, query)
+	if err != nil {
+		a.logger.Warn("Failed to embed user input", "error", err)
+		return nil
+	}
+	results, err := a.vector.Search(ctx, embedding, a.cfg.Memories)
+	if err != nil {
+		a.logger.Warn("Failed to search memories", "error", err)
+		return nil
+	}
+	a.logger.Info("found memories", "count", len(results))
+	return results
+}
+
+// prompt builds the decision prompt: instructions, tools, retrieved code and the conversation.
+func (a *LiaisonAgent) prompt(wake domain.ChatWake, retrieved retrievedContext, tools []mcp.Tool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "You are %s, the liaison between a human user and a swarm of software agents.\n", a.id)
+	b.WriteString("Decide whether the user's latest message needs one of the tools below, or just an answer.\n")
+	b.WriteString("Respond with ONLY one JSON object and nothing else, in one of these forms:\n")
+	b.WriteString(`{"action": "tool", "tool": "<tool name>", "arguments": {<arguments matching the tool's parameters>}, "reply": "<short note to the user>"}` + "\n")
+	b.WriteString(`{"action": "reply", "reply": "<your answer to the user>"}` + "\n")
+	b.WriteString("Only use a tool when the user clearly asks for what it does.\n")
+	if retrieved.Block != "" {
+		b.WriteString("When your reply relies on the retrieved code, cite it as [n].\n")
+	}
+
+	b.WriteString("\n## Tools\n")
+	if len(tools) == 0 {
+		b.WriteString("(none)\n")
+	}
+	for _, t := range tools {
+		params := strings.TrimSpace(string(t.Parameters))
+		if params == "" {
+			params = "{}"
+		}
+		fmt.Fprintf(&b, "- %s: %s\n  parameters: %s\n", t.Name, t.Description, params)
+	}
+
+	if retrieved.Block != "" {
+		b.WriteString("\n")
+		b.WriteString(retrieved.Block)
+	}
+
+	if wake.Summary != "" {
+		b.WriteString("\n## Earlier in the conversation (summary)\n")
+		b.WriteString(wake.Summary)
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n## Conversation\n")
+	for _, msg := range wake.History {
+		fmt.Fprintf(&b, "%s: %s\n", msg.Sender, msg.Content)
+	}
+	if wake.UserInput != "" && !slices.ContainsFunc(wake.History, func(m domain.ChatMessage) bool { return m.Content == wake.UserInput }) {
+		fmt.Fprintf(&b, "user: %s\n", wake.UserInput)
+	}
+	return b.String()
+}
+
+// toolCall turns a tool decision into a call, checking the tool exists and the arguments fit its schema.
+func (a *LiaisonAgent) toolCall(d liaisonDecision) (mcp.ToolCall, error) {
+	if a.registry == nil {
+		return mcp.ToolCall{}, fmt.Errorf("no tools are available")
+	}
+	tool, ok := a.registry.GetTool(d.Tool)
+	if !ok {
+		return mcp.ToolCall{}, fmt.Errorf("unknown tool")
+	}
+	if d.Arguments == nil {
+		d.Arguments = map[string]interface{}{}
+	}
+	if err := tool.ValidateArguments(d.Arguments); err != nil {
+		return mcp.ToolCall{}, err
+	}
+	return mcp.ToolCall{ID: "call_" + uuid.NewString(), ToolName: tool.Name, Arguments: d.Arguments}, nil
+}
+
+// reply builds a chat.message for the session, citing the sources it refers to.
+func (a *LiaisonAgent) reply(session, content string, sources []domain.ChatCitation) (*domain.CloudEvent, error) {
+	if content == "" {
+		content = "I'm listening, but I didn't see anything to act on."
+	}
+	cited := citeSources(content, sources)
+	evt, err := domain.NewEvent(a.id, domain.ChatMessageEventType, domain.ChatMessage{
+		Session:   session,
+		Sender:    a.id,
+		Content:   withSources(content, cited),
+		Citations: cited,
 	})
+	if err != nil {
+		return nil, err
+	}
+	return &evt, nil
+}
 
-	return &reply, nil
+// parseDecision extracts the JSON decision from an LLM response, tolerating code fences
+// and text around the object.
+func parseDecision(response string) (liaisonDecision, bool) {
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+	if start < 0 || end < start {
+		return liaisonDecision{}, false
+	}
+
+	var d liaisonDecision
+	if err := json.Unmarshal([]byte(response[start:end+1]), &d); err != nil {
+		return liaisonDecision{}, false
+	}
+	switch d.Action {
+	case "tool":
+		return d, d.Tool != ""
+	case "reply":
+		return d, true
+	}
+	return liaisonDecision{}, false
 }
@@ -0,0 +1,120 @@
+package agent
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+	"github.com/point-unknown/catalyst/pkg/vector"
+)
+
+const (
+	minChunkTokens = 32 // Smallest useful remainder of a chunk; smaller ones are dropped
+	truncatedMark  = "\n…(truncated)"
+)
+
+// retrievedContext is the retrieval block of a prompt and the sources it contains.
+type retrievedContext struct {
+	Block   string
+	Sources []domain.ChatCitation
+}
+
+// buildRetrievedContext formats search results as a numbered block of at most budget
+// (estimated) tokens, best score first. A chunk that does not fit is truncated; once
+// fewer than minChunkTokens remain, the rest is dropped.
+func buildRetrievedContext(results []vector.SearchResult, budget int, minScore float32) retrievedContext {
+	var (
+		ctx  retrievedContext
+		body strings.Builder
+	)
+	header := "## Retrieved code (cite as [n])\n"
+	remaining := budget - domain.EstimateTokens(header)
+
+	for _, r := range results {
+		if r.Score < minScore {
+			continue
+		}
+		index := len(ctx.Sources) + 1
+		path := documentPath(r.Document)
+		head := fmt.Sprintf("[%d] %s (score %.2f)\n```\n", index, path, r.Score)
+		tail := "\n```\n"
+
+		room := remaining - domain.EstimateTokens(head+tail)
+		if room < minChunkTokens {
+			break
+		}
+		content := r.Content
+		if domain.EstimateTokens(content) > room {
+			content = truncateRunes(content, (room-domain.EstimateTokens(truncatedMark))*4) + truncatedMark
+		}
+
+		chunk := head + content + tail
+		body.WriteString(chunk)
+		remaining -= domain.EstimateTokens(chunk)
+		ctx.Sources = append(ctx.Sources, domain.ChatCitation{Index: index, Path: path, Score: r.Score})
+	}
+
+	if len(ctx.Sources) > 0 {
+		ctx.Block = header + body.String()
+	}
+	return ctx
+}
+
+// documentPath is the file path of an indexed document (the repo indexer uses the path as ID).
+func documentPath(doc vector.Document) string {
+	if p, ok := doc.Metadata["path"].(string); ok && p != "" {
+		return p
+	}
+	return doc.ID
+}
+
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
+
+var citationRef = regexp.MustCompile(`\[(\d+)\]`)
+
+// citeSources returns the sources a reply refers to as [n], or all sources if it
+// refers to none of them.
+func citeSources(reply string, sources []domain.ChatCitation) []domain.ChatCitation {
+	if len(sources) == 0 {
+		return nil
+	}
+	referenced := make(map[int]bool)
+	for _, m := range citationRef.FindAllStringSubmatch(reply, -1) {
+		if n, err := strconv.Atoi(m[1]); err == nil {
+			referenced[n] = true
+		}
+	}
+
+	var cited []domain.ChatCitation
+	for _, s := range sources {
+		if referenced[s.Index] {
+			cited = append(cited, s)
+		}
+	}
+	if len(cited) == 0 {
+		return sources
+	}
+	return cited
+}
+
+// withSources appends a human-readable source list to a reply.
+func withSources(reply string, cited []domain.ChatCitation) string {
+	if len(cited) == 0 {
+		return reply
+	}
+	var b strings.Builder
+	b.WriteString(reply)
+	b.WriteString("\n\nSources:")
+	for _, c := range cited {
+		fmt.Fprintf(&b, "\n[%d] %s (score %.2f)", c.Index, c.Path, c.Score)
+	}
+	return b.String()
+}
@@ -0,0 +1,233 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+	"github.com/point-unknown/catalyst/pkg/mcp"
+	"github.com/point-unknown/catalyst/pkg/vector"
+)
+
+// scriptedLLM answers every prompt with a fixed response and records the prompts.
+type scriptedLLM struct {
+	response string
+	err      error
+	prompts  []string
+}
+
+func (l *scriptedLLM) GenerateCode(_ context.Context, prompt string) (string, error) {
+	l.prompts = append(l.prompts, prompt)
+	return l.response, l.err
+}
+
+func (l *scriptedLLM) CheckHealth(context.Context) error { return nil }
+
+func (l *scriptedLLM) Embed(context.Context, string) ([]float32, error) { return []float32{1}, nil }
+
+// fixedMemories is a vector.Store that always returns the same results.
+type fixedMemories struct{ results []vector.SearchResult }
+
+func (m *fixedMemories) Init(interface{}) error                      { return nil }
+func (m *fixedMemories) Upsert(interface{}, []vector.Document) error { return nil }
+func (m *fixedMemories) Delete(interface{}, []string) error          { return nil }
+func (m *fixedMemories) Search(interface{}, vector.Embedding, int) ([]vector.SearchResult, error) {
+	return m.results, nil
+}
+
+func newTestLiaison(llm domain.LLMProvider, vec vector.Store) *LiaisonAgent {
+	reg := mcp.NewLocalRegistry()
+	reg.Register(mcp.Tool{
+		Name:        "git_create_issue",
+		Description: "Create a new issue in the repository",
+		Parameters:  json.RawMessage(`{"type": "object", "properties": {"title": {"type": "string"}, "body": {"type": "string"}}, "required": ["title", "body"]}`),
+	})
+	return NewLiaisonAgent("Liaison", llm, reg, vec, DefaultLiaisonConfig)
+}
+
+func wakeEvent(t *testing.T, input string) domain.CloudEvent {
+	t.Helper()
+	evt, err := domain.NewEvent("chat-manager", domain.ChatWakeEventType, domain.ChatWake{
+		TargetAgent: "Liaison",
+		UserInput:   input,
+		History:     []domain.ChatMessage{{Sender: "user", Content: input}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return evt
+}
+
+func chatContent(t *testing.T, evt *domain.CloudEvent) string {
+	t.Helper()
+	if evt == nil || evt.Type != domain.ChatMessageEventType {
+		t.Fatalf("expected a chat.message, got %+v", evt)
+	}
+	var msg domain.ChatMessage
+	if err := json.Unmarshal(evt.Data, &msg); err != nil {
+		t.Fatal(err)
+	}
+	return msg.Content
+}
+
+func TestLiaison_ToolCall(t *testing.T) {
+	llm := &scriptedLLM{response: "Sure!\n```json\n" +
+		`{"action": "tool", "tool": "git_create_issue", "arguments": {"title": "Refactor login", "body": "Split the handler"}}` +
+		"\n```"}
+	memories := &fixedMemories{results: []vector.SearchResult{{Document: vector.Document{ID: "auth/login.go", Content: "func Login()"}, Score: 0.9}}}
+	a := newTestLiaison(llm, memories)
+
+	out, err := a.Execute(context.Background(), wakeEvent(t, "Please open an issue to refactor login"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out == nil || out.Type != domain.ToolCallEventType {
+		t.Fatalf("expected a tool.call, got %+v", out)
+	}
+	var call domain.ChatToolCall
+	if err := json.Unmarshal(out.Data, &call); err != nil {
+		t.Fatal(err)
+	}
+	if call.ToolName != "git_create_issue" || call.Arguments["title"] != "Refactor login" || call.ID == "" {
+		t.Errorf("unexpected tool call: %+v", call)
+	}
+	// Without a note from the LLM, the chat is still told what is being done
+	if call.Reply != "Running git_create_issue." {
+		t.Errorf("expected a chat reply for the tool call, got %q", call.Reply)
+	}
+
+	// The prompt carries the tools, the memories and the conversation
+	prompt := llm.prompts[0]
+	for _, want := range []string{"git_create_issue", "auth/login.go", "user: Please open an issue"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt is missing %q:\n%s", want, prompt)
+		}
+	}
+}
+
+func TestLiaison_Fallbacks(t *testing.T) {
+	cases := []struct {
+		name     string
+		llm      *scriptedLLM
+		contains string
+	}{
+		{"plain reply", &scriptedLLM{response: `{"action": "reply", "reply": "Hello there"}`}, "Hello there"},
+		{"free text", &scriptedLLM{response: "Just some prose."}, "Just some prose."},
+		{"unknown tool", &scriptedLLM{response: `{"action": "tool", "tool": "rm_rf", "arguments": {}}`}, "unknown tool"},
+		{"invalid arguments", &scriptedLLM{response: `{"action": "tool", "tool": "git_create_issue", "arguments": {"title": 7}}`}, "missing required"},
+		{"llm down", &scriptedLLM{err: errors.New("connection refused")}, "language model unavailable"},
+	}
+	for _, c := range cases {
+		out, err := newTestLiaison(c.llm, nil).Execute(context.Background(), wakeEvent(t, "hi"))
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		if got := chatContent(t, out); !strings.Contains(got, c.contains) {
+			t.Errorf("%s: expected reply containing %q, got %q", c.name, c.contains, got)
+		}
+	}
+}
+
+func TestLiaison_IgnoresOtherEvents(t *testing.T) {
+	llm := &scriptedLLM{}
+	out, err := newTestLiaison(llm, nil).Execute(context.Background(), domain.CloudEvent{Type: "sensor.cpu.temp"})
+	if out != nil || err != nil || len(llm.prompts) != 0 {
+		t.Errorf("expected the event to be ignored, got %v, %v", out, err)
+	}
+
+	// A wake for another agent of the group chat
+	other, err := domain.NewEvent("chat-manager", domain.ChatWakeEventType, domain.ChatWake{TargetAgent: "SystemArchitect", UserInput: "hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err = newTestLiaison(llm, nil).Execute(context.Background(), other)
+	if out != nil || err != nil || len(llm.prompts) != 0 {
+		t.Errorf("expected another agent's wake to be ignored, got %v, %v", out, err)
+	}
+}
+
+func TestLiaison_CitesRetrievedCode(t *testing.T) {
+	llm := &scriptedLLM{response: `{"action": "reply", "reply": "Login lives in the auth package [2]."}`}
+	memories := &fixedMemories{results: []vector.SearchResult{
+		{Document: vector.Document{ID: "README.md", Content: "# Catalyst"}, Score: 0.91},
+		{Document: vector.Document{ID: "auth/login.go", Content: "func Login() {}"}, Score: 0.87},
+	}}
+
+	out, err := newTestLiaison(llm, memories).Execute(context.Background(), wakeEvent(t, "Where is login?"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msg domain.ChatMessage
+	if err := json.Unmarshal(out.Data, &msg); err != nil {
+		t.Fatal(err)
+	}
+	if len(msg.Citations) != 1 || msg.Citations[0].Path != "auth/login.go" || msg.Citations[0].Index != 2 {
+		t.Errorf("expected only the referenced source to be cited, got %+v", msg.Citations)
+	}
+	if !strings.Contains(msg.Content, "Sources:\n[2] auth/login.go (score 0.87)") {
+		t.Errorf("expected a source list in the reply, got %q", msg.Content)
+	}
+	if !strings.Contains(llm.prompts[0], "[1] README.md (score 0.91)") {
+		t.Errorf("expected numbered sources in the prompt:\n%s", llm.prompts[0])
+	}
+}
+
+func TestLiaison_SessionAndSummary(t *testing.T) {
+	llm := &scriptedLLM{response: `{"action": "reply", "reply": "Still auth.go."}`}
+	wake, err := domain.NewEvent("chat-manager", domain.ChatWakeEventType, domain.ChatWake{
+		Session:     "s1",
+		TargetAgent: "Liaison",
+		UserInput:   "And logout?",
+		Summary:     "The user asked where login is; it is in auth.go.",
+		History:     []domain.ChatMessage{{Sender: "user", Content: "And logout?"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := newTestLiaison(llm, nil).Execute(context.Background(), wake)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msg domain.ChatMessage
+	if err := json.Unmarshal(out.Data, &msg); err != nil {
+		t.Fatal(err)
+	}
+	if msg.Session != "s1" {
+		t.Errorf("reply session = %q, want s1", msg.Session)
+	}
+	if !strings.Contains(llm.prompts[0], "(summary)\nThe user asked where login is") {
+		t.Errorf("expected the summary in the prompt:\n%s", llm.prompts[0])
+	}
+}
+
+func TestBuildRetrievedContext_Budget(t *testing.T) {
+	long := strings.Repeat("x", 4000) // ~1000 tokens
+	results := []vector.SearchResult{
+		{Document: vector.Document{ID: "a.go", Content: long}, Score: 0.9},
+		{Document: vector.Document{ID: "b.go", Content: long}, Score: 0.8},
+		{Document: vector.Document{ID: "c.go", Content: long, Metadata: map[string]interface{}{"path": "pkg/c.go"}}, Score: 0.1},
+	}
+
+	ctx := buildRetrievedContext(results, 1500, 0.5)
+	if got := domain.EstimateTokens(ctx.Block); got > 1500 {
+		t.Errorf("block uses %d tokens, budget is 1500", got)
+	}
+	if len(ctx.Sources) != 2 || ctx.Sources[1].Path != "b.go" {
+		t.Fatalf("expected a.go and a truncated b.go, got %+v", ctx.Sources)
+	}
+	if !strings.Contains(ctx.Block, "(truncated)") {
+		t.Error("expected the second chunk to be truncated")
+	}
+
+	if ctx := buildRetrievedContext(results[2:], 1500, 0); ctx.Sources[0].Path != "pkg/c.go" {
+		t.Errorf("expected the metadata path, got %+v", ctx.Sources)
+	}
+	if ctx := buildRetrievedContext(nil, 1500, 0); ctx.Block != "" {
+		t.Errorf("expected no block without results, got %q", ctx.Block)
+	}
+}
@@ -0,0 +1,67 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/datacraft/catalyst/core/internal/adapter/workspace"
+	"github.com/datacraft/catalyst/core/internal/domain"
+)
+
+// TrendScoutConfig is the `config:` block of a trend-scout agent.
+type TrendScoutConfig struct {
+	Threshold float64 `yaml:"threshold"` // Moving average that triggers an anomaly
+}
+
+func (c *TrendScoutConfig) Validate() error {
+	if c.Threshold <= 0 {
+		return fmt.Errorf("threshold must be positive, got %v", c.Threshold)
+	}
+	return nil
+}
+
+// errNoState is returned when building a stateful agent type without AgentDeps.State.
+var errNoState = errors.New("agent state is not configured")
+
+// Built-in agent types. Custom types register the same way from their own package.
+func init() {
+	domain.RegisterAgentType(domain.NewAgentType("trend-scout", TrendScoutConfig{Threshold: 80.0},
+		func(cfg domain.AgentConfig, c TrendScoutConfig, deps domain.AgentDeps) (domain.Agent, error) {
+			if deps.State == nil {
+				return nil, errNoState
+			}
+			return NewTrendScout(cfg.ID, c.Threshold, deps.State.For(cfg.ID)), nil
+		}))
+
+	domain.RegisterAgentType(domain.NewAgentType("anomaly-detector",
+		AnomalyDetectorConfig{Types: []string{"sensor.*"}, Window: 20, AlertAfter: 1, ClearAfter: 3, Severity: "warning"},
+		func(cfg domain.AgentConfig, c AnomalyDetectorConfig, deps domain.AgentDeps) (domain.Agent, error) {
+			if deps.State == nil {
+				return nil, errNoState
+			}
+			return NewAnomalyDetector(cfg.ID, c, deps.State.For(cfg.ID)), nil
+		}))
+
+	domain.RegisterAgentType(domain.NewAgentType("console-reporter", domain.NoConfig{},
+		func(cfg domain.AgentConfig, _ domain.NoConfig, deps domain.AgentDeps) (domain.Agent, error) {
+			return NewConsoleReporter(cfg.ID), nil
+		}))
+
+	domain.RegisterAgentType(domain.NewAgentType("engineer", domain.NoConfig{},
+		func(cfg domain.AgentConfig, _ domain.NoConfig, deps domain.AgentDeps) (domain.Agent, error) {
+			// Create Secure Workspace with Resolver (GitGuard)
+			ws := workspace.NewLocalWorkspace(cfg.Security, deps.Resolver)
+			return NewEngineerAgent(cfg.ID, deps.LLM, ws, cfg.Security), nil
+		}))
+
+	domain.RegisterAgentType(domain.NewAgentType("liaison", DefaultLiaisonConfig,
+		func(cfg domain.AgentConfig, c LiaisonConfig, deps domain.AgentDeps) (domain.Agent, error) {
+			// Inject Vector Store into Liaison
+			return NewLiaisonAgent(cfg.ID, deps.LLM, deps.Tools, deps.Vectors, c), nil
+		}))
+
+	domain.RegisterAgentType(domain.NewAgentType("exec", ExecConfig{},
+		func(cfg domain.AgentConfig, c ExecConfig, deps domain.AgentDeps) (domain.Agent, error) {
+			return NewExecAgent(cfg.ID, c), nil
+		}))
+}
@@ -13,18 +13,25 @@ import (
 
 // TrendScout is a "Communicator" agent (Level 2).
 // It analyzes sensor data streams to find patterns and signal anomalies.
+// Each event source keeps its own moving window in the agent state, keyed by source.
 type TrendScout struct {
 	id         string
 	threshold  float64
-	readings   []float64 // Moving window
+	state      domain.AgentState
 	windowSize int
 }
 
-func NewTrendScout(id string, threshold float64) *TrendScout {
+// trendWindow is the persisted state of one event source.
+type trendWindow struct {
+	Readings []float64 `json:"readings"` // Moving window, oldest first
+}
+
+// NewTrendScout creates a TrendScout that keeps its windows in st.
+func NewTrendScout(id string, threshold float64, st domain.AgentState) *TrendScout {
 	return &TrendScout{
 		id:         id,
 		threshold:  threshold,
-		readings:   make([]float64, 0),
+		state:      st,
 		windowSize: 5,
 	}
 }
@@ -70,20 +77,26 @@ func (a *TrendScout) Execute(ctx context.Context, input domain.CloudEvent) (*dom
 		}
 	}
 
-	// 2. Logic: Moving Average & Threshold
-	a.readings = append(a.readings, data.Value)
-	if len(a.readings) > a.windowSize {
-		a.readings = a.readings[1:]
-	}
+	// 2. Logic: Moving Average (per source) & Threshold
+	var avg float64
+	err := domain.UpdateState(ctx, a.state, input.Source, func(w *trendWindow) error {
+		w.Readings = append(w.Readings, data.Value)
+		if len(w.Readings) > a.windowSize {
+			w.Readings = w.Readings[len(w.Readings)-a.windowSize:]
+		}
 
-	// Calculate Avg
-	sum := 0.0
-	for _, v := range a.readings {
-		sum += v
+		sum := 0.0
+		for _, v := range w.Readings {
+			sum += v
+		}
+		avg = sum / float64(len(w.Readings))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update window for %q: %w", input.Source, err)
 	}
-	avg := sum / float64(len(a.readings))
 
-	log.Printf("  >>> [AGENT:%s] Analysis: Current=%.1f Avg=%.1f (Threshold=%.1f)", a.id, data.Value, avg, a.threshold)
+	log.Printf("  >>> [AGENT:%s] Analysis (%s): Current=%.1f Avg=%.1f (Threshold=%.1f)", a.id, input.Source, data.Value, avg, a.threshold)
 
 	// 3. Output: Signal if Overheat
 	if avg > a.threshold {
@@ -93,9 +106,10 @@ func (a *TrendScout) Execute(ctx context.Context, input domain.CloudEvent) (*dom
 			"severity": "critical",
 			"message":  fmt.Sprintf("CPU High Temp detected: %.1f C (Avg %.1f)", data.Value, avg),
 			"source":   input.Source,
+			"metric":   input.Type,
 		}
 
-		evt, err := domain.NewEvent("agent.trend_scout", "swarm.security.alert", signalData)
+		evt, err := domain.NewEvent("agent.trend_scout", domain.SecurityAlertEventType, signalData)
 		if err != nil {
 			return nil, err
 		}
@@ -3,15 +3,46 @@ package agent
 import (
 	"context"
 	"encoding/json"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/datacraft/catalyst/core/internal/domain"
 )
 
+// memoryState is an in-memory domain.AgentState.
+type memoryState struct {
+	mu     sync.Mutex
+	values map[string]json.RawMessage
+}
+
+func (s *memoryState) Get(ctx context.Context, key string) (json.RawMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.values[key], nil
+}
+
+func (s *memoryState) Update(ctx context.Context, key string, fn func(current json.RawMessage) (json.RawMessage, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	next, err := fn(s.values[key])
+	if err != nil {
+		return err
+	}
+	if s.values == nil {
+		s.values = make(map[string]json.RawMessage)
+	}
+	s.values[key] = next
+	return nil
+}
+
+type memoryStates struct{}
+
+func (memoryStates) For(agentID string) domain.AgentState { return &memoryState{} }
+
 func TestTrendScout_Execute(t *testing.T) {
 	// Setup
-	agent := NewTrendScout("TestScout", 80.0)
+	agent := NewTrendScout("TestScout", 80.0, &memoryState{})
 	ctx := context.Background()
 
 	// Scenario 1: Ignore non-matching event
@@ -60,3 +91,43 @@ func TestTrendScout_Execute(t *testing.T) {
 		t.Errorf("Expected signal type 'swarm.security.alert', got %s", out.Type)
 	}
 }
+
+func TestTrendScout_WindowPerSource(t *testing.T) {
+	agent := NewTrendScout("TestScout", 80.0, &memoryState{})
+	ctx := context.Background()
+
+	reading := func(source string, value float64) *domain.CloudEvent {
+		data, _ := json.Marshal(map[string]interface{}{"value": value, "unit": "C"})
+		out, err := agent.Execute(ctx, domain.CloudEvent{Source: source, Type: "sensor.cpu.temp", Data: data, Time: time.Now()})
+		if err != nil {
+			t.Fatalf("Execution failed: %v", err)
+		}
+		return out
+	}
+
+	// A hot source must not raise the average of a cool one
+	if out := reading("sensor/hot", 1000.0); out == nil {
+		t.Fatal("Expected Alert for the hot source")
+	}
+	if out := reading("sensor/cool", 50.0); out != nil {
+		t.Errorf("Expected no alert for the cool source, got %s", out.Type)
+	}
+}
+
+func TestTrendScout_ConcurrentExecute(t *testing.T) {
+	agent := NewTrendScout("TestScout", 80.0, &memoryState{})
+	ctx := context.Background()
+	data, _ := json.Marshal(map[string]interface{}{"value": 50.0, "unit": "C"})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := agent.Execute(ctx, domain.CloudEvent{Source: "sensor/a", Type: "sensor.cpu.temp", Data: data}); err != nil {
+				t.Errorf("Execution failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
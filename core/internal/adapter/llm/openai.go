@@ -11,23 +11,27 @@ import (
 	"net/url"
 	"strings"
 	"time"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
 )
 
 // OpenAIAdapter implements domain.LLMProvider for OpenAI-compatible APIs (Ollama, vLLM, etc.)
 type OpenAIAdapter struct {
-	endpoint    string
-	model       string
-	apiKey      string
-	client      *http.Client
-	privateMode bool
+	endpoint        string
+	model           string
+	apiKey          string
+	client          *http.Client
+	privateMode     bool
+	maxPromptTokens int
 }
 
 // Config holds the configuration for the adapter
 type Config struct {
-	Endpoint    string
-	Model       string
-	APIKey      string
-	PrivateMode bool
+	Endpoint        string
+	Model           string
+	APIKey          string
+	PrivateMode     bool
+	MaxPromptTokens int // Prompts estimated larger are rejected with domain.ErrPromptTooLong (0: no limit)
 }
 
 // Msg represents a chat message
@@ -62,11 +66,12 @@ func NewOpenAIAdapter(cfg Config) (*OpenAIAdapter, error) {
 	}
 
 	return &OpenAIAdapter{
-		endpoint:    strings.TrimSuffix(cfg.Endpoint, "/"),
-		model:       cfg.Model,
-		apiKey:      cfg.APIKey,
-		privateMode: cfg.PrivateMode,
-		client:      &http.Client{Timeout: 60 * time.Second},
+		endpoint:        strings.TrimSuffix(cfg.Endpoint, "/"),
+		model:           cfg.Model,
+		apiKey:          cfg.APIKey,
+		privateMode:     cfg.PrivateMode,
+		maxPromptTokens: cfg.MaxPromptTokens,
+		client:          &http.Client{Timeout: 60 * time.Second},
 	}, nil
 }
 
@@ -83,6 +88,17 @@ func (a *OpenAIAdapter) GenerateCode(ctx context.Context, prompt string) (string
 		Stream: false,
 	}
 
+	// Fail fast instead of letting the server truncate the prompt
+	if a.maxPromptTokens > 0 {
+		tokens := 0
+		for _, m := range payload.Messages {
+			tokens += domain.EstimateTokens(m.Content)
+		}
+		if tokens > a.maxPromptTokens {
+			return "", fmt.Errorf("%w: about %d tokens, limit %d", domain.ErrPromptTooLong, tokens, a.maxPromptTokens)
+		}
+	}
+
 	jsonBytes, err := json.Marshal(payload)
 	if err != nil {
 		return "", err
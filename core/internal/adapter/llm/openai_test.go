@@ -0,0 +1,37 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+)
+
+func TestGenerateCode_PromptLimit(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"choices": [{"message": {"content": "ok"}}]}`))
+	}))
+	defer srv.Close()
+
+	a, err := NewOpenAIAdapter(Config{Endpoint: srv.URL, PrivateMode: true, MaxPromptTokens: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if out, err := a.GenerateCode(context.Background(), "short prompt"); err != nil || out != "ok" {
+		t.Fatalf("got %q, %v", out, err)
+	}
+	_, err = a.GenerateCode(context.Background(), strings.Repeat("word ", 200))
+	if !errors.Is(err, domain.ErrPromptTooLong) {
+		t.Errorf("expected ErrPromptTooLong, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("the oversized prompt reached the server (%d calls)", calls)
+	}
+}
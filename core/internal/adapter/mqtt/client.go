@@ -41,15 +41,16 @@ func (a *Adapter) Publish(topic string, event domain.CloudEvent) error {
 	return token.Error()
 }
 
-// Subscribe listens for messages on a topic and triggers the handler
-func (a *Adapter) Subscribe(topic string, handler func(domain.CloudEvent)) error {
+// Subscribe listens for messages on a topic filter and triggers the handler
+// with the concrete topic each message arrived on.
+func (a *Adapter) Subscribe(topic string, handler func(topic string, event domain.CloudEvent)) error {
 	cb := func(client mqtt.Client, msg mqtt.Message) {
 		var event domain.CloudEvent
 		if err := json.Unmarshal(msg.Payload(), &event); err != nil {
 			fmt.Printf("Error unmarshalling event on %s: %v\n", topic, err)
 			return
 		}
-		handler(event)
+		handler(msg.Topic(), event)
 	}
 
 	token := a.client.Subscribe(topic, 0, cb)
@@ -0,0 +1,36 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// LoadAgentState implements domain.AgentStateStore.
+func (s *PostgresStore) LoadAgentState(ctx context.Context, agentID, key string) (json.RawMessage, error) {
+	var value []byte
+	err := s.pool.QueryRow(ctx, `SELECT value FROM agent_state WHERE agent_id = $1 AND key = $2`, agentID, key).Scan(&value)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load state %s/%s: %w", agentID, key, err)
+	}
+	return value, nil
+}
+
+// SaveAgentState implements domain.AgentStateStore.
+func (s *PostgresStore) SaveAgentState(ctx context.Context, agentID, key string, value json.RawMessage) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO agent_state (agent_id, key, value, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (agent_id, key) DO UPDATE SET value = EXCLUDED.value, updated_at = NOW()`,
+		agentID, key, []byte(value))
+	if err != nil {
+		return fmt.Errorf("failed to save state %s/%s: %w", agentID, key, err)
+	}
+	return nil
+}
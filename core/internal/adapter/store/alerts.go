@@ -0,0 +1,143 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+const alertColumns = `id, fingerprint, grp, status, severity, agent, source, metric, event_type, message,
+	count, first_seen, last_seen, acked_by, acked_at, resolved_at`
+
+// ActiveAlert implements domain.AlertStore.
+func (s *PostgresStore) ActiveAlert(ctx context.Context, fingerprint string) (*domain.Alert, error) {
+	row := s.pool.QueryRow(ctx, `SELECT `+alertColumns+` FROM alerts WHERE fingerprint = $1 AND status <> $2`,
+		fingerprint, domain.AlertStatusResolved)
+	alert, err := scanAlert(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query active alert: %w", err)
+	}
+	return alert, nil
+}
+
+// SaveAlert implements domain.AlertStore.
+func (s *PostgresStore) SaveAlert(ctx context.Context, a domain.Alert) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO alerts (`+alertColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		ON CONFLICT (id) DO UPDATE SET
+			status = EXCLUDED.status, severity = EXCLUDED.severity, message = EXCLUDED.message,
+			count = EXCLUDED.count, last_seen = EXCLUDED.last_seen, acked_by = EXCLUDED.acked_by,
+			acked_at = EXCLUDED.acked_at, resolved_at = EXCLUDED.resolved_at`,
+		a.ID, a.Fingerprint, a.Group, a.Status, a.Severity, a.Agent, a.Source, a.Metric, a.EventType, a.Message,
+		a.Count, a.FirstSeen, a.LastSeen, a.AckedBy, a.AckedAt, a.ResolvedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save alert %s: %w", a.ID, err)
+	}
+	return nil
+}
+
+// GetAlert implements domain.AlertStore.
+func (s *PostgresStore) GetAlert(ctx context.Context, id string) (*domain.Alert, error) {
+	alert, err := scanAlert(s.pool.QueryRow(ctx, `SELECT `+alertColumns+` FROM alerts WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrAlertNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alert: %w", err)
+	}
+	return alert, nil
+}
+
+// ListAlerts implements domain.AlertStore.
+func (s *PostgresStore) ListAlerts(ctx context.Context, status string, limit int) ([]domain.Alert, error) {
+	query := `SELECT ` + alertColumns + ` FROM alerts WHERE ($1 = '' OR status = $1) ORDER BY last_seen DESC`
+	args := []interface{}{status}
+	if limit > 0 {
+		query += ` LIMIT $2`
+		args = append(args, limit)
+	}
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alerts: %w", err)
+	}
+	defer rows.Close()
+
+	alerts := make([]domain.Alert, 0)
+	for rows.Next() {
+		alert, err := scanAlert(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan alert: %w", err)
+		}
+		alerts = append(alerts, *alert)
+	}
+	return alerts, rows.Err()
+}
+
+func scanAlert(row pgx.Row) (*domain.Alert, error) {
+	var a domain.Alert
+	err := row.Scan(&a.ID, &a.Fingerprint, &a.Group, &a.Status, &a.Severity, &a.Agent, &a.Source, &a.Metric,
+		&a.EventType, &a.Message, &a.Count, &a.FirstSeen, &a.LastSeen, &a.AckedBy, &a.AckedAt, &a.ResolvedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// SaveSilence implements domain.AlertStore.
+func (s *PostgresStore) SaveSilence(ctx context.Context, sil domain.Silence) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO alert_silences (id, fingerprint, grp, agent, source, metric, severity, comment, created_by, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		sil.ID, sil.Fingerprint, sil.Group, sil.Agent, sil.Source, sil.Metric, sil.Severity,
+		sil.Comment, sil.CreatedBy, sil.CreatedAt, sil.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to save silence: %w", err)
+	}
+	return nil
+}
+
+// DeleteSilence implements domain.AlertStore.
+func (s *PostgresStore) DeleteSilence(ctx context.Context, id string) error {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM alert_silences WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete silence: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrSilenceNotFound
+	}
+	return nil
+}
+
+// ListSilences implements domain.AlertStore. Expired silences are pruned on the way.
+func (s *PostgresStore) ListSilences(ctx context.Context, now time.Time) ([]domain.Silence, error) {
+	if _, err := s.pool.Exec(ctx, `DELETE FROM alert_silences WHERE expires_at <= $1`, now); err != nil {
+		return nil, fmt.Errorf("failed to prune silences: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, fingerprint, grp, agent, source, metric, severity, comment, created_by, created_at, expires_at
+		FROM alert_silences ORDER BY expires_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query silences: %w", err)
+	}
+	defer rows.Close()
+
+	silences := make([]domain.Silence, 0)
+	for rows.Next() {
+		var sil domain.Silence
+		if err := rows.Scan(&sil.ID, &sil.Fingerprint, &sil.Group, &sil.Agent, &sil.Source, &sil.Metric, &sil.Severity,
+			&sil.Comment, &sil.CreatedBy, &sil.CreatedAt, &sil.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("failed to scan silence: %w", err)
+		}
+		silences = append(silences, sil)
+	}
+	return silences, rows.Err()
+}
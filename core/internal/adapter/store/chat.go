@@ -0,0 +1,120 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+const chatSessionColumns = `s.id, s.title, s.created_at, s.updated_at, s.summary, s.summarized,
+	(SELECT count(*) FROM chat_messages m WHERE m.session_id = s.id)`
+
+// SaveChatSession implements domain.ChatStore.
+func (s *PostgresStore) SaveChatSession(ctx context.Context, cs domain.ChatSession) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO chat_sessions (id, title, created_at, updated_at, summary, summarized) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (id) DO UPDATE SET title = EXCLUDED.title, updated_at = EXCLUDED.updated_at,
+			summary = EXCLUDED.summary, summarized = EXCLUDED.summarized`,
+		cs.ID, cs.Title, cs.CreatedAt, cs.UpdatedAt, cs.Summary, cs.Summarized)
+	if err != nil {
+		return fmt.Errorf("failed to save chat session %s: %w", cs.ID, err)
+	}
+	return nil
+}
+
+// GetChatSession implements domain.ChatStore.
+func (s *PostgresStore) GetChatSession(ctx context.Context, id string) (*domain.ChatSession, error) {
+	var cs domain.ChatSession
+	err := s.pool.QueryRow(ctx, `SELECT `+chatSessionColumns+` FROM chat_sessions s WHERE s.id = $1`, id).
+		Scan(&cs.ID, &cs.Title, &cs.CreatedAt, &cs.UpdatedAt, &cs.Summary, &cs.Summarized, &cs.MessageCount)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrChatSessionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chat session: %w", err)
+	}
+	return &cs, nil
+}
+
+// ListChatSessions implements domain.ChatStore.
+func (s *PostgresStore) ListChatSessions(ctx context.Context, limit int) ([]domain.ChatSession, error) {
+	query := `SELECT ` + chatSessionColumns + ` FROM chat_sessions s ORDER BY s.updated_at DESC`
+	var args []interface{}
+	if limit > 0 {
+		query += ` LIMIT $1`
+		args = append(args, limit)
+	}
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chat sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := make([]domain.ChatSession, 0)
+	for rows.Next() {
+		var cs domain.ChatSession
+		if err := rows.Scan(&cs.ID, &cs.Title, &cs.CreatedAt, &cs.UpdatedAt, &cs.Summary, &cs.Summarized, &cs.MessageCount); err != nil {
+			return nil, fmt.Errorf("failed to scan chat session: %w", err)
+		}
+		sessions = append(sessions, cs)
+	}
+	return sessions, rows.Err()
+}
+
+// AppendChatMessage implements domain.ChatStore.
+func (s *PostgresStore) AppendChatMessage(ctx context.Context, msg domain.ChatMessage) error {
+	citations, err := json.Marshal(msg.Citations)
+	if err != nil {
+		return fmt.Errorf("failed to marshal citations: %w", err)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO chat_messages (session_id, sender, content, citations, created_at) VALUES ($1, $2, $3, $4, $5)`,
+		msg.Session, msg.Sender, msg.Content, citations, msg.Time); err != nil {
+		return fmt.Errorf("failed to insert chat message: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `UPDATE chat_sessions SET updated_at = GREATEST(updated_at, $2) WHERE id = $1`,
+		msg.Session, msg.Time); err != nil {
+		return fmt.Errorf("failed to touch chat session: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+// ListChatMessages implements domain.ChatStore.
+func (s *PostgresStore) ListChatMessages(ctx context.Context, sessionID string) ([]domain.ChatMessage, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT session_id, sender, content, citations, created_at
+		FROM chat_messages WHERE session_id = $1 ORDER BY id`, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chat messages: %w", err)
+	}
+	defer rows.Close()
+
+	messages := make([]domain.ChatMessage, 0)
+	for rows.Next() {
+		var (
+			msg       domain.ChatMessage
+			citations []byte
+		)
+		if err := rows.Scan(&msg.Session, &msg.Sender, &msg.Content, &citations, &msg.Time); err != nil {
+			return nil, fmt.Errorf("failed to scan chat message: %w", err)
+		}
+		if len(citations) > 0 {
+			if err := json.Unmarshal(citations, &msg.Citations); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal citations: %w", err)
+			}
+		}
+		messages = append(messages, msg)
+	}
+	return messages, rows.Err()
+}
@@ -0,0 +1,126 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// maxQueueAttempts bounds how often an abandoned run is handed out again before it is marked failed.
+const maxQueueAttempts = 5
+
+// Enqueue implements domain.MissionQueue.
+func (s *PostgresStore) Enqueue(ctx context.Context, missionID string, trigger domain.CloudEvent, maxPending int) (bool, error) {
+	payload, err := json.Marshal(trigger)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal trigger: %w", err)
+	}
+	triggerID := trigger.ID
+	if triggerID == "" {
+		triggerID = uuid.NewString()
+	}
+
+	// The trigger ID de-duplicates the same MQTT message received by several replicas.
+	var exists, full bool
+	err = s.pool.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM mission_queue WHERE mission_id = $1 AND trigger_id = $2),
+		       (SELECT COUNT(*) FROM mission_queue WHERE mission_id = $1 AND status = 'pending') >= $3`,
+		missionID, triggerID, maxPending).Scan(&exists, &full)
+	if err != nil {
+		return false, fmt.Errorf("failed to check queue depth: %w", err)
+	}
+	if exists {
+		return false, nil
+	}
+	if full {
+		return false, domain.ErrQueueFull
+	}
+
+	tag, err := s.pool.Exec(ctx, `
+		INSERT INTO mission_queue (id, mission_id, trigger_id, trigger)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (mission_id, trigger_id) DO NOTHING`,
+		uuid.NewString(), missionID, triggerID, payload)
+	if err != nil {
+		return false, fmt.Errorf("failed to enqueue run: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// Claim implements domain.MissionQueue. SKIP LOCKED lets concurrent workers
+// (in this process or other replicas) claim different runs without blocking each other.
+func (s *PostgresStore) Claim(ctx context.Context, missionID string, lease time.Duration) (*domain.MissionJob, error) {
+	// Give up on runs that keep getting abandoned (e.g. they crash the process)
+	_, err := s.pool.Exec(ctx, `
+		UPDATE mission_queue SET status = 'failed', finished_at = NOW(), locked_until = NULL
+		WHERE mission_id = $1 AND status = 'running' AND locked_until < NOW() AND attempts >= $2`,
+		missionID, maxQueueAttempts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expire abandoned runs: %w", err)
+	}
+
+	var job domain.MissionJob
+	var payload []byte
+	err = s.pool.QueryRow(ctx, `
+		UPDATE mission_queue SET status = 'running', attempts = attempts + 1,
+			locked_until = NOW() + make_interval(secs => $2)
+		WHERE id = (
+			SELECT id FROM mission_queue
+			WHERE mission_id = $1
+			  AND (status = 'pending' OR (status = 'running' AND locked_until < NOW()))
+			ORDER BY enqueued_at
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING id, mission_id, trigger, attempts`,
+		missionID, lease.Seconds()).Scan(&job.ID, &job.MissionID, &payload, &job.Attempts)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim run: %w", err)
+	}
+
+	if err := json.Unmarshal(payload, &job.Trigger); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal trigger of run %s: %w", job.ID, err)
+	}
+	return &job, nil
+}
+
+// Extend implements domain.MissionQueue.
+func (s *PostgresStore) Extend(ctx context.Context, jobID string, lease time.Duration) error {
+	_, err := s.pool.Exec(ctx, `
+		UPDATE mission_queue SET locked_until = NOW() + make_interval(secs => $2)
+		WHERE id = $1 AND status = 'running'`, jobID, lease.Seconds())
+	return err
+}
+
+// Complete implements domain.MissionQueue.
+func (s *PostgresStore) Complete(ctx context.Context, jobID string) error {
+	_, err := s.pool.Exec(ctx, `
+		UPDATE mission_queue SET status = 'done', finished_at = NOW(), locked_until = NULL
+		WHERE id = $1`, jobID)
+	return err
+}
+
+// Backlog implements domain.MissionQueue.
+func (s *PostgresStore) Backlog(ctx context.Context, missionID string) (int, error) {
+	var n int
+	err := s.pool.QueryRow(ctx, `SELECT COUNT(*) FROM mission_queue WHERE mission_id = $1 AND status = 'pending'`, missionID).Scan(&n)
+	return n, err
+}
+
+// Prune implements domain.MissionQueue.
+func (s *PostgresStore) Prune(ctx context.Context, missionID string, olderThan time.Duration) error {
+	_, err := s.pool.Exec(ctx, `
+		DELETE FROM mission_queue
+		WHERE mission_id = $1 AND status IN ('done', 'failed') AND finished_at < NOW() - make_interval(secs => $2)`,
+		missionID, olderThan.Seconds())
+	return err
+}
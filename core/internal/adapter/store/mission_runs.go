@@ -0,0 +1,147 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+)
+
+// SaveMissionRun persists a finished mission run and its steps in one transaction.
+func (s *PostgresStore) SaveMissionRun(ctx context.Context, run domain.MissionRun) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO mission_runs (id, mission_id, trigger_event_id, trigger_type, status, error, started_at, finished_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (id) DO UPDATE SET status = EXCLUDED.status, error = EXCLUDED.error, finished_at = EXCLUDED.finished_at`,
+		run.ID, run.MissionID, run.TriggerEventID, run.TriggerType, run.Status, run.Error, run.StartedAt, nullTime(run.FinishedAt))
+	if err != nil {
+		return fmt.Errorf("failed to save mission run: %w", err)
+	}
+
+	for _, step := range run.Steps {
+		input, err := marshalEvent(step.Input)
+		if err != nil {
+			return err
+		}
+		output, err := marshalEvent(step.Output)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO mission_steps (run_id, step_id, agent_id, status, attempts, input, output, error, started_at, duration_ms)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			ON CONFLICT (run_id, step_id) DO UPDATE SET
+				agent_id = EXCLUDED.agent_id, status = EXCLUDED.status, attempts = EXCLUDED.attempts,
+				input = EXCLUDED.input, output = EXCLUDED.output, error = EXCLUDED.error,
+				started_at = EXCLUDED.started_at, duration_ms = EXCLUDED.duration_ms`,
+			run.ID, step.StepID, step.AgentID, step.Status, step.Attempts, input, output, step.Error, nullTime(step.StartedAt), step.DurationMs)
+		if err != nil {
+			return fmt.Errorf("failed to save step %s: %w", step.StepID, err)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ListMissionRuns returns the most recent runs of a mission (newest first), including step records.
+func (s *PostgresStore) ListMissionRuns(ctx context.Context, missionID string, limit int) ([]domain.MissionRun, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, mission_id, trigger_event_id, trigger_type, status, error, started_at, finished_at
+		FROM mission_runs WHERE mission_id = $1
+		ORDER BY started_at DESC LIMIT $2`, missionID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query mission runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := make([]domain.MissionRun, 0)
+	index := make(map[string]int)
+	var ids []string
+	for rows.Next() {
+		var run domain.MissionRun
+		var finished *time.Time
+		if err := rows.Scan(&run.ID, &run.MissionID, &run.TriggerEventID, &run.TriggerType, &run.Status, &run.Error, &run.StartedAt, &finished); err != nil {
+			return nil, fmt.Errorf("failed to scan mission run: %w", err)
+		}
+		if finished != nil {
+			run.FinishedAt = *finished
+		}
+		run.Steps = make([]domain.MissionStepRun, 0)
+		index[run.ID] = len(runs)
+		ids = append(ids, run.ID)
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return runs, nil
+	}
+
+	stepRows, err := s.pool.Query(ctx, `
+		SELECT run_id, step_id, agent_id, status, attempts, input, output, error, started_at, duration_ms
+		FROM mission_steps WHERE run_id = ANY($1)
+		ORDER BY started_at NULLS LAST, step_id`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query mission steps: %w", err)
+	}
+	defer stepRows.Close()
+
+	for stepRows.Next() {
+		var runID string
+		var step domain.MissionStepRun
+		var input, output []byte
+		var started *time.Time
+		if err := stepRows.Scan(&runID, &step.StepID, &step.AgentID, &step.Status, &step.Attempts, &input, &output, &step.Error, &started, &step.DurationMs); err != nil {
+			return nil, fmt.Errorf("failed to scan mission step: %w", err)
+		}
+		if started != nil {
+			step.StartedAt = *started
+		}
+		step.Input = unmarshalEvent(input)
+		step.Output = unmarshalEvent(output)
+
+		i := index[runID]
+		runs[i].Steps = append(runs[i].Steps, step)
+	}
+	return runs, stepRows.Err()
+}
+
+func marshalEvent(event *domain.CloudEvent) ([]byte, error) {
+	if event == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event: %w", err)
+	}
+	return data, nil
+}
+
+func unmarshalEvent(data []byte) *domain.CloudEvent {
+	if len(data) == 0 {
+		return nil
+	}
+	var event domain.CloudEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil
+	}
+	return &event
+}
+
+// nullTime maps the zero time to SQL NULL.
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
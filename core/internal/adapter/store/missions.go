@@ -0,0 +1,218 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+// SeedMissions implements domain.MissionStore. The full mission definition is kept in
+// the config column; name, trigger_topic and enabled are also exposed as columns.
+// Seeded missions are recorded in config_missions, and a mission is only inserted the
+// first time its ID is recorded.
+func (s *PostgresStore) SeedMissions(ctx context.Context, missions []domain.Mission) error {
+	for _, m := range missions {
+		config, err := missionConfig(m)
+		if err != nil {
+			return err
+		}
+		configured, err := json.Marshal(m)
+		if err != nil {
+			return fmt.Errorf("failed to marshal mission %s: %w", m.ID, err)
+		}
+		_, err = s.pool.Exec(ctx, `
+			WITH seed AS (
+				INSERT INTO config_missions (id, config) VALUES ($1, $6::jsonb)
+				ON CONFLICT (id) DO NOTHING
+				RETURNING id
+			)
+			INSERT INTO missions (id, name, trigger_topic, enabled, config)
+			SELECT id, $2::text, $3::text, $4::boolean, $5::jsonb FROM seed
+			ON CONFLICT (id) DO NOTHING`,
+			m.ID, m.Name, m.TriggerTopic, !m.Disabled, config, configured)
+		if err != nil {
+			return fmt.Errorf("failed to seed mission %s: %w", m.ID, err)
+		}
+	}
+	return nil
+}
+
+// ConfiguredMissions implements domain.MissionStore.
+func (s *PostgresStore) ConfiguredMissions(ctx context.Context) ([]domain.Mission, error) {
+	rows, err := s.pool.Query(ctx, `SELECT id, config FROM config_missions ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query configured missions: %w", err)
+	}
+	defer rows.Close()
+
+	missions := make([]domain.Mission, 0)
+	for rows.Next() {
+		var id string
+		var config []byte
+		if err := rows.Scan(&id, &config); err != nil {
+			return nil, fmt.Errorf("failed to scan configured mission: %w", err)
+		}
+		var m domain.Mission
+		if err := json.Unmarshal(config, &m); err != nil {
+			return nil, fmt.Errorf("invalid config for configured mission %s: %w", id, err)
+		}
+		m.ID = id
+		missions = append(missions, m)
+	}
+	return missions, rows.Err()
+}
+
+// SaveConfiguredMission implements domain.MissionStore. The definition is kept as
+// configured, including its disabled flag.
+func (s *PostgresStore) SaveConfiguredMission(ctx context.Context, m domain.Mission) error {
+	config, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("failed to marshal mission %s: %w", m.ID, err)
+	}
+	_, err = s.pool.Exec(ctx, `
+		INSERT INTO config_missions (id, config) VALUES ($1, $2)
+		ON CONFLICT (id) DO UPDATE SET config = EXCLUDED.config`,
+		m.ID, config)
+	if err != nil {
+		return fmt.Errorf("failed to save configured mission %s: %w", m.ID, err)
+	}
+	return nil
+}
+
+// ForgetConfiguredMission implements domain.MissionStore.
+func (s *PostgresStore) ForgetConfiguredMission(ctx context.Context, id string) error {
+	if _, err := s.pool.Exec(ctx, `DELETE FROM config_missions WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to forget configured mission %s: %w", id, err)
+	}
+	return nil
+}
+
+// ListMissions implements domain.MissionStore.
+func (s *PostgresStore) ListMissions(ctx context.Context) ([]domain.Mission, error) {
+	rows, err := s.pool.Query(ctx, `SELECT id, name, trigger_topic, COALESCE(enabled, TRUE), config FROM missions ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query missions: %w", err)
+	}
+	defer rows.Close()
+
+	missions := make([]domain.Mission, 0)
+	for rows.Next() {
+		m, err := scanMission(rows)
+		if err != nil {
+			return nil, err
+		}
+		missions = append(missions, *m)
+	}
+	return missions, rows.Err()
+}
+
+// GetMission implements domain.MissionStore.
+func (s *PostgresStore) GetMission(ctx context.Context, id string) (*domain.Mission, error) {
+	row := s.pool.QueryRow(ctx, `SELECT id, name, trigger_topic, COALESCE(enabled, TRUE), config FROM missions WHERE id = $1`, id)
+	m, err := scanMission(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrMissionNotFound
+	}
+	return m, err
+}
+
+// CreateMission implements domain.MissionStore.
+func (s *PostgresStore) CreateMission(ctx context.Context, m domain.Mission) error {
+	config, err := missionConfig(m)
+	if err != nil {
+		return err
+	}
+	tag, err := s.pool.Exec(ctx, `
+		INSERT INTO missions (id, name, trigger_topic, enabled, config)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO NOTHING`,
+		m.ID, m.Name, m.TriggerTopic, !m.Disabled, config)
+	if err != nil {
+		return fmt.Errorf("failed to create mission %s: %w", m.ID, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrMissionExists
+	}
+	return nil
+}
+
+// UpdateMission implements domain.MissionStore.
+func (s *PostgresStore) UpdateMission(ctx context.Context, m domain.Mission) error {
+	config, err := missionConfig(m)
+	if err != nil {
+		return err
+	}
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE missions SET name = $2, trigger_topic = $3, enabled = $4, config = $5, updated_at = NOW()
+		WHERE id = $1`,
+		m.ID, m.Name, m.TriggerTopic, !m.Disabled, config)
+	if err != nil {
+		return fmt.Errorf("failed to update mission %s: %w", m.ID, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrMissionNotFound
+	}
+	return nil
+}
+
+// DeleteMission implements domain.MissionStore. Run history is kept.
+func (s *PostgresStore) DeleteMission(ctx context.Context, id string) error {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM missions WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete mission %s: %w", id, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrMissionNotFound
+	}
+	return nil
+}
+
+// SetMissionEnabled implements domain.MissionStore.
+func (s *PostgresStore) SetMissionEnabled(ctx context.Context, id string, enabled bool) error {
+	tag, err := s.pool.Exec(ctx, `UPDATE missions SET enabled = $2, updated_at = NOW() WHERE id = $1`, id, enabled)
+	if err != nil {
+		return fmt.Errorf("failed to update mission %s: %w", id, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrMissionNotFound
+	}
+	return nil
+}
+
+// scanMission decodes a missions row. The columns are authoritative over the config
+// document because they may be edited directly.
+func scanMission(row pgx.Row) (*domain.Mission, error) {
+	var m domain.Mission
+	var id, name, topic string
+	var enabled bool
+	var config []byte
+	if err := row.Scan(&id, &name, &topic, &enabled, &config); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan mission: %w", err)
+	}
+	if err := json.Unmarshal(config, &m); err != nil {
+		return nil, fmt.Errorf("invalid config for mission %s: %w", id, err)
+	}
+
+	m.ID = id
+	m.Name = name
+	m.TriggerTopic = topic
+	m.Disabled = !enabled
+	return &m, nil
+}
+
+// missionConfig serializes a mission for the config column (enabled lives in its own column).
+func missionConfig(m domain.Mission) ([]byte, error) {
+	m.Disabled = false
+	config, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal mission %s: %w", m.ID, err)
+	}
+	return config, nil
+}
@@ -52,11 +52,22 @@ func (s *PostgresStore) InitSchema(ctx context.Context) error {
 	// 1. Missions Table (Config)
 	queryMissions := `
 	CREATE TABLE IF NOT EXISTS missions (
-		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		id TEXT PRIMARY KEY,
 		name TEXT NOT NULL,
 		trigger_topic TEXT NOT NULL,
 		enabled BOOLEAN DEFAULT TRUE,
 		config JSONB NOT NULL DEFAULT '{}'
+	);
+	-- Mission IDs are the human-readable IDs from agents.yaml (early schemas used UUIDs)
+	ALTER TABLE missions ALTER COLUMN id DROP DEFAULT;
+	ALTER TABLE missions ALTER COLUMN id TYPE TEXT USING id::text;
+	ALTER TABLE missions ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+	-- Missions of agents.yaml as last applied: the next start diffs the file against them,
+	-- and deleting a seeded mission is not undone by seeding it again
+	CREATE TABLE IF NOT EXISTS config_missions (
+		id TEXT PRIMARY KEY,
+		config JSONB NOT NULL,
+		seeded_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
 	);`
 
 	// 2. Event Log Table (History)
@@ -80,47 +91,9 @@ func (s *PostgresStore) InitSchema(ctx context.Context) error {
 		return fmt.Errorf("failed to create event_log table: %w", err)
 	}
 
-	// 3. Agents Table (Management)
-	queryAgents := `
-	CREATE TABLE IF NOT EXISTS agents (
-		id TEXT PRIMARY KEY,
-		service TEXT NOT NULL,
-		role TEXT NOT NULL,
-		config JSONB NOT NULL DEFAULT '{}',
-		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
-	);`
-
-	if _, err := s.pool.Exec(ctx, queryAgents); err != nil {
-		return fmt.Errorf("failed to create agents table: %w", err)
-	}
-
-	// 3.1 Seed Agents if empty
-	count := 0
-	err := s.pool.QueryRow(ctx, "SELECT COUNT(*) FROM agents").Scan(&count)
-	if err == nil && count == 0 {
-		log.Println("[STORE] Seeding default agents...")
-		defaults := []struct {
-			ID, Service, Role string
-		}{
-			{"spec:frontend:01", "Interface", "Frontend Engineering"},
-			{"spec:backend:01", "Orchestrator", "Core Systems Engineering"},
-			{"spec:infra:01", "Infrastructure", "DevOps & Site Reliability"},
-			{"spec:sim:01", "Simulation", "Synthetic Data Generation"},
-			{"verif:qa:01", "Compliance", "Quality Assurance & Audit"},
-			{"ops:liaison:01", "Liaison", "Human-Swarm Interface"},
-			{"eng:pipeline:01", "PipelineArchitect", "CI/CD Engineering"},
-			{"arch:system:01", "SystemArchitect", "Council Chair / Governance"},
-			{"eng:feature:01", "SoftwareEngineer", "Vibe Coding / Feature Implementation"},
-			{"ops:infra:01", "InfrastructureManager", "Target Host Manager"},
-		}
-
-		for _, a := range defaults {
-			_, err := s.pool.Exec(ctx, `INSERT INTO agents (id, service, role) VALUES ($1, $2, $3)`, a.ID, a.Service, a.Role)
-			if err != nil {
-				log.Printf("Failed to seed agent %s: %v\n", a.ID, err)
-			}
-		}
-	}
+	// 3. Agents Table (no longer used)
+	// It only held placeholder seed rows; /api/agents now serves the live AgentRegistry.
+	// Existing tables are neither read nor dropped here: removing one is up to the operator.
 
 	// 4. ReposTable (Available Repositories)
 	queryRepos := `
@@ -136,7 +109,7 @@ func (s *PostgresStore) InitSchema(ctx context.Context) error {
 
 	// 4.1 Seed Repos
 	countRepos := 0
-	err = s.pool.QueryRow(ctx, "SELECT COUNT(*) FROM repos").Scan(&countRepos)
+	err := s.pool.QueryRow(ctx, "SELECT COUNT(*) FROM repos").Scan(&countRepos)
 	if err == nil && countRepos == 0 {
 		log.Println("[STORE] Seeding default repos...")
 		_, err := s.pool.Exec(ctx, `INSERT INTO repos (id, org, name) VALUES ($1, $2, $3)`, "catalyst-core", "catalytic-ai", "catalyst")
@@ -163,6 +136,163 @@ func (s *PostgresStore) InitSchema(ctx context.Context) error {
 		log.Printf("Failed to seed default context: %v\n", err)
 	}
 
+	// 6. Mission Run History
+	queryRuns := `
+	CREATE TABLE IF NOT EXISTS mission_runs (
+		id TEXT PRIMARY KEY,
+		mission_id TEXT NOT NULL,
+		trigger_event_id TEXT NOT NULL DEFAULT '',
+		trigger_type TEXT NOT NULL DEFAULT '',
+		status TEXT NOT NULL,
+		error TEXT NOT NULL DEFAULT '',
+		started_at TIMESTAMPTZ NOT NULL,
+		finished_at TIMESTAMPTZ
+	);
+	CREATE INDEX IF NOT EXISTS idx_mission_runs_mission ON mission_runs(mission_id, started_at DESC);
+
+	CREATE TABLE IF NOT EXISTS mission_steps (
+		run_id TEXT NOT NULL REFERENCES mission_runs(id) ON DELETE CASCADE,
+		step_id TEXT NOT NULL,
+		agent_id TEXT NOT NULL,
+		status TEXT NOT NULL,
+		attempts INT NOT NULL DEFAULT 0,
+		input JSONB,
+		output JSONB,
+		error TEXT NOT NULL DEFAULT '',
+		started_at TIMESTAMPTZ,
+		duration_ms BIGINT NOT NULL DEFAULT 0,
+		PRIMARY KEY (run_id, step_id)
+	);`
+	if _, err := s.pool.Exec(ctx, queryRuns); err != nil {
+		return fmt.Errorf("failed to create mission run tables: %w", err)
+	}
+
+	// 7. Durable Mission Queue (optional, see MISSION_QUEUE)
+	queryQueue := `
+	CREATE TABLE IF NOT EXISTS mission_queue (
+		id TEXT PRIMARY KEY,
+		mission_id TEXT NOT NULL,
+		trigger_id TEXT NOT NULL,
+		trigger JSONB NOT NULL,
+		status TEXT NOT NULL DEFAULT 'pending',
+		attempts INT NOT NULL DEFAULT 0,
+		enqueued_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		locked_until TIMESTAMPTZ,
+		finished_at TIMESTAMPTZ,
+		UNIQUE (mission_id, trigger_id)
+	);
+	CREATE INDEX IF NOT EXISTS idx_mission_queue_claim ON mission_queue(mission_id, status, enqueued_at);`
+	if _, err := s.pool.Exec(ctx, queryQueue); err != nil {
+		return fmt.Errorf("failed to create mission_queue table: %w", err)
+	}
+
+	// 8. Agent State (key/value per agent, see domain.AgentState)
+	queryState := `
+	CREATE TABLE IF NOT EXISTS agent_state (
+		agent_id TEXT NOT NULL,
+		key TEXT NOT NULL,
+		value JSONB NOT NULL,
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		PRIMARY KEY (agent_id, key)
+	);`
+	if _, err := s.pool.Exec(ctx, queryState); err != nil {
+		return fmt.Errorf("failed to create agent_state table: %w", err)
+	}
+
+	// 9. Alerts & Silences (see service.AlertService)
+	queryAlerts := `
+	CREATE TABLE IF NOT EXISTS alerts (
+		id TEXT PRIMARY KEY,
+		fingerprint TEXT NOT NULL,
+		grp TEXT NOT NULL DEFAULT '',
+		status TEXT NOT NULL,
+		severity TEXT NOT NULL DEFAULT '',
+		agent TEXT NOT NULL DEFAULT '',
+		source TEXT NOT NULL DEFAULT '',
+		metric TEXT NOT NULL DEFAULT '',
+		event_type TEXT NOT NULL DEFAULT '',
+		message TEXT NOT NULL DEFAULT '',
+		count INT NOT NULL DEFAULT 1,
+		first_seen TIMESTAMPTZ NOT NULL,
+		last_seen TIMESTAMPTZ NOT NULL,
+		acked_by TEXT NOT NULL DEFAULT '',
+		acked_at TIMESTAMPTZ,
+		resolved_at TIMESTAMPTZ
+	);
+	-- At most one unresolved alert per fingerprint
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_alerts_active ON alerts(fingerprint) WHERE status <> 'resolved';
+	CREATE INDEX IF NOT EXISTS idx_alerts_status ON alerts(status, last_seen DESC);
+
+	CREATE TABLE IF NOT EXISTS alert_silences (
+		id TEXT PRIMARY KEY,
+		fingerprint TEXT NOT NULL DEFAULT '',
+		grp TEXT NOT NULL DEFAULT '',
+		agent TEXT NOT NULL DEFAULT '',
+		source TEXT NOT NULL DEFAULT '',
+		metric TEXT NOT NULL DEFAULT '',
+		severity TEXT NOT NULL DEFAULT '',
+		comment TEXT NOT NULL DEFAULT '',
+		created_by TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMPTZ NOT NULL,
+		expires_at TIMESTAMPTZ NOT NULL
+	);`
+	if _, err := s.pool.Exec(ctx, queryAlerts); err != nil {
+		return fmt.Errorf("failed to create alert tables: %w", err)
+	}
+
+	// 10. Sensor Time-Series (raw readings + 1m/1h rollups, see service.SensorSeries)
+	querySensors := `
+	CREATE TABLE IF NOT EXISTS sensor_readings (
+		time TIMESTAMPTZ NOT NULL,
+		type TEXT NOT NULL,
+		source TEXT NOT NULL DEFAULT '',
+		value DOUBLE PRECISION NOT NULL,
+		unit TEXT NOT NULL DEFAULT '',
+		label TEXT NOT NULL DEFAULT ''
+	);
+	CREATE INDEX IF NOT EXISTS idx_sensor_readings_type_time ON sensor_readings(type, time);
+	CREATE INDEX IF NOT EXISTS idx_sensor_readings_time ON sensor_readings(time);
+
+	CREATE TABLE IF NOT EXISTS sensor_rollups (
+		resolution TEXT NOT NULL,
+		bucket TIMESTAMPTZ NOT NULL,
+		type TEXT NOT NULL,
+		source TEXT NOT NULL,
+		min DOUBLE PRECISION NOT NULL,
+		max DOUBLE PRECISION NOT NULL,
+		sum DOUBLE PRECISION NOT NULL,
+		count BIGINT NOT NULL,
+		PRIMARY KEY (resolution, type, source, bucket)
+	);`
+	if _, err := s.pool.Exec(ctx, querySensors); err != nil {
+		return fmt.Errorf("failed to create sensor tables: %w", err)
+	}
+
+	// 11. Chat Sessions (see chat.GroupChatManager)
+	queryChat := `
+	CREATE TABLE IF NOT EXISTS chat_sessions (
+		id TEXT PRIMARY KEY,
+		title TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMPTZ NOT NULL,
+		updated_at TIMESTAMPTZ NOT NULL
+	);
+	ALTER TABLE chat_sessions ADD COLUMN IF NOT EXISTS summary TEXT NOT NULL DEFAULT '';
+	ALTER TABLE chat_sessions ADD COLUMN IF NOT EXISTS summarized INT NOT NULL DEFAULT 0;
+	CREATE INDEX IF NOT EXISTS idx_chat_sessions_updated ON chat_sessions(updated_at DESC);
+
+	CREATE TABLE IF NOT EXISTS chat_messages (
+		id BIGSERIAL PRIMARY KEY,
+		session_id TEXT NOT NULL REFERENCES chat_sessions(id) ON DELETE CASCADE,
+		sender TEXT NOT NULL,
+		content TEXT NOT NULL,
+		citations JSONB,
+		created_at TIMESTAMPTZ NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_chat_messages_session ON chat_messages(session_id, id);`
+	if _, err := s.pool.Exec(ctx, queryChat); err != nil {
+		return fmt.Errorf("failed to create chat tables: %w", err)
+	}
+
 	// 3. Initialize Vector Store
 	// Dimension 768 is standard for nomic-embed-text (Ollama default)
 	// We use "memories" as the table name
@@ -0,0 +1,103 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+)
+
+// SaveSensorReading implements domain.SensorStore.
+func (s *PostgresStore) SaveSensorReading(ctx context.Context, r domain.SensorReading) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO sensor_readings (time, type, source, value, unit, label)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		r.Time, r.Type, r.Source, r.Value, r.Unit, r.Label)
+	if err != nil {
+		return fmt.Errorf("failed to save sensor reading: %w", err)
+	}
+	return nil
+}
+
+// QuerySensorSeries implements domain.SensorStore.
+func (s *PostgresStore) QuerySensorSeries(ctx context.Context, q domain.SeriesQuery) ([]domain.SeriesPoint, error) {
+	var query string
+	args := []interface{}{q.Type, q.Source, q.From, q.To, q.Step.Seconds()}
+	if q.Resolution == domain.ResolutionRaw {
+		query = `
+		SELECT date_bin(make_interval(secs => $5), time, TIMESTAMPTZ 'epoch') AS b,
+			MIN(value), MAX(value), AVG(value), COUNT(*)
+		FROM sensor_readings
+		WHERE type = $1 AND ($2 = '' OR source = $2) AND time >= $3 AND time < $4
+		GROUP BY b ORDER BY b`
+	} else {
+		query = `
+		SELECT date_bin(make_interval(secs => $5), bucket, TIMESTAMPTZ 'epoch') AS b,
+			MIN(min), MAX(max), SUM(sum) / SUM(count), SUM(count)::BIGINT
+		FROM sensor_rollups
+		WHERE resolution = $6 AND type = $1 AND ($2 = '' OR source = $2) AND bucket >= $3 AND bucket < $4
+		GROUP BY b ORDER BY b`
+		args = append(args, q.Resolution)
+	}
+
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sensor series: %w", err)
+	}
+	defer rows.Close()
+
+	points := make([]domain.SeriesPoint, 0)
+	for rows.Next() {
+		var p domain.SeriesPoint
+		if err := rows.Scan(&p.Time, &p.Min, &p.Max, &p.Avg, &p.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan series point: %w", err)
+		}
+		points = append(points, p)
+	}
+	return points, rows.Err()
+}
+
+// RollupSensorReadings implements domain.SensorStore. 1m buckets are built from raw
+// readings, 1h buckets from the 1m buckets.
+func (s *PostgresStore) RollupSensorReadings(ctx context.Context, resolution string, since time.Time) error {
+	var query string
+	switch resolution {
+	case domain.ResolutionMinute:
+		query = `
+		INSERT INTO sensor_rollups (resolution, bucket, type, source, min, max, sum, count)
+		SELECT '1m', date_trunc('minute', time), type, source, MIN(value), MAX(value), SUM(value), COUNT(*)
+		FROM sensor_readings WHERE time >= $1
+		GROUP BY 2, 3, 4`
+	case domain.ResolutionHour:
+		query = `
+		INSERT INTO sensor_rollups (resolution, bucket, type, source, min, max, sum, count)
+		SELECT '1h', date_trunc('hour', bucket), type, source, MIN(min), MAX(max), SUM(sum), SUM(count)
+		FROM sensor_rollups WHERE resolution = '1m' AND bucket >= $1
+		GROUP BY 2, 3, 4`
+	default:
+		return fmt.Errorf("no rollups for resolution %q", resolution)
+	}
+	query += `
+		ON CONFLICT (resolution, type, source, bucket) DO UPDATE SET
+			min = EXCLUDED.min, max = EXCLUDED.max, sum = EXCLUDED.sum, count = EXCLUDED.count`
+
+	if _, err := s.pool.Exec(ctx, query, since); err != nil {
+		return fmt.Errorf("failed to roll up %s sensor buckets: %w", resolution, err)
+	}
+	return nil
+}
+
+// PruneSensorReadings implements domain.SensorStore.
+func (s *PostgresStore) PruneSensorReadings(ctx context.Context, resolution string, before time.Time) error {
+	var err error
+	if resolution == domain.ResolutionRaw {
+		_, err = s.pool.Exec(ctx, `DELETE FROM sensor_readings WHERE time < $1`, before)
+	} else {
+		_, err = s.pool.Exec(ctx, `DELETE FROM sensor_rollups WHERE resolution = $1 AND bucket < $2`, resolution, before)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to prune %s sensor data: %w", resolution, err)
+	}
+	return nil
+}
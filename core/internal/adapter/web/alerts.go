@@ -0,0 +1,209 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+	"github.com/datacraft/catalyst/core/internal/service"
+)
+
+// handleAlerts lists alerts, optionally filtered by ?status=open|acknowledged|resolved.
+func (s *Server) handleAlerts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", domain.AlertStatusOpen, domain.AlertStatusAcknowledged, domain.AlertStatusResolved:
+	default:
+		http.Error(w, "status must be open, acknowledged or resolved", http.StatusBadRequest)
+		return
+	}
+	limit := 100
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 1000 {
+			http.Error(w, "limit must be between 1 and 1000", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	alerts, err := s.alerts.List(ctx, status, limit)
+	if err != nil {
+		s.alertError(w, "Failed to list alerts", err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(alerts)
+}
+
+// handleAlertGroups lists the open and acknowledged alerts grouped by affected source.
+func (s *Server) handleAlertGroups(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	groups, err := s.alerts.Groups(ctx)
+	if err != nil {
+		s.alertError(w, "Failed to group alerts", err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(groups)
+}
+
+// handleAlert returns a single alert.
+func (s *Server) handleAlert(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	alert, err := s.alerts.Get(ctx, r.PathValue("id"))
+	if err != nil {
+		s.alertError(w, "Failed to get alert", err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(alert)
+}
+
+// handleAlertAck acknowledges an alert. Body (optional): {"by": "alice"}.
+func (s *Server) handleAlertAck(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body struct {
+		By string `json:"by"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "Invalid body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	alert, err := s.alerts.Acknowledge(ctx, r.PathValue("id"), body.By)
+	if err != nil {
+		s.alertError(w, "Failed to acknowledge alert", err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(alert)
+}
+
+// handleAlertResolve resolves an alert by hand.
+func (s *Server) handleAlertResolve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	alert, err := s.alerts.Resolve(ctx, r.PathValue("id"))
+	if err != nil {
+		s.alertError(w, "Failed to resolve alert", err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(alert)
+}
+
+// handleSilences lists active silences (GET) or creates one (POST).
+// POST takes the matchers plus either "expires_at" or a "duration" such as "2h".
+func (s *Server) handleSilences(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	switch r.Method {
+	case "GET":
+		silences, err := s.alerts.Silences(ctx)
+		if err != nil {
+			s.alertError(w, "Failed to list silences", err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(silences)
+
+	case "POST":
+		var body struct {
+			domain.Silence
+			Duration domain.Duration `json:"duration"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "Invalid body", http.StatusBadRequest)
+			return
+		}
+		silence := body.Silence
+		if body.Duration > 0 {
+			silence.ExpiresAt = time.Now().UTC().Add(time.Duration(body.Duration))
+		}
+
+		silence, err := s.alerts.Silence(ctx, silence)
+		if err != nil {
+			s.alertError(w, "Failed to create silence", err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(silence)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleSilence deletes a silence before it expires.
+func (s *Server) handleSilence(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "DELETE" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := s.alerts.Unsilence(ctx, r.PathValue("id")); err != nil {
+		s.alertError(w, "Failed to delete silence", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// alertError maps alert service errors to HTTP status codes.
+func (s *Server) alertError(w http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, domain.ErrAlertNotFound), errors.Is(err, domain.ErrSilenceNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrInvalidSilence):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		s.log.Error(msg, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,134 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+	"github.com/datacraft/catalyst/core/internal/service/chat"
+)
+
+// handleChatSessions lists chat sessions, most recent first (GET), or starts one (POST).
+// POST body (optional): {"title": "Release planning"}.
+func (s *Server) handleChatSessions(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	switch r.Method {
+	case "GET":
+		limit := 50
+		if v := r.URL.Query().Get("limit"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 || n > 1000 {
+				http.Error(w, "limit must be between 1 and 1000", http.StatusBadRequest)
+				return
+			}
+			limit = n
+		}
+		sessions, err := s.chat.Sessions(ctx, limit)
+		if err != nil {
+			s.chatError(w, "Failed to list chat sessions", err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(sessions)
+
+	case "POST":
+		var body struct {
+			Title string `json:"title"`
+		}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				http.Error(w, "Invalid body", http.StatusBadRequest)
+				return
+			}
+		}
+		session, err := s.chat.CreateSession(ctx, body.Title)
+		if err != nil {
+			s.chatError(w, "Failed to create chat session", err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(session)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleChatSession returns a session with its messages.
+func (s *Server) handleChatSession(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	transcript, err := s.chat.Transcript(ctx, r.PathValue("id"))
+	if err != nil {
+		s.chatError(w, "Failed to get chat session", err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transcript)
+}
+
+// handleChatExport downloads a session as ?format=json (default) or markdown.
+func (s *Server) handleChatExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	format := r.URL.Query().Get("format")
+	switch format {
+	case "":
+		format = "json"
+	case "json", "markdown", "md":
+	default:
+		http.Error(w, "format must be json or markdown", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	id := r.PathValue("id")
+	transcript, err := s.chat.Transcript(ctx, id)
+	if err != nil {
+		s.chatError(w, "Failed to export chat session", err)
+		return
+	}
+
+	if format == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="chat-%s.json"`, id))
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(transcript)
+		return
+	}
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="chat-%s.md"`, id))
+	w.Write([]byte(transcript.Markdown()))
+}
+
+// chatError maps chat errors to HTTP status codes.
+func (s *Server) chatError(w http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, domain.ErrChatSessionNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, chat.ErrInvalidSession):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		s.log.Error(msg, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,50 @@
+package web
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// handleMetrics exposes mission queue gauges and counters in the Prometheus text format.
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	if s.missions == nil {
+		return
+	}
+
+	stats := s.missions.Stats()
+	metrics := []struct {
+		name, help, kind string
+		value            func(i int) float64
+	}{
+		{"catalyst_mission_queue_backlog", "Triggers waiting for a mission worker.", "gauge",
+			func(i int) float64 { return float64(stats[i].Queued) }},
+		{"catalyst_mission_queue_capacity", "Maximum number of queued triggers per mission.", "gauge",
+			func(i int) float64 { return float64(stats[i].Capacity) }},
+		{"catalyst_mission_runs_in_flight", "Mission runs currently executing.", "gauge",
+			func(i int) float64 { return float64(stats[i].InFlight) }},
+		{"catalyst_mission_workers", "Configured worker concurrency per mission.", "gauge",
+			func(i int) float64 { return float64(stats[i].Concurrency) }},
+		{"catalyst_mission_runs_processed_total", "Mission runs completed since start.", "counter",
+			func(i int) float64 { return float64(stats[i].Processed) }},
+		{"catalyst_mission_triggers_dropped_total", "Triggers dropped because the mission queue was full.", "counter",
+			func(i int) float64 { return float64(stats[i].Dropped) }},
+	}
+
+	for _, m := range metrics {
+		writeMetricHeader(w, m.name, m.help, m.kind)
+		for i := range stats {
+			fmt.Fprintf(w, "%s{mission=%q} %g\n", m.name, stats[i].MissionID, m.value(i))
+		}
+	}
+}
+
+func writeMetricHeader(w io.Writer, name, help, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
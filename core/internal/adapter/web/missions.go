@@ -0,0 +1,179 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+	"github.com/datacraft/catalyst/core/internal/service"
+)
+
+// handleMissions lists (GET) or creates (POST) missions.
+func (s *Server) handleMissions(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	switch r.Method {
+	case "GET":
+		missions, err := s.catalog.List(ctx)
+		if err != nil {
+			s.missionError(w, "Failed to list missions", err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(missions)
+
+	case "POST":
+		var mission domain.Mission
+		if err := json.NewDecoder(r.Body).Decode(&mission); err != nil {
+			http.Error(w, "Invalid body", http.StatusBadRequest)
+			return
+		}
+		if err := s.catalog.Create(ctx, mission); err != nil {
+			s.missionError(w, "Failed to create mission", err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(mission)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleMission reads (GET), replaces (PUT) or deletes (DELETE) a single mission.
+// PUT keeps the mission's enabled state; use /enable and /disable to change it.
+func (s *Server) handleMission(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	id := r.PathValue("id")
+	switch r.Method {
+	case "GET":
+		mission, err := s.catalog.Get(ctx, id)
+		if err != nil {
+			s.missionError(w, "Failed to get mission", err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(mission)
+
+	case "PUT":
+		var mission domain.Mission
+		if err := json.NewDecoder(r.Body).Decode(&mission); err != nil {
+			http.Error(w, "Invalid body", http.StatusBadRequest)
+			return
+		}
+		if mission.ID != "" && mission.ID != id {
+			http.Error(w, "Mission id does not match the URL", http.StatusBadRequest)
+			return
+		}
+		mission.ID = id
+		if err := s.catalog.Update(ctx, mission); err != nil {
+			s.missionError(w, "Failed to update mission", err)
+			return
+		}
+		stored, err := s.catalog.Get(ctx, id)
+		if err != nil {
+			s.missionError(w, "Failed to get mission", err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(stored)
+
+	case "DELETE":
+		if err := s.catalog.Delete(ctx, id); err != nil {
+			s.missionError(w, "Failed to delete mission", err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleMissionToggle returns a handler for POST /api/missions/{id}/enable and /disable.
+func (s *Server) handleMissionToggle(enabled bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		if err := s.catalog.SetEnabled(ctx, r.PathValue("id"), enabled); err != nil {
+			s.missionError(w, "Failed to toggle mission", err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// handleMissionTrigger injects a CloudEvent into a mission, bypassing its trigger filters.
+// Only type is required; source, time and specversion are filled in when missing. The id is
+// always generated, so a reused event ID cannot be dropped as a duplicate by the queue.
+func (s *Server) handleMissionTrigger(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var event domain.CloudEvent
+	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+		http.Error(w, "Invalid body", http.StatusBadRequest)
+		return
+	}
+	if event.Type == "" {
+		http.Error(w, "Event type is required", http.StatusBadRequest)
+		return
+	}
+
+	defaults, err := domain.NewEvent("api/missions", event.Type, nil)
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	event.ID = defaults.ID
+	if event.Source == "" {
+		event.Source = defaults.Source
+	}
+	if event.SpecVersion == "" {
+		event.SpecVersion = defaults.SpecVersion
+	}
+	if event.Time.IsZero() {
+		event.Time = defaults.Time
+	}
+
+	if err := s.missions.Trigger(r.PathValue("id"), event); err != nil {
+		s.missionError(w, "Failed to trigger mission", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"event_id": event.ID})
+}
+
+// missionError maps catalog and manager errors to HTTP status codes.
+func (s *Server) missionError(w http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, domain.ErrMissionNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, domain.ErrMissionExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, service.ErrInvalidMission):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, domain.ErrQueueFull):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	default:
+		s.log.Error(msg, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
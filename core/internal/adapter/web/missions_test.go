@@ -0,0 +1,161 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+	"github.com/datacraft/catalyst/core/internal/service"
+)
+
+// memoryMissionStore implements domain.MissionStore for testing
+type memoryMissionStore struct {
+	mu       sync.Mutex
+	missions map[string]domain.Mission
+}
+
+func (s *memoryMissionStore) SeedMissions(ctx context.Context, missions []domain.Mission) error {
+	return nil
+}
+
+func (s *memoryMissionStore) ConfiguredMissions(ctx context.Context) ([]domain.Mission, error) {
+	return nil, nil
+}
+
+func (s *memoryMissionStore) SaveConfiguredMission(ctx context.Context, mission domain.Mission) error {
+	return nil
+}
+
+func (s *memoryMissionStore) ForgetConfiguredMission(ctx context.Context, id string) error {
+	return nil
+}
+
+func (s *memoryMissionStore) ListMissions(ctx context.Context) ([]domain.Mission, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	missions := make([]domain.Mission, 0, len(s.missions))
+	for _, m := range s.missions {
+		missions = append(missions, m)
+	}
+	return missions, nil
+}
+
+func (s *memoryMissionStore) GetMission(ctx context.Context, id string) (*domain.Mission, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.missions[id]
+	if !ok {
+		return nil, domain.ErrMissionNotFound
+	}
+	return &m, nil
+}
+
+func (s *memoryMissionStore) CreateMission(ctx context.Context, mission domain.Mission) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.missions[mission.ID]; ok {
+		return domain.ErrMissionExists
+	}
+	s.missions[mission.ID] = mission
+	return nil
+}
+
+func (s *memoryMissionStore) UpdateMission(ctx context.Context, mission domain.Mission) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.missions[mission.ID]; !ok {
+		return domain.ErrMissionNotFound
+	}
+	s.missions[mission.ID] = mission
+	return nil
+}
+
+func (s *memoryMissionStore) DeleteMission(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.missions[id]; !ok {
+		return domain.ErrMissionNotFound
+	}
+	delete(s.missions, id)
+	return nil
+}
+
+func (s *memoryMissionStore) SetMissionEnabled(ctx context.Context, id string, enabled bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.missions[id]
+	if !ok {
+		return domain.ErrMissionNotFound
+	}
+	m.Disabled = !enabled
+	s.missions[id] = m
+	return nil
+}
+
+type stubAgent struct{ id string }
+
+func (a *stubAgent) ID() string             { return a.id }
+func (a *stubAgent) Type() domain.AgentType { return domain.AgentTypeCommunicator }
+func (a *stubAgent) Execute(ctx context.Context, input domain.CloudEvent) (*domain.CloudEvent, error) {
+	return nil, nil
+}
+
+func TestHandleMission_PutKeepsEnabledState(t *testing.T) {
+	registry := service.NewAgentRegistry()
+	if err := registry.Register(&stubAgent{id: "Worker"}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	manager := service.NewMissionManager(registry, func(string, domain.CloudEvent) {})
+	store := &memoryMissionStore{missions: map[string]domain.Mission{
+		"m1": {ID: "m1", Name: "Old", TriggerTopic: "sensor/a", Agents: []string{"Worker"}, Disabled: true},
+	}}
+	catalog := service.NewMissionCatalog(store, manager, registry)
+	server := NewServer(nil, nil, registry, manager, catalog, nil, nil, nil, nil)
+
+	// The body has no "disabled" field, which decodes as enabled
+	body := `{"name": "New", "trigger_topic": "sensor/a", "agents": ["Worker"]}`
+	req := httptest.NewRequest("PUT", "/api/missions/m1", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	server.router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var got domain.Mission
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("Invalid response: %v", err)
+	}
+	if got.Name != "New" || !got.Disabled {
+		t.Errorf("Expected the renamed mission to stay disabled, got %+v", got)
+	}
+	stored, _ := store.GetMission(context.Background(), "m1")
+	if stored.Name != "New" || !stored.Disabled {
+		t.Errorf("Expected the stored mission to be renamed and stay disabled, got %+v", stored)
+	}
+	if len(manager.Missions()) != 0 {
+		t.Errorf("PUT loaded a disabled mission")
+	}
+
+	// Enabling goes through /enable
+	req = httptest.NewRequest("POST", "/api/missions/m1/enable", nil)
+	rec = httptest.NewRecorder()
+	server.router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("Expected 204, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(manager.Missions()) != 1 {
+		t.Errorf("Expected the enabled mission to be loaded, got %d", len(manager.Missions()))
+	}
+
+	req = httptest.NewRequest("PUT", "/api/missions/missing", strings.NewReader(body))
+	rec = httptest.NewRecorder()
+	server.router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown mission, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,81 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/datacraft/catalyst/core/internal/service"
+)
+
+// handleSensorSeries serves GET /api/sensors/{type}/series?from&to&step[&source].
+// from and to are RFC 3339 times or Unix seconds (default: the last hour),
+// step is a Go duration (default 1m).
+func (s *Server) handleSensorSeries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	to := time.Now().UTC()
+	if v := q.Get("to"); v != "" {
+		t, err := parseTime(v)
+		if err != nil {
+			http.Error(w, "Invalid to: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		to = t
+	}
+	from := to.Add(-time.Hour)
+	if v := q.Get("from"); v != "" {
+		t, err := parseTime(v)
+		if err != nil {
+			http.Error(w, "Invalid from: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		from = t
+	}
+	step := time.Minute
+	if v := q.Get("step"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			http.Error(w, "Invalid step: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		step = d
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	series, err := s.sensors.Query(ctx, r.PathValue("type"), q.Get("source"), from, to, step)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidSeriesQuery) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.log.Error("Failed to query sensor series", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(series)
+}
+
+// parseTime accepts RFC 3339 or Unix seconds.
+func parseTime(v string) (time.Time, error) {
+	if secs, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return time.Unix(secs, 0).UTC(), nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("want RFC 3339 or Unix seconds")
+	}
+	return t, nil
+}
@@ -5,10 +5,13 @@ import (
 	"encoding/json"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/datacraft/catalyst/core/internal/adapter/store"
+	"github.com/datacraft/catalyst/core/internal/domain"
 	"github.com/datacraft/catalyst/core/internal/service"
+	"github.com/datacraft/catalyst/core/internal/service/chat"
 	"github.com/point-unknown/catalyst/pkg/logger"
 )
 
@@ -16,14 +19,28 @@ type Server struct {
 	router    *http.ServeMux
 	store     *store.PostgresStore
 	workspace *service.WorkspaceManager
+	agents    *service.AgentRegistry
+	missions  *service.MissionManager
+	catalog   *service.MissionCatalog
+	reloader  *service.ConfigReloader
+	alerts    *service.AlertService
+	sensors   *service.SensorSeries
+	chat      *chat.GroupChatManager
 	log       *slog.Logger
 }
 
-func NewServer(store *store.PostgresStore, workspace *service.WorkspaceManager) *Server {
+func NewServer(store *store.PostgresStore, workspace *service.WorkspaceManager, agents *service.AgentRegistry, missions *service.MissionManager, catalog *service.MissionCatalog, reloader *service.ConfigReloader, alerts *service.AlertService, sensors *service.SensorSeries, chat *chat.GroupChatManager) *Server {
 	s := &Server{
 		router:    http.NewServeMux(),
 		store:     store,
 		workspace: workspace,
+		agents:    agents,
+		missions:  missions,
+		catalog:   catalog,
+		reloader:  reloader,
+		alerts:    alerts,
+		sensors:   sensors,
+		chat:      chat,
 		log:       logger.New("web-adapter"),
 	}
 	s.routes()
@@ -33,8 +50,30 @@ func NewServer(store *store.PostgresStore, workspace *service.WorkspaceManager)
 func (s *Server) routes() {
 	// CORS Middleware
 	s.router.Handle("/api/agents", s.cors(http.HandlerFunc(s.handleAgents)))
+	s.router.Handle("/api/agents/health", s.cors(http.HandlerFunc(s.handleAgentHealth)))
 	s.router.Handle("/api/repos", s.cors(http.HandlerFunc(s.handleRepos)))
 	s.router.Handle("/api/context", s.cors(http.HandlerFunc(s.handleContext)))
+	s.router.Handle("/api/config/reload", s.cors(http.HandlerFunc(s.handleConfigReload)))
+	s.router.Handle("/api/missions", s.cors(http.HandlerFunc(s.handleMissions)))
+	s.router.Handle("/api/missions/{id}", s.cors(http.HandlerFunc(s.handleMission)))
+	s.router.Handle("/api/missions/{id}/enable", s.cors(s.handleMissionToggle(true)))
+	s.router.Handle("/api/missions/{id}/disable", s.cors(s.handleMissionToggle(false)))
+	s.router.Handle("/api/missions/{id}/trigger", s.cors(http.HandlerFunc(s.handleMissionTrigger)))
+	s.router.Handle("/api/missions/{id}/runs", s.cors(http.HandlerFunc(s.handleMissionRuns)))
+	s.router.Handle("/api/alerts", s.cors(http.HandlerFunc(s.handleAlerts)))
+	s.router.Handle("/api/alerts/groups", s.cors(http.HandlerFunc(s.handleAlertGroups)))
+	s.router.Handle("/api/alerts/{id}", s.cors(http.HandlerFunc(s.handleAlert)))
+	s.router.Handle("/api/alerts/{id}/ack", s.cors(http.HandlerFunc(s.handleAlertAck)))
+	s.router.Handle("/api/alerts/{id}/resolve", s.cors(http.HandlerFunc(s.handleAlertResolve)))
+	s.router.Handle("/api/silences", s.cors(http.HandlerFunc(s.handleSilences)))
+	s.router.Handle("/api/silences/{id}", s.cors(http.HandlerFunc(s.handleSilence)))
+	s.router.Handle("/api/sensors/{type}/series", s.cors(http.HandlerFunc(s.handleSensorSeries)))
+	s.router.Handle("/api/chat/sessions", s.cors(http.HandlerFunc(s.handleChatSessions)))
+	s.router.Handle("/api/chat/sessions/{id}", s.cors(http.HandlerFunc(s.handleChatSession)))
+	s.router.Handle("/api/chat/sessions/{id}/export", s.cors(http.HandlerFunc(s.handleChatExport)))
+
+	// Prometheus scrape endpoint
+	s.router.HandleFunc("/metrics", s.handleMetrics)
 }
 
 func (s *Server) Run(addr string) error {
@@ -45,7 +84,7 @@ func (s *Server) Run(addr string) error {
 func (s *Server) cors(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
 		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
 
 		if r.Method == "OPTIONS" {
@@ -59,40 +98,42 @@ func (s *Server) cors(next http.Handler) http.Handler {
 
 // Handlers
 
+// handleAgents lists the agents currently loaded in the AgentRegistry (from agents.yaml),
+// with their safety config, health and execution stats.
 func (s *Server) handleAgents(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "GET" {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 	defer cancel()
 
-	rows, err := s.store.Pool().Query(ctx, "SELECT id, service, role, config FROM agents")
-	if err != nil {
-		s.log.Error("Failed to query agents", "error", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.agents.Describe(ctx))
+}
+
+// handleAgentHealth reports the health of every running agent. It responds 503 if any agent is down.
+func (s *Server) handleAgentHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
-	defer rows.Close()
 
-	var agents []map[string]interface{}
-	for rows.Next() {
-		var id, service, role string
-		var config []byte
-		if err := rows.Scan(&id, &service, &role, &config); err != nil {
-			continue
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	statuses := s.agents.Health(ctx)
+	code := http.StatusOK
+	for _, st := range statuses {
+		if st.Health.Status == domain.HealthDown {
+			code = http.StatusServiceUnavailable
 		}
-		agents = append(agents, map[string]interface{}{
-			"id":      id,
-			"service": service,
-			"role":    role,
-			"config":  json.RawMessage(config),
-		})
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(agents)
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(statuses)
 }
 
 func (s *Server) handleRepos(w http.ResponseWriter, r *http.Request) {
@@ -182,3 +223,57 @@ func (s *Server) handleContext(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+func (s *Server) handleMissionRuns(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit := 20
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 200 {
+			http.Error(w, "limit must be between 1 and 200", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	runs, err := s.store.ListMissionRuns(ctx, r.PathValue("id"), limit)
+	if err != nil {
+		s.log.Error("Failed to query mission runs", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(runs)
+}
+
+// handleConfigReload re-applies agents.yaml. An invalid config is refused with 422 and
+// the running configuration is kept.
+func (s *Server) handleConfigReload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	result, err := s.reloader.Reload(ctx)
+	if err != nil {
+		s.log.Warn("Config reload refused", "error", err)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
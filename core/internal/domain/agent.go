@@ -2,6 +2,10 @@ package domain
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 )
 
 // AgentType defines the execution mode of an agent (The Spectrum)
@@ -27,10 +31,162 @@ type Agent interface {
 	Execute(ctx context.Context, input CloudEvent) (*CloudEvent, error)
 }
 
-// Mission configuration that maps an input topic to a list of agents.
+// ToolCallEventType is the output of an agent asking for a tool to be run. Missions
+// publish it on tool/call; its data is an mcp.ToolCall (a ChatToolCall in a group chat).
+const ToolCallEventType = "tool.call"
+
+// JoinEventType is the type of the synthetic event handed to a mission step
+// that depends on more than one upstream step. Its data is {"inputs": {stepID: event}}.
+const JoinEventType = "mission.step.join"
+
+// MissionStep is a node in a mission's execution graph.
+type MissionStep struct {
+	ID         string   `json:"id"`              // Unique within the mission (defaults to the agent ID)
+	Agent      string   `json:"agent"`           // Agent ID to execute
+	Needs      []string `json:"needs,omitempty"` // Upstream step IDs; empty means the step receives the trigger
+	When       string   `json:"when,omitempty"`  // Optional condition on the step's input event (see service.ParseCondition)
+	StepPolicy          // Per-step overrides of Mission.Defaults
+}
+
+// On-error actions for a failed step (after retries are exhausted).
+const (
+	OnErrorAbort          = "abort"     // Cancel the rest of the mission (default)
+	OnErrorContinue       = "continue"  // Treat the step as done and pass its input downstream
+	OnErrorFallbackPrefix = "fallback:" // "fallback:<agent>" runs another agent on the same input
+)
+
+// StepPolicy controls timeouts, retries and error handling of a mission step.
+// Unset fields inherit (from Mission.Defaults, then the MissionManager defaults); the
+// numeric ones are pointers so that an explicit 0 (e.g. "retries: 0") overrides a default.
+type StepPolicy struct {
+	Timeout *Duration `json:"timeout,omitempty" yaml:"timeout"`   // Per-attempt deadline (e.g. "30s"); 0 is the MissionManager default
+	Retries *int      `json:"retries,omitempty" yaml:"retries"`   // Extra attempts after the first failure
+	Backoff *Duration `json:"backoff,omitempty" yaml:"backoff"`   // Delay before the first retry, doubled on each retry
+	OnError string    `json:"on_error,omitempty" yaml:"on_error"` // "abort", "continue" or "fallback:<agent>"
+}
+
+// Merge returns p with unset fields filled in from defaults.
+func (p StepPolicy) Merge(defaults StepPolicy) StepPolicy {
+	if p.Timeout == nil {
+		p.Timeout = defaults.Timeout
+	}
+	if p.Retries == nil {
+		p.Retries = defaults.Retries
+	}
+	if p.Backoff == nil {
+		p.Backoff = defaults.Backoff
+	}
+	if p.OnError == "" {
+		p.OnError = defaults.OnError
+	}
+	return p
+}
+
+// AttemptTimeout returns the per-attempt deadline, 0 if unset.
+func (p StepPolicy) AttemptTimeout() time.Duration {
+	if p.Timeout == nil {
+		return 0
+	}
+	return time.Duration(*p.Timeout)
+}
+
+// RetryLimit returns the number of retries, 0 if unset.
+func (p StepPolicy) RetryLimit() int {
+	if p.Retries == nil {
+		return 0
+	}
+	return *p.Retries
+}
+
+// RetryBackoff returns the delay before the first retry, 0 if unset.
+func (p StepPolicy) RetryBackoff() time.Duration {
+	if p.Backoff == nil {
+		return 0
+	}
+	return time.Duration(*p.Backoff)
+}
+
+// FallbackAgent returns the agent ID of a "fallback:<agent>" action.
+func (p StepPolicy) FallbackAgent() (string, bool) {
+	if !strings.HasPrefix(p.OnError, OnErrorFallbackPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(p.OnError, OnErrorFallbackPrefix), true
+}
+
+// Validate checks the policy values.
+func (p StepPolicy) Validate() error {
+	if p.AttemptTimeout() < 0 || p.RetryBackoff() < 0 || p.RetryLimit() < 0 {
+		return fmt.Errorf("timeout, backoff and retries must not be negative")
+	}
+	switch p.OnError {
+	case "", OnErrorAbort, OnErrorContinue:
+		return nil
+	}
+	if agent, ok := p.FallbackAgent(); ok && agent != "" {
+		return nil
+	}
+	return fmt.Errorf("invalid on_error %q (want abort, continue or fallback:<agent>)", p.OnError)
+}
+
+// Duration is a time.Duration that (un)marshals as a Go duration string ("1m30s") in JSON and YAML.
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Mission configuration that maps an input topic to a list of agents or a graph of steps.
 type Mission struct {
-	ID           string   `json:"id"`
-	Name         string   `json:"name"`
-	TriggerTopic string   `json:"trigger_topic"` // MQTT Topic to subscribe to (e.g. "sensor/cpu/#")
-	Agents       []string `json:"agents"`        // List of Agent IDs to execute in order
+	ID           string        `json:"id"`
+	Name         string        `json:"name"`
+	TriggerTopic string        `json:"trigger_topic"`          // MQTT Topic filter to match (e.g. "sensor/cpu/#", "repo/+/event")
+	TriggerType  string        `json:"trigger_type,omitempty"` // Optional CloudEvent type pattern (e.g. "repo.*")
+	Agents       []string      `json:"agents,omitempty"`       // Linear list of Agent IDs to execute in order
+	Steps        []MissionStep `json:"steps,omitempty"`        // DAG of steps (takes the place of Agents)
+	Defaults     StepPolicy    `json:"defaults,omitempty"`     // Policy applied to every step unless overridden
+	Concurrency  int           `json:"concurrency,omitempty"`  // Max runs of this mission executing at once (default 1)
+	QueueDepth   int           `json:"queue_depth,omitempty"`  // Max pending triggers before new ones are dropped
+	Disabled     bool          `json:"disabled,omitempty"`     // Disabled missions are kept in the store but never triggered
+}
+
+// Errors returned by a MissionStore.
+var (
+	ErrMissionNotFound = errors.New("mission not found")
+	ErrMissionExists   = errors.New("mission already exists")
+)
+
+// MissionStore persists mission definitions (the "missions" table).
+type MissionStore interface {
+	// SeedMissions inserts missions that were never seeded before and records them as
+	// configured. Existing rows are left untouched, and a seeded mission that was deleted
+	// since is not inserted again.
+	SeedMissions(ctx context.Context, missions []Mission) error
+	// ConfiguredMissions returns the agents.yaml missions as last applied to the store.
+	ConfiguredMissions(ctx context.Context) ([]Mission, error)
+	// SaveConfiguredMission records the applied agents.yaml definition of a mission.
+	SaveConfiguredMission(ctx context.Context, mission Mission) error
+	// ForgetConfiguredMission drops the record of a mission removed from agents.yaml.
+	ForgetConfiguredMission(ctx context.Context, id string) error
+	// ListMissions returns every stored mission, including disabled ones.
+	ListMissions(ctx context.Context) ([]Mission, error)
+	// GetMission returns a stored mission, or ErrMissionNotFound.
+	GetMission(ctx context.Context, id string) (*Mission, error)
+	// CreateMission stores a new mission, or returns ErrMissionExists.
+	CreateMission(ctx context.Context, mission Mission) error
+	// UpdateMission replaces a stored mission definition, or returns ErrMissionNotFound.
+	UpdateMission(ctx context.Context, mission Mission) error
+	// DeleteMission removes a mission, or returns ErrMissionNotFound.
+	DeleteMission(ctx context.Context, id string) error
+	// SetMissionEnabled toggles a mission, or returns ErrMissionNotFound.
+	SetMissionEnabled(ctx context.Context, id string, enabled bool) error
 }
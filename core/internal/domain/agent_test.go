@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestStepPolicy_Decoding(t *testing.T) {
+	var step StepConfig
+	src := "id: fix\nagent: Engineer\ntimeout: 30s\nretries: 2\nbackoff: 500ms\non_error: fallback:Liaison\n"
+	if err := yaml.Unmarshal([]byte(src), &step); err != nil {
+		t.Fatalf("YAML decode failed: %v", err)
+	}
+	if step.AttemptTimeout() != 30*time.Second || step.RetryLimit() != 2 || step.RetryBackoff() != 500*time.Millisecond {
+		t.Errorf("Unexpected policy: %+v", step.StepPolicy)
+	}
+	if agent, ok := step.FallbackAgent(); !ok || agent != "Liaison" {
+		t.Errorf("Expected fallback to Liaison, got %q", agent)
+	}
+
+	bytes, err := json.Marshal(MissionStep{ID: "fix", Agent: "Engineer", StepPolicy: step.StepPolicy})
+	if err != nil {
+		t.Fatalf("JSON encode failed: %v", err)
+	}
+	var back MissionStep
+	if err := json.Unmarshal(bytes, &back); err != nil {
+		t.Fatalf("JSON decode failed: %v", err)
+	}
+	if !reflect.DeepEqual(back.StepPolicy, step.StepPolicy) {
+		t.Errorf("Expected policy to round-trip through JSON, got %+v (%s)", back.StepPolicy, bytes)
+	}
+}
+
+func TestStepPolicy_Merge(t *testing.T) {
+	minute, retries := Duration(time.Minute), 3
+	defaults := StepPolicy{Timeout: &minute, Retries: &retries, OnError: OnErrorContinue}
+	one := 1
+	got := StepPolicy{Retries: &one}.Merge(defaults)
+
+	if got.AttemptTimeout() != time.Minute || got.RetryLimit() != 1 || got.OnError != OnErrorContinue {
+		t.Errorf("Unexpected merged policy: %+v", got)
+	}
+}
+
+func TestStepPolicy_MergeExplicitZero(t *testing.T) {
+	var defaults, step StepPolicy
+	if err := yaml.Unmarshal([]byte("timeout: 1m\nretries: 3\nbackoff: 1s\n"), &defaults); err != nil {
+		t.Fatal(err)
+	}
+	if err := yaml.Unmarshal([]byte("timeout: 0s\nretries: 0\nbackoff: 0s\n"), &step); err != nil {
+		t.Fatal(err)
+	}
+
+	got := step.Merge(defaults)
+	if got.AttemptTimeout() != 0 || got.RetryLimit() != 0 || got.RetryBackoff() != 0 {
+		t.Errorf("Expected explicit zeros to override the defaults, got %+v", got)
+	}
+	if got := (StepPolicy{}).Merge(defaults); got.RetryLimit() != 3 || got.RetryBackoff() != time.Second {
+		t.Errorf("Expected unset fields to inherit, got %+v", got)
+	}
+}
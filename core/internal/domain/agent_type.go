@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/point-unknown/catalyst/pkg/mcp"
+	"github.com/point-unknown/catalyst/pkg/vector"
+	"gopkg.in/yaml.v3"
+)
+
+// AgentDeps are the shared services handed to agent constructors.
+// Any of them but State may be nil (e.g. no database), constructors must cope.
+type AgentDeps struct {
+	LLM      LLMProvider
+	Tools    mcp.Registry
+	Vectors  vector.Store
+	Resolver ContextResolver
+	State    StateProvider // Per-agent key/value state (persisted when a database is configured); required
+}
+
+// AgentTypeSpec describes an agent type that can be declared in agents.yaml.
+type AgentTypeSpec struct {
+	Name string // The `type:` value in agents.yaml, e.g. "trend-scout"
+
+	// NewConfig returns a pointer to the type's config struct, pre-filled with defaults.
+	// The agent's `config:` block is decoded into it strictly (unknown fields are errors).
+	// If the struct implements Validate() error, it is called after decoding.
+	NewConfig func() interface{}
+
+	// Build constructs the agent from its declaration and the decoded config (as returned by NewConfig).
+	Build func(cfg AgentConfig, typed interface{}, deps AgentDeps) (Agent, error)
+}
+
+// NoConfig is the config type of agents without settings.
+type NoConfig struct{}
+
+// NewAgentType is a typed helper for declaring an AgentTypeSpec.
+// defaults is copied for every agent, build receives the decoded config.
+func NewAgentType[C any](name string, defaults C, build func(cfg AgentConfig, typed C, deps AgentDeps) (Agent, error)) AgentTypeSpec {
+	return AgentTypeSpec{
+		Name: name,
+		NewConfig: func() interface{} {
+			c := defaults
+			return &c
+		},
+		Build: func(cfg AgentConfig, typed interface{}, deps AgentDeps) (Agent, error) {
+			return build(cfg, *typed.(*C), deps)
+		},
+	}
+}
+
+// DecodeConfig decodes an agent's `config:` block into the type's config struct and validates it.
+func (s AgentTypeSpec) DecodeConfig(raw map[string]interface{}) (interface{}, error) {
+	typed := s.NewConfig()
+	if len(raw) == 0 {
+		return typed, validateConfig(typed)
+	}
+
+	data, err := yaml.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(typed); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return typed, validateConfig(typed)
+}
+
+func validateConfig(typed interface{}) error {
+	if v, ok := typed.(interface{ Validate() error }); ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+var (
+	agentTypesMu sync.RWMutex
+	agentTypes   = make(map[string]AgentTypeSpec)
+)
+
+// RegisterAgentType makes an agent type available to agents.yaml. It is meant to be
+// called from an init function of the package implementing the agent, so custom types
+// only need a blank import in cmd/server. It panics on an invalid or duplicate spec.
+func RegisterAgentType(spec AgentTypeSpec) {
+	if spec.Name == "" || spec.NewConfig == nil || spec.Build == nil {
+		panic("domain: RegisterAgentType requires Name, NewConfig and Build")
+	}
+
+	agentTypesMu.Lock()
+	defer agentTypesMu.Unlock()
+	if _, dup := agentTypes[spec.Name]; dup {
+		panic(fmt.Sprintf("domain: agent type %q registered twice", spec.Name))
+	}
+	agentTypes[spec.Name] = spec
+}
+
+// LookupAgentType returns the registered spec for an agent type.
+func LookupAgentType(name string) (AgentTypeSpec, bool) {
+	agentTypesMu.RLock()
+	defer agentTypesMu.RUnlock()
+	spec, ok := agentTypes[name]
+	return spec, ok
+}
+
+// AgentTypeNames returns the registered agent types, sorted.
+func AgentTypeNames() []string {
+	agentTypesMu.RLock()
+	defer agentTypesMu.RUnlock()
+
+	names := make([]string, 0, len(agentTypes))
+	for name := range agentTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
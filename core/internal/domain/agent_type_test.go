@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type echoConfig struct {
+	Prefix string `yaml:"prefix"`
+	Repeat int    `yaml:"repeat"`
+}
+
+func (c *echoConfig) Validate() error {
+	if c.Repeat < 1 {
+		return fmt.Errorf("repeat must be at least 1")
+	}
+	return nil
+}
+
+type echoAgent struct {
+	id  string
+	cfg echoConfig
+}
+
+func (a *echoAgent) ID() string      { return a.id }
+func (a *echoAgent) Type() AgentType { return AgentTypeExpressor }
+func (a *echoAgent) Execute(ctx context.Context, input CloudEvent) (*CloudEvent, error) {
+	return &input, nil
+}
+
+func TestRegisterAgentType(t *testing.T) {
+	RegisterAgentType(NewAgentType("test-echo", echoConfig{Prefix: ">", Repeat: 1},
+		func(cfg AgentConfig, c echoConfig, deps AgentDeps) (Agent, error) {
+			return &echoAgent{id: cfg.ID, cfg: c}, nil
+		}))
+
+	spec, ok := LookupAgentType("test-echo")
+	if !ok {
+		t.Fatalf("Registered type not found")
+	}
+
+	// Defaults are kept for omitted fields
+	typed, err := spec.DecodeConfig(map[string]interface{}{"repeat": 3})
+	if err != nil {
+		t.Fatalf("DecodeConfig failed: %v", err)
+	}
+	a, err := spec.Build(AgentConfig{ID: "Echo"}, typed, AgentDeps{})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if got := a.(*echoAgent).cfg; got.Prefix != ">" || got.Repeat != 3 {
+		t.Errorf("Unexpected config: %+v", got)
+	}
+
+	if _, err := spec.DecodeConfig(map[string]interface{}{"prefx": "!"}); err == nil || !strings.Contains(err.Error(), "prefx") {
+		t.Errorf("Expected unknown field error, got %v", err)
+	}
+	if _, err := spec.DecodeConfig(map[string]interface{}{"repeat": 0}); err == nil {
+		t.Errorf("Expected Validate to reject repeat 0")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected duplicate registration to panic")
+		}
+	}()
+	RegisterAgentType(spec)
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Alert statuses.
+const (
+	AlertStatusOpen         = "open"
+	AlertStatusAcknowledged = "acknowledged"
+	AlertStatusResolved     = "resolved"
+)
+
+// Events published by the alert service on alert/<id>/<opened|acknowledged|resolved>.
+const (
+	AlertOpenedEventType       = "alert.opened"
+	AlertAcknowledgedEventType = "alert.acknowledged"
+	AlertResolvedEventType     = "alert.resolved"
+)
+
+// SecurityAlertEventType is the alert emitted by TrendScout.
+const SecurityAlertEventType = "swarm.security.alert"
+
+var (
+	ErrAlertNotFound   = errors.New("alert not found")
+	ErrSilenceNotFound = errors.New("silence not found")
+)
+
+// Alert is a deduplicated alert: repeated alert events with the same fingerprint
+// update one Alert until it is resolved.
+type Alert struct {
+	ID          string     `json:"id"`
+	Fingerprint string     `json:"fingerprint"` // Identity for deduplication (agent, source, metric)
+	Group       string     `json:"group"`       // Related alerts share a group (the affected source)
+	Status      string     `json:"status"`
+	Severity    string     `json:"severity"`
+	Agent       string     `json:"agent"`            // Source of the alert event
+	Source      string     `json:"source"`           // Affected source, e.g. a sensor topic
+	Metric      string     `json:"metric,omitempty"` // e.g. "sensor.cpu.temp"
+	EventType   string     `json:"event_type"`       // Type of the alert events
+	Message     string     `json:"message"`          // Latest message
+	Count       int        `json:"count"`            // Alert events received
+	FirstSeen   time.Time  `json:"first_seen"`
+	LastSeen    time.Time  `json:"last_seen"`
+	AckedBy     string     `json:"acked_by,omitempty"`
+	AckedAt     *time.Time `json:"acked_at,omitempty"`
+	ResolvedAt  *time.Time `json:"resolved_at,omitempty"`
+	Silenced    bool       `json:"silenced"` // Matched by an active silence (computed, not stored)
+}
+
+// Silence suppresses notifications for matching alerts until it expires.
+// Every non-empty matcher field must equal the alert's field.
+type Silence struct {
+	ID          string    `json:"id"`
+	Fingerprint string    `json:"fingerprint,omitempty"`
+	Group       string    `json:"group,omitempty"`
+	Agent       string    `json:"agent,omitempty"`
+	Source      string    `json:"source,omitempty"`
+	Metric      string    `json:"metric,omitempty"`
+	Severity    string    `json:"severity,omitempty"`
+	Comment     string    `json:"comment"`
+	CreatedBy   string    `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// Matches reports whether the silence covers the alert at the given time.
+func (s Silence) Matches(a Alert, now time.Time) bool {
+	if !now.Before(s.ExpiresAt) {
+		return false
+	}
+	for _, m := range [][2]string{
+		{s.Fingerprint, a.Fingerprint},
+		{s.Group, a.Group},
+		{s.Agent, a.Agent},
+		{s.Source, a.Source},
+		{s.Metric, a.Metric},
+		{s.Severity, a.Severity},
+	} {
+		if m[0] != "" && m[0] != m[1] {
+			return false
+		}
+	}
+	return true
+}
+
+// AlertStore persists alerts and silences.
+type AlertStore interface {
+	// ActiveAlert returns the open or acknowledged alert with the fingerprint, or nil.
+	ActiveAlert(ctx context.Context, fingerprint string) (*Alert, error)
+	SaveAlert(ctx context.Context, alert Alert) error // Insert or update by ID
+	GetAlert(ctx context.Context, id string) (*Alert, error)
+	// ListAlerts returns alerts by last seen (newest first); an empty status lists all, limit 0 means no limit.
+	ListAlerts(ctx context.Context, status string, limit int) ([]Alert, error)
+
+	SaveSilence(ctx context.Context, silence Silence) error
+	DeleteSilence(ctx context.Context, id string) error
+	// ListSilences returns the silences that have not expired at the given time.
+	ListSilences(ctx context.Context, now time.Time) ([]Silence, error)
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// Events emitted by anomaly detectors.
+const (
+	AnomalyEventType  = "swarm.anomaly.detected"
+	RecoveryEventType = "swarm.anomaly.recovered"
+)
+
+// AnomalyReport is the data of AnomalyEventType and RecoveryEventType events.
+type AnomalyReport struct {
+	Agent      string    `json:"agent"`                // Detector that raised it
+	Source     string    `json:"source"`               // Source of the sensor events
+	Metric     string    `json:"metric"`               // Sensor event type, e.g. "sensor.cpu.temp"
+	Value      float64   `json:"value"`                // Latest reading
+	Severity   string    `json:"severity"`             // e.g. "warning", "critical"
+	Violations []string  `json:"violations,omitempty"` // Rules that fired, e.g. "ewma 91.2 > 85"
+	Message    string    `json:"message"`
+	Since      time.Time `json:"since"` // When the anomaly started
+}
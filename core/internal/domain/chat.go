@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/point-unknown/catalyst/pkg/mcp"
+)
+
+// Chat event types.
+const (
+	ChatMessageEventType = "chat.message" // A message from a user or an agent
+	ChatWakeEventType    = "chat.wake"    // Published on agent/<id>/wake when it is the agent's turn
+	ChatEndedEventType   = "chat.ended"   // The agents stopped talking and the user has the turn again
+)
+
+// DefaultChatSession is the session of messages that don't name one.
+const DefaultChatSession = "default"
+
+// ErrChatSessionNotFound is returned for unknown chat sessions.
+var ErrChatSessionNotFound = errors.New("chat session not found")
+
+// ChatMessage is the data of a ChatMessageEventType event.
+type ChatMessage struct {
+	Session   string         `json:"session,omitempty"` // Chat session (DefaultChatSession when empty)
+	Sender    string         `json:"sender"`
+	Content   string         `json:"content"`
+	Citations []ChatCitation `json:"citations,omitempty"` // Sources the reply is based on
+	Time      time.Time      `json:"time"`                // When the chat manager received it
+	Tokens    int            `json:"tokens,omitempty"`    // Estimated size of "sender: content" in a prompt
+}
+
+// ChatCitation is a retrieved document cited in a reply as [Index].
+type ChatCitation struct {
+	Index int     `json:"index"`
+	Path  string  `json:"path"`
+	Score float32 `json:"score"`
+}
+
+// ChatWake is the data of the event that asks an agent to take its turn in a group chat.
+// The agent's reply must carry the same Session.
+type ChatWake struct {
+	Session     string        `json:"session,omitempty"`
+	TargetAgent string        `json:"target_agent"`
+	UserInput   string        `json:"user_input,omitempty"` // Latest user message
+	Summary     string        `json:"summary,omitempty"`    // Summary of the turns before History
+	History     []ChatMessage `json:"history,omitempty"`    // Most recent turns that fit the agent's budget, oldest first
+}
+
+// ChatToolCall is the data of a ToolCallEventType event an agent emits as its chat turn:
+// the call, plus the session and a note to the user that is recorded as the agent's reply.
+type ChatToolCall struct {
+	mcp.ToolCall
+	Session string `json:"session,omitempty"`
+	Reply   string `json:"reply,omitempty"`
+}
+
+// Reasons a group chat round ends.
+const (
+	ChatEndTerminate   = "terminate"    // An agent said the termination keyword
+	ChatEndMaxTurns    = "max_turns"    // The agents used up their turns
+	ChatEndIdleTimeout = "idle_timeout" // The woken agent did not answer in time
+	ChatEndHandoff     = "handoff"      // Speaker selection gave the turn back to the user
+)
+
+// ChatEnded is the data of a ChatEndedEventType event.
+type ChatEnded struct {
+	Session string `json:"session"`
+	Reason  string `json:"reason"`
+	Turns   int    `json:"turns"` // Agent turns since the last user message
+}
+
+// ChatSession is one conversation of the group chat.
+type ChatSession struct {
+	ID           string    `json:"id"`
+	Title        string    `json:"title"` // The start of the first user message
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"` // Last message
+	MessageCount int       `json:"message_count"`
+	Summary      string    `json:"summary,omitempty"`    // Rolling summary of the oldest messages
+	Summarized   int       `json:"summarized,omitempty"` // How many of the oldest messages Summary covers
+}
+
+// ChatStore persists chat sessions and their messages.
+type ChatStore interface {
+	// SaveChatSession creates or updates a session (MessageCount is ignored).
+	SaveChatSession(ctx context.Context, s ChatSession) error
+	// GetChatSession returns ErrChatSessionNotFound for unknown sessions.
+	GetChatSession(ctx context.Context, id string) (*ChatSession, error)
+	// ListChatSessions returns the most recently updated sessions first.
+	ListChatSessions(ctx context.Context, limit int) ([]ChatSession, error)
+	// AppendChatMessage adds a message to its session and bumps the session's UpdatedAt.
+	AppendChatMessage(ctx context.Context, msg ChatMessage) error
+	// ListChatMessages returns the messages of a session, oldest first.
+	ListChatMessages(ctx context.Context, sessionID string) ([]ChatMessage, error)
+}
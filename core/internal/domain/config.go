@@ -2,18 +2,34 @@ package domain
 
 // AgentConfig represents the configuration for a single agent instance.
 type AgentConfig struct {
-	ID       string                 `yaml:"id"`
-	Type     string                 `yaml:"type"`   // e.g., "trend-scout", "engineer"
-	Config   map[string]interface{} `yaml:"config"` // Agent-specific settings
-	Security SecurityConfig         `yaml:"safety"` // Mandatory Safety Protocol
+	ID          string                 `yaml:"id"`
+	Type        string                 `yaml:"type"`        // e.g., "trend-scout", "engineer"
+	Description string                 `yaml:"description"` // What the agent does (used e.g. to pick chat speakers)
+	Config      map[string]interface{} `yaml:"config"`      // Agent-specific settings
+	Security    SecurityConfig         `yaml:"safety"`      // Mandatory Safety Protocol
 }
 
 // MissionConfig represents the configuration for a mission.
 type MissionConfig struct {
-	ID           string   `yaml:"id"`
-	Name         string   `yaml:"name"`
-	TriggerTopic string   `yaml:"trigger_topic"`
-	Agents       []string `yaml:"agents"`
+	ID           string       `yaml:"id"`
+	Name         string       `yaml:"name"`
+	TriggerTopic string       `yaml:"trigger_topic"`
+	TriggerType  string       `yaml:"trigger_type"`
+	Agents       []string     `yaml:"agents"` // Linear pipeline (legacy form)
+	Steps        []StepConfig `yaml:"steps"`  // DAG form: parallel fan-out and joins via "needs"
+	Defaults     StepPolicy   `yaml:"defaults"`
+	Concurrency  int          `yaml:"concurrency"`
+	QueueDepth   int          `yaml:"queue_depth"`
+}
+
+// StepConfig represents a single node of a DAG mission.
+type StepConfig struct {
+	ID    string   `yaml:"id"`
+	Agent string   `yaml:"agent"`
+	Needs []string `yaml:"needs"`
+	When  string   `yaml:"when"` // e.g. `type == "swarm.security.alert" && data.severity == "critical"`
+
+	StepPolicy `yaml:",inline"` // timeout, retries, backoff, on_error
 }
 
 // SystemConfig is the top-level structure for config/agents.yaml
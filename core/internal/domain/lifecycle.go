@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// HealthStatus is the coarse state reported by an agent.
+type HealthStatus string
+
+const (
+	HealthOK       HealthStatus = "ok"
+	HealthDegraded HealthStatus = "degraded" // Working, but with errors or reduced capacity
+	HealthDown     HealthStatus = "down"     // Cannot execute
+)
+
+// HeartbeatEventType is the type of the events published on agent/<id>/heartbeat.
+const HeartbeatEventType = "agent.heartbeat"
+
+// AgentHealth is a point-in-time health report.
+type AgentHealth struct {
+	Status    HealthStatus `json:"status"`
+	Message   string       `json:"message,omitempty"`
+	CheckedAt time.Time    `json:"checked_at"`
+}
+
+// Lifecycle is optionally implemented by agents that hold resources or run background
+// work. The AgentRegistry calls Start when the agent is registered (a failing Start
+// rejects the agent), Stop when it is replaced, removed or the process shuts down,
+// and Health for the HTTP API and heartbeats. Agents without it are always healthy.
+type Lifecycle interface {
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+	Health(ctx context.Context) AgentHealth
+}
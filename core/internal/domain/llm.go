@@ -1,6 +1,13 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"errors"
+	"unicode/utf8"
+)
+
+// ErrPromptTooLong is returned by providers for prompts that exceed their context window.
+var ErrPromptTooLong = errors.New("prompt exceeds the model's context window")
 
 // LLMProvider defines the contract for any Large Language Model service
 // (e.g., OpenAI, Ollama, Anthropic).
@@ -14,3 +21,9 @@ type LLMProvider interface {
 	// Embed generates a vector embedding for the given text.
 	Embed(ctx context.Context, text string) ([]float32, error)
 }
+
+// EstimateTokens approximates the number of LLM tokens in text (about four characters
+// per token for English and code), for budgeting prompts without a tokenizer.
+func EstimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Mission run / step statuses.
+const (
+	RunStatusRunning   = "running"
+	RunStatusSucceeded = "succeeded"
+	RunStatusFailed    = "failed"
+	RunStatusSkipped   = "skipped"
+)
+
+// MissionRun is the record of a single mission execution.
+type MissionRun struct {
+	ID             string           `json:"id"`
+	MissionID      string           `json:"mission_id"`
+	TriggerEventID string           `json:"trigger_event_id"`
+	TriggerType    string           `json:"trigger_type"`
+	Status         string           `json:"status"`
+	Error          string           `json:"error,omitempty"`
+	StartedAt      time.Time        `json:"started_at"`
+	FinishedAt     time.Time        `json:"finished_at"`
+	Steps          []MissionStepRun `json:"steps"`
+}
+
+// MissionStepRun is the record of one step within a MissionRun.
+type MissionStepRun struct {
+	StepID     string      `json:"step_id"`
+	AgentID    string      `json:"agent_id"` // The agent that produced the result (the fallback agent, if used)
+	Status     string      `json:"status"`
+	Attempts   int         `json:"attempts"`
+	Input      *CloudEvent `json:"input,omitempty"`
+	Output     *CloudEvent `json:"output,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	StartedAt  time.Time   `json:"started_at"`
+	DurationMs int64       `json:"duration_ms"`
+}
+
+// MissionRunStore persists mission execution history.
+type MissionRunStore interface {
+	SaveMissionRun(ctx context.Context, run MissionRun) error
+	ListMissionRuns(ctx context.Context, missionID string, limit int) ([]MissionRun, error)
+}
+
+// ErrQueueFull is returned by MissionQueue.Enqueue when a mission's backlog is at its limit.
+var ErrQueueFull = errors.New("mission queue is full")
+
+// MissionJob is a mission run waiting in (or claimed from) a durable queue.
+type MissionJob struct {
+	ID        string     `json:"id"`
+	MissionID string     `json:"mission_id"`
+	Trigger   CloudEvent `json:"trigger"`
+	Attempts  int        `json:"attempts"`
+}
+
+// MissionQueue is a durable queue of mission runs that can be shared by several core replicas.
+// Claimed runs are leased; a run whose lease expires (e.g. the replica crashed) is handed out again.
+type MissionQueue interface {
+	// Enqueue adds a run. It returns false without error if the trigger is already queued for
+	// the mission (e.g. by another replica), and ErrQueueFull if maxPending runs are waiting.
+	Enqueue(ctx context.Context, missionID string, trigger CloudEvent, maxPending int) (bool, error)
+	// Claim leases the oldest pending or abandoned run of a mission. It returns nil if there is none.
+	Claim(ctx context.Context, missionID string, lease time.Duration) (*MissionJob, error)
+	// Extend renews the lease of a claimed run.
+	Extend(ctx context.Context, jobID string, lease time.Duration) error
+	// Complete marks a claimed run as finished.
+	Complete(ctx context.Context, jobID string) error
+	// Backlog returns the number of runs of a mission waiting for a worker.
+	Backlog(ctx context.Context, missionID string) (int, error)
+	// Prune deletes finished runs of a mission older than the given age.
+	Prune(ctx context.Context, missionID string, olderThan time.Duration) error
+}
@@ -6,10 +6,10 @@ import (
 
 // SecurityConfig defines the restrictions for an Agent's execution environment.
 type SecurityConfig struct {
-	SandboxPath     string   `yaml:"sandbox_path"`     // Absolute path agent is confined to
-	AllowedPatterns []string `yaml:"allowed_patterns"` // e.g. ["*.go", "README.md"]
-	DeniedPatterns  []string `yaml:"denied_patterns"`  // e.g. [".env", "id_rsa"]
-	ReadOnly        bool     `yaml:"read_only"`
+	SandboxPath     string   `yaml:"sandbox_path" json:"sandbox_path,omitempty"`         // Absolute path agent is confined to
+	AllowedPatterns []string `yaml:"allowed_patterns" json:"allowed_patterns,omitempty"` // e.g. ["*.go", "README.md"]
+	DeniedPatterns  []string `yaml:"denied_patterns" json:"denied_patterns,omitempty"`   // e.g. [".env", "id_rsa"]
+	ReadOnly        bool     `yaml:"read_only" json:"read_only"`
 }
 
 // ContextResolver defines how to retrieve the dynamic workspace root
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SensorReading is one numeric sample of a `sensor.*` event.
+type SensorReading struct {
+	Time   time.Time `json:"time"`
+	Type   string    `json:"type"`   // Event type, e.g. "sensor.cpu.temp"
+	Source string    `json:"source"` // Event source, e.g. "device-mock/1"
+	Value  float64   `json:"value"`
+	Unit   string    `json:"unit,omitempty"`
+	Label  string    `json:"label,omitempty"`
+}
+
+// IsSensorEvent reports whether an event type is a sensor reading ("sensor.*").
+func IsSensorEvent(eventType string) bool {
+	return strings.HasPrefix(eventType, "sensor.")
+}
+
+// ParseSensorReading extracts a reading from an event whose data is either
+// `{"value": 45.2, "unit": "C", "label": "CPU"}` or a bare (optionally quoted) number.
+func ParseSensorReading(event CloudEvent) (SensorReading, bool) {
+	reading := SensorReading{Time: event.Time, Type: event.Type, Source: event.Source}
+	if reading.Time.IsZero() {
+		reading.Time = time.Now().UTC()
+	}
+
+	var data struct {
+		Value *float64 `json:"value"`
+		Unit  string   `json:"unit"`
+		Label string   `json:"label"`
+	}
+	if err := json.Unmarshal(event.Data, &data); err == nil && data.Value != nil {
+		reading.Value, reading.Unit, reading.Label = *data.Value, data.Unit, data.Label
+		return reading, true
+	}
+	value, err := strconv.ParseFloat(strings.Trim(string(event.Data), `"`), 64)
+	if err != nil {
+		return SensorReading{}, false
+	}
+	reading.Value = value
+	return reading, true
+}
+
+// Resolutions of stored sensor series: raw readings and their rollups.
+const (
+	ResolutionRaw    = "raw"
+	ResolutionMinute = "1m"
+	ResolutionHour   = "1h"
+)
+
+// SeriesQuery selects an aggregated sensor series.
+type SeriesQuery struct {
+	Type       string
+	Source     string // Optional; all sources are aggregated when empty
+	From, To   time.Time
+	Step       time.Duration // Bucket width, a multiple of the resolution
+	Resolution string        // Which table to read: ResolutionRaw, ResolutionMinute or ResolutionHour
+}
+
+// SeriesPoint is one bucket of a sensor series.
+type SeriesPoint struct {
+	Time  time.Time `json:"time"` // Bucket start
+	Min   float64   `json:"min"`
+	Max   float64   `json:"max"`
+	Avg   float64   `json:"avg"`
+	Count int64     `json:"count"`
+}
+
+// SensorStore persists sensor readings and their rollups.
+type SensorStore interface {
+	SaveSensorReading(ctx context.Context, reading SensorReading) error
+	// QuerySensorSeries returns the buckets in [From, To) that have data, oldest first.
+	QuerySensorSeries(ctx context.Context, q SeriesQuery) ([]SeriesPoint, error)
+	// RollupSensorReadings (re)computes the rollups of the given resolution for buckets
+	// starting at or after since. It is idempotent.
+	RollupSensorReadings(ctx context.Context, resolution string, since time.Time) error
+	// PruneSensorReadings deletes data of the given resolution older than before.
+	PruneSensorReadings(ctx context.Context, resolution string, before time.Time) error
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// AgentState is a small key/value state API scoped to one agent, for agents that keep
+// data between executions (e.g. a moving window per event source). Values are JSON
+// documents. Update is atomic per key, so agents stay race-free when the mission
+// executor runs them concurrently.
+type AgentState interface {
+	// Get returns the value stored under key, or nil if there is none.
+	Get(ctx context.Context, key string) (json.RawMessage, error)
+	// Update atomically replaces the value under key with fn(current). current is nil if unset.
+	Update(ctx context.Context, key string, fn func(current json.RawMessage) (json.RawMessage, error)) error
+}
+
+// StateProvider hands out the state of each agent.
+type StateProvider interface {
+	For(agentID string) AgentState
+}
+
+// AgentStateStore persists agent state so it survives restarts.
+type AgentStateStore interface {
+	// LoadAgentState returns the stored value, or nil if there is none.
+	LoadAgentState(ctx context.Context, agentID, key string) (json.RawMessage, error)
+	SaveAgentState(ctx context.Context, agentID, key string, value json.RawMessage) error
+}
+
+// UpdateState is a typed helper around AgentState.Update: the current value is decoded
+// into a T (zero value if unset), modified in place by fn, and stored back.
+func UpdateState[T any](ctx context.Context, state AgentState, key string, fn func(value *T) error) error {
+	return state.Update(ctx, key, func(current json.RawMessage) (json.RawMessage, error) {
+		var value T
+		if len(current) > 0 {
+			if err := json.Unmarshal(current, &value); err != nil {
+				return nil, err
+			}
+		}
+		if err := fn(&value); err != nil {
+			return nil, err
+		}
+		return json.Marshal(value)
+	})
+}
@@ -0,0 +1,377 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidSilence is returned for silences without matchers or with a past expiry.
+var ErrInvalidSilence = errors.New("invalid silence")
+
+// AlertQueueDepth bounds the alert events submitted but not yet ingested by Run.
+const AlertQueueDepth = 256
+
+// AlertService turns alert events from agents into managed alerts: events with the
+// same fingerprint are deduplicated into one alert, which stays open until it is
+// resolved (by a recovery event or the API). Only state changes are published, on
+// alert/<id>/<opened|acknowledged|resolved>; silenced alerts are tracked but not published.
+type AlertService struct {
+	store   domain.AlertStore
+	publish func(topic string, event domain.CloudEvent)
+	now     func() time.Time
+	queue   chan domain.CloudEvent // Submitted events, ingested in order by Run
+
+	mu sync.Mutex // Serializes ingestion so concurrent events cannot open duplicates
+}
+
+// AlertGroup is a set of active alerts sharing a group.
+type AlertGroup struct {
+	Group    string         `json:"group"`
+	Severity string         `json:"severity"` // Highest severity in the group
+	Count    int            `json:"count"`    // Active alerts
+	Alerts   []domain.Alert `json:"alerts"`
+}
+
+// alertPayload is the data of alert events (TrendScout alerts and domain.AnomalyReport).
+type alertPayload struct {
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Source   string `json:"source"`
+	Metric   string `json:"metric"`
+}
+
+func NewAlertService(store domain.AlertStore, publisher func(topic string, event domain.CloudEvent)) *AlertService {
+	return &AlertService{
+		store:   store,
+		publish: publisher,
+		now:     func() time.Time { return time.Now().UTC() },
+		queue:   make(chan domain.CloudEvent, AlertQueueDepth),
+	}
+}
+
+// Submit queues an alert event for Run without waiting for the store, so it can be
+// called from the EventBus. It returns false if the queue is full and the event was dropped.
+func (s *AlertService) Submit(event domain.CloudEvent) bool {
+	select {
+	case s.queue <- event:
+		return true
+	default:
+		log.Printf("[ALERT] Queue full (depth %d). Dropping %s from %s", cap(s.queue), event.Type, event.Source)
+		return false
+	}
+}
+
+// Run ingests submitted events one at a time, in order, until ctx is cancelled.
+func (s *AlertService) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-s.queue:
+			ingestCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			if err := s.Ingest(ingestCtx, event); err != nil {
+				log.Printf("[ALERT] Failed to ingest %s from %s: %v", event.Type, event.Source, err)
+			}
+			cancel()
+		}
+	}
+}
+
+// Handles reports whether an event type is ingested by the alert service.
+func (s *AlertService) Handles(eventType string) bool {
+	switch eventType {
+	case domain.SecurityAlertEventType, domain.AnomalyEventType, domain.RecoveryEventType:
+		return true
+	}
+	return false
+}
+
+// Ingest records an alert event. Recovery events resolve the matching alert.
+func (s *AlertService) Ingest(ctx context.Context, event domain.CloudEvent) error {
+	if !s.Handles(event.Type) {
+		return nil
+	}
+	var payload alertPayload
+	if len(event.Data) > 0 {
+		if err := json.Unmarshal(event.Data, &payload); err != nil {
+			return fmt.Errorf("invalid alert data: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fingerprint := Fingerprint(event.Source, payload.Source, payload.Metric)
+	active, err := s.store.ActiveAlert(ctx, fingerprint)
+	if err != nil {
+		return err
+	}
+	now := s.now()
+
+	if event.Type == domain.RecoveryEventType {
+		if active == nil {
+			return nil
+		}
+		if payload.Message != "" {
+			active.Message = payload.Message
+		}
+		return s.resolve(ctx, active, now)
+	}
+
+	if active != nil {
+		// Duplicate: update the existing alert without notifying
+		active.Count++
+		active.LastSeen = now
+		if payload.Message != "" {
+			active.Message = payload.Message
+		}
+		if payload.Severity != "" {
+			active.Severity = payload.Severity
+		}
+		return s.store.SaveAlert(ctx, *active)
+	}
+
+	alert := domain.Alert{
+		ID:          uuid.New().String(),
+		Fingerprint: fingerprint,
+		Group:       payload.Source,
+		Status:      domain.AlertStatusOpen,
+		Severity:    payload.Severity,
+		Agent:       event.Source,
+		Source:      payload.Source,
+		Metric:      payload.Metric,
+		EventType:   event.Type,
+		Message:     payload.Message,
+		Count:       1,
+		FirstSeen:   now,
+		LastSeen:    now,
+	}
+	if alert.Group == "" {
+		alert.Group = alert.Agent
+	}
+	if alert.Severity == "" {
+		alert.Severity = "warning"
+	}
+	if err := s.store.SaveAlert(ctx, alert); err != nil {
+		return err
+	}
+
+	log.Printf("[ALERT] Opened %s (%s): %s", alert.ID, alert.Severity, alert.Message)
+	s.notify(ctx, alert, "opened", domain.AlertOpenedEventType)
+	return nil
+}
+
+// Fingerprint identifies an alert: the emitting agent, the affected source and the metric.
+func Fingerprint(agent, source, metric string) string {
+	sum := sha256.Sum256([]byte(agent + "\x00" + source + "\x00" + metric))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Get returns an alert by ID.
+func (s *AlertService) Get(ctx context.Context, id string) (*domain.Alert, error) {
+	alert, err := s.store.GetAlert(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	alerts := []domain.Alert{*alert}
+	if err := s.annotate(ctx, alerts); err != nil {
+		return nil, err
+	}
+	return &alerts[0], nil
+}
+
+// List returns alerts by status (empty for all), newest first.
+func (s *AlertService) List(ctx context.Context, status string, limit int) ([]domain.Alert, error) {
+	alerts, err := s.store.ListAlerts(ctx, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.annotate(ctx, alerts); err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+// Groups returns the open and acknowledged alerts grouped by Group, most severe first.
+func (s *AlertService) Groups(ctx context.Context) ([]AlertGroup, error) {
+	var active []domain.Alert
+	for _, status := range []string{domain.AlertStatusOpen, domain.AlertStatusAcknowledged} {
+		alerts, err := s.List(ctx, status, 0)
+		if err != nil {
+			return nil, err
+		}
+		active = append(active, alerts...)
+	}
+
+	index := make(map[string]int)
+	groups := make([]AlertGroup, 0)
+	for _, a := range active {
+		i, ok := index[a.Group]
+		if !ok {
+			i = len(groups)
+			index[a.Group] = i
+			groups = append(groups, AlertGroup{Group: a.Group, Severity: a.Severity})
+		}
+		g := &groups[i]
+		g.Alerts = append(g.Alerts, a)
+		g.Count++
+		if severityRank(a.Severity) > severityRank(g.Severity) {
+			g.Severity = a.Severity
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if ri, rj := severityRank(groups[i].Severity), severityRank(groups[j].Severity); ri != rj {
+			return ri > rj
+		}
+		return groups[i].Group < groups[j].Group
+	})
+	return groups, nil
+}
+
+// Acknowledge marks an open alert as being handled. Acknowledged alerts keep
+// deduplicating events until they are resolved.
+func (s *AlertService) Acknowledge(ctx context.Context, id, by string) (*domain.Alert, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	alert, err := s.store.GetAlert(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if alert.Status != domain.AlertStatusOpen {
+		return alert, nil
+	}
+
+	now := s.now()
+	alert.Status = domain.AlertStatusAcknowledged
+	alert.AckedBy = by
+	alert.AckedAt = &now
+	if err := s.store.SaveAlert(ctx, *alert); err != nil {
+		return nil, err
+	}
+	s.notify(ctx, *alert, "acknowledged", domain.AlertAcknowledgedEventType)
+	return alert, nil
+}
+
+// Resolve closes an alert; the next event with its fingerprint opens a new one.
+func (s *AlertService) Resolve(ctx context.Context, id string) (*domain.Alert, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	alert, err := s.store.GetAlert(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if alert.Status == domain.AlertStatusResolved {
+		return alert, nil
+	}
+	if err := s.resolve(ctx, alert, s.now()); err != nil {
+		return nil, err
+	}
+	return alert, nil
+}
+
+// resolve closes an active alert. Callers hold s.mu.
+func (s *AlertService) resolve(ctx context.Context, alert *domain.Alert, now time.Time) error {
+	alert.Status = domain.AlertStatusResolved
+	alert.ResolvedAt = &now
+	if err := s.store.SaveAlert(ctx, *alert); err != nil {
+		return err
+	}
+	log.Printf("[ALERT] Resolved %s", alert.ID)
+	s.notify(ctx, *alert, "resolved", domain.AlertResolvedEventType)
+	return nil
+}
+
+// Silences returns the silences that have not expired.
+func (s *AlertService) Silences(ctx context.Context) ([]domain.Silence, error) {
+	return s.store.ListSilences(ctx, s.now())
+}
+
+// Silence creates a silence. It needs at least one matcher and an expiry in the future.
+func (s *AlertService) Silence(ctx context.Context, silence domain.Silence) (domain.Silence, error) {
+	now := s.now()
+	if silence.Fingerprint == "" && silence.Group == "" && silence.Agent == "" &&
+		silence.Source == "" && silence.Metric == "" && silence.Severity == "" {
+		return silence, fmt.Errorf("%w: at least one matcher is required", ErrInvalidSilence)
+	}
+	if !silence.ExpiresAt.After(now) {
+		return silence, fmt.Errorf("%w: expires_at must be in the future", ErrInvalidSilence)
+	}
+
+	silence.ID = uuid.New().String()
+	silence.CreatedAt = now
+	if err := s.store.SaveSilence(ctx, silence); err != nil {
+		return silence, err
+	}
+	return silence, nil
+}
+
+// Unsilence deletes a silence before it expires.
+func (s *AlertService) Unsilence(ctx context.Context, id string) error {
+	return s.store.DeleteSilence(ctx, id)
+}
+
+// annotate sets Silenced on alerts matched by an active silence.
+func (s *AlertService) annotate(ctx context.Context, alerts []domain.Alert) error {
+	now := s.now()
+	silences, err := s.store.ListSilences(ctx, now)
+	if err != nil {
+		return err
+	}
+	for i := range alerts {
+		alerts[i].Silenced = false
+		for _, sil := range silences {
+			if sil.Matches(alerts[i], now) {
+				alerts[i].Silenced = true
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// notify publishes an alert state change unless the alert is silenced.
+func (s *AlertService) notify(ctx context.Context, alert domain.Alert, change, eventType string) {
+	if s.publish == nil {
+		return
+	}
+	alerts := []domain.Alert{alert}
+	if err := s.annotate(ctx, alerts); err != nil {
+		log.Printf("[ALERT] Failed to check silences for %s: %v", alert.ID, err)
+	}
+	if alerts[0].Silenced {
+		log.Printf("[ALERT] %s is silenced, not publishing %s", alert.ID, change)
+		return
+	}
+
+	event, err := domain.NewEvent("core/alerts", eventType, alerts[0])
+	if err != nil {
+		return
+	}
+	s.publish("alert/"+alert.ID+"/"+change, event)
+}
+
+func severityRank(severity string) int {
+	switch severity {
+	case "critical":
+		return 3
+	case "warning":
+		return 2
+	case "info":
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+)
+
+// MemoryAlertStore is an in-memory domain.AlertStore.
+type MemoryAlertStore struct {
+	mu       sync.Mutex
+	alerts   map[string]domain.Alert
+	silences map[string]domain.Silence
+}
+
+func NewMemoryAlertStore() *MemoryAlertStore {
+	return &MemoryAlertStore{alerts: make(map[string]domain.Alert), silences: make(map[string]domain.Silence)}
+}
+
+func (s *MemoryAlertStore) ActiveAlert(_ context.Context, fingerprint string) (*domain.Alert, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, a := range s.alerts {
+		if a.Fingerprint == fingerprint && a.Status != domain.AlertStatusResolved {
+			return &a, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *MemoryAlertStore) SaveAlert(_ context.Context, alert domain.Alert) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.alerts[alert.ID] = alert
+	return nil
+}
+
+func (s *MemoryAlertStore) GetAlert(_ context.Context, id string) (*domain.Alert, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.alerts[id]
+	if !ok {
+		return nil, domain.ErrAlertNotFound
+	}
+	return &a, nil
+}
+
+func (s *MemoryAlertStore) ListAlerts(_ context.Context, status string, limit int) ([]domain.Alert, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := make([]domain.Alert, 0)
+	for _, a := range s.alerts {
+		if status == "" || a.Status == status {
+			list = append(list, a)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].LastSeen.After(list[j].LastSeen) })
+	if limit > 0 && len(list) > limit {
+		list = list[:limit]
+	}
+	return list, nil
+}
+
+func (s *MemoryAlertStore) SaveSilence(_ context.Context, silence domain.Silence) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.silences[silence.ID] = silence
+	return nil
+}
+
+func (s *MemoryAlertStore) DeleteSilence(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.silences[id]; !ok {
+		return domain.ErrSilenceNotFound
+	}
+	delete(s.silences, id)
+	return nil
+}
+
+func (s *MemoryAlertStore) ListSilences(_ context.Context, now time.Time) ([]domain.Silence, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := make([]domain.Silence, 0)
+	for _, sil := range s.silences {
+		if now.Before(sil.ExpiresAt) {
+			list = append(list, sil)
+		}
+	}
+	return list, nil
+}
+
+// alertHarness wires an AlertService to a memory store, a recording publisher and a fake clock.
+type alertHarness struct {
+	svc       *AlertService
+	store     *MemoryAlertStore
+	mu        sync.Mutex
+	published []string // Topics
+	clock     time.Time
+}
+
+func newAlertHarness() *alertHarness {
+	h := &alertHarness{store: NewMemoryAlertStore(), clock: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	h.svc = NewAlertService(h.store, func(topic string, _ domain.CloudEvent) {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.published = append(h.published, topic)
+	})
+	h.svc.now = func() time.Time { return h.clock }
+	return h
+}
+
+func (h *alertHarness) ingest(t *testing.T, eventType, source, metric string) {
+	t.Helper()
+	h.clock = h.clock.Add(time.Second)
+	event, _ := domain.NewEvent("agent/Detector", eventType, map[string]interface{}{
+		"severity": "critical",
+		"message":  "too hot",
+		"source":   source,
+		"metric":   metric,
+	})
+	if err := h.svc.Ingest(context.Background(), event); err != nil {
+		t.Fatalf("ingest failed: %v", err)
+	}
+}
+
+func (h *alertHarness) topics() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.published...)
+}
+
+func TestAlertService_Deduplicates(t *testing.T) {
+	h := newAlertHarness()
+	for i := 0; i < 5; i++ {
+		h.ingest(t, domain.AnomalyEventType, "sensor/cpu", "sensor.temp")
+	}
+
+	alerts, _ := h.svc.List(context.Background(), "", 0)
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %d", len(alerts))
+	}
+	if alerts[0].Count != 5 || alerts[0].Status != domain.AlertStatusOpen {
+		t.Errorf("unexpected alert: %+v", alerts[0])
+	}
+	if topics := h.topics(); len(topics) != 1 || topics[0] != "alert/"+alerts[0].ID+"/opened" {
+		t.Errorf("expected a single opened notification, got %v", topics)
+	}
+}
+
+func TestAlertService_SubmitIngestsInOrder(t *testing.T) {
+	h := newAlertHarness()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go h.svc.Run(ctx)
+
+	for _, eventType := range []string{domain.AnomalyEventType, domain.RecoveryEventType, domain.AnomalyEventType} {
+		event, _ := domain.NewEvent("agent/Detector", eventType, map[string]interface{}{"source": "sensor/cpu", "metric": "sensor.temp"})
+		if !h.svc.Submit(event) {
+			t.Fatalf("Submit dropped %s", eventType)
+		}
+	}
+	waitFor(t, func() bool { return len(h.topics()) == 3 })
+
+	open, _ := h.svc.List(context.Background(), domain.AlertStatusOpen, 0)
+	resolved, _ := h.svc.List(context.Background(), domain.AlertStatusResolved, 0)
+	if len(open) != 1 || len(resolved) != 1 {
+		t.Errorf("expected the recovery to resolve the first alert and the last event to reopen it, got %d open, %d resolved", len(open), len(resolved))
+	}
+}
+
+func TestAlertService_RecoveryResolvesAndReopens(t *testing.T) {
+	h := newAlertHarness()
+	h.ingest(t, domain.AnomalyEventType, "sensor/cpu", "sensor.temp")
+	h.ingest(t, domain.RecoveryEventType, "sensor/cpu", "sensor.temp")
+
+	resolved, _ := h.svc.List(context.Background(), domain.AlertStatusResolved, 0)
+	if len(resolved) != 1 || resolved[0].ResolvedAt == nil {
+		t.Fatalf("expected the alert to be resolved, got %+v", resolved)
+	}
+
+	// The same condition later is a new incident
+	h.ingest(t, domain.AnomalyEventType, "sensor/cpu", "sensor.temp")
+	open, _ := h.svc.List(context.Background(), domain.AlertStatusOpen, 0)
+	if len(open) != 1 || open[0].ID == resolved[0].ID {
+		t.Fatalf("expected a new open alert, got %+v", open)
+	}
+	if n := len(h.topics()); n != 3 {
+		t.Errorf("expected opened, resolved, opened; got %v", h.topics())
+	}
+}
+
+func TestAlertService_AcknowledgeKeepsDeduplicating(t *testing.T) {
+	h := newAlertHarness()
+	ctx := context.Background()
+	h.ingest(t, domain.SecurityAlertEventType, "sensor/cpu", "sensor.temp")
+	alerts, _ := h.svc.List(ctx, "", 0)
+
+	acked, err := h.svc.Acknowledge(ctx, alerts[0].ID, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acked.Status != domain.AlertStatusAcknowledged || acked.AckedBy != "alice" || acked.AckedAt == nil {
+		t.Errorf("unexpected acknowledged alert: %+v", acked)
+	}
+
+	h.ingest(t, domain.SecurityAlertEventType, "sensor/cpu", "sensor.temp")
+	got, _ := h.svc.Get(ctx, alerts[0].ID)
+	if got.Status != domain.AlertStatusAcknowledged || got.Count != 2 {
+		t.Errorf("expected the acknowledged alert to absorb the event, got %+v", got)
+	}
+
+	if _, err := h.svc.Acknowledge(ctx, "missing", "alice"); err != domain.ErrAlertNotFound {
+		t.Errorf("expected ErrAlertNotFound, got %v", err)
+	}
+}
+
+func TestAlertService_Silences(t *testing.T) {
+	h := newAlertHarness()
+	ctx := context.Background()
+
+	if _, err := h.svc.Silence(ctx, domain.Silence{ExpiresAt: h.clock.Add(time.Hour)}); err == nil {
+		t.Error("expected a silence without matchers to be rejected")
+	}
+	if _, err := h.svc.Silence(ctx, domain.Silence{Source: "sensor/gpu", ExpiresAt: h.clock}); err == nil {
+		t.Error("expected an expired silence to be rejected")
+	}
+
+	if _, err := h.svc.Silence(ctx, domain.Silence{Source: "sensor/gpu", ExpiresAt: h.clock.Add(time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+	h.ingest(t, domain.AnomalyEventType, "sensor/gpu", "sensor.temp")
+	h.ingest(t, domain.AnomalyEventType, "sensor/cpu", "sensor.temp")
+
+	if topics := h.topics(); len(topics) != 1 {
+		t.Errorf("expected only the unsilenced alert to be published, got %v", topics)
+	}
+	alerts, _ := h.svc.List(ctx, "", 0)
+	for _, a := range alerts {
+		if a.Silenced != (a.Source == "sensor/gpu") {
+			t.Errorf("alert %s: silenced=%v", a.Source, a.Silenced)
+		}
+	}
+
+	// Once the silence expires the alert is no longer marked
+	h.clock = h.clock.Add(time.Hour)
+	alerts, _ = h.svc.List(ctx, "", 0)
+	for _, a := range alerts {
+		if a.Silenced {
+			t.Errorf("alert %s still silenced after expiry", a.Source)
+		}
+	}
+}
+
+func TestAlertService_Groups(t *testing.T) {
+	h := newAlertHarness()
+	ctx := context.Background()
+	h.ingest(t, domain.AnomalyEventType, "sensor/cpu", "sensor.temp")
+	h.ingest(t, domain.AnomalyEventType, "sensor/cpu", "sensor.load") // Same group, different fingerprint
+	h.ingest(t, domain.AnomalyEventType, "sensor/disk", "sensor.temp")
+
+	groups, err := h.svc.Groups(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %+v", groups)
+	}
+	if groups[0].Group != "sensor/cpu" || groups[0].Count != 2 {
+		t.Errorf("unexpected first group: %+v", groups[0])
+	}
+}
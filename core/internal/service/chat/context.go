@@ -0,0 +1,213 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+)
+
+// DefaultHistoryTokens is the token budget of the conversation sent to an agent.
+const DefaultHistoryTokens = 2000
+
+// minSummaryTokens is the smallest share of a budget given to the summary.
+const minSummaryTokens = 32
+
+// promptMargin leaves room for what the provider adds to a prompt (its system message).
+const promptMargin = 64
+
+// Summarizer condenses older chat turns into a rolling summary.
+type Summarizer interface {
+	// Summarize folds messages into the previous summary, in about maxTokens tokens.
+	Summarize(ctx context.Context, previous string, messages []domain.ChatMessage, maxTokens int) (string, error)
+}
+
+// ChunkedSummarizer is a Summarizer that can only take so many messages per call.
+// Longer histories are folded one chunk at a time.
+type ChunkedSummarizer interface {
+	Summarizer
+	// Fit returns how many of the first messages one Summarize call can take (at least one).
+	Fit(previous string, messages []domain.ChatMessage, maxTokens int) int
+}
+
+// LLMSummarizer asks the LLM to update the summary.
+type LLMSummarizer struct {
+	LLM             domain.LLMProvider
+	MaxPromptTokens int // The provider's prompt limit, see ChunkedSummarizer (0: no limit)
+}
+
+// Fit implements ChunkedSummarizer.
+func (s *LLMSummarizer) Fit(previous string, messages []domain.ChatMessage, maxTokens int) int {
+	if s.MaxPromptTokens <= 0 {
+		return len(messages)
+	}
+	used := domain.EstimateTokens(s.prompt(previous, nil, maxTokens)) + promptMargin
+	for i, msg := range messages {
+		used += domain.EstimateTokens(line(msg))
+		if used > s.MaxPromptTokens {
+			return max(i, 1)
+		}
+	}
+	return len(messages)
+}
+
+func (s *LLMSummarizer) Summarize(ctx context.Context, previous string, messages []domain.ChatMessage, maxTokens int) (string, error) {
+	// A single message may still be too long: clip it to what is left of the prompt
+	if s.MaxPromptTokens > 0 {
+		room := max(s.MaxPromptTokens-promptMargin-domain.EstimateTokens(s.prompt(previous, nil, maxTokens)), minSummaryTokens)
+		clipped := make([]domain.ChatMessage, len(messages))
+		for i, msg := range messages {
+			if domain.EstimateTokens(line(msg)) > room {
+				msg.Content = clip(msg.Content, room-domain.EstimateTokens(msg.Sender+": ")-1)
+			}
+			clipped[i] = msg
+		}
+		messages = clipped
+	}
+
+	summary, err := s.LLM.GenerateCode(ctx, s.prompt(previous, messages, maxTokens))
+	if err != nil {
+		return "", err
+	}
+	summary = strings.TrimSpace(summary)
+	if summary == "" {
+		return "", fmt.Errorf("empty summary")
+	}
+	// Models overshoot: keep the summary within its share of the budget
+	if domain.EstimateTokens(summary) > maxTokens {
+		summary = clip(summary, maxTokens)
+	}
+	return summary, nil
+}
+
+func (s *LLMSummarizer) prompt(previous string, messages []domain.ChatMessage, maxTokens int) string {
+	var b strings.Builder
+	b.WriteString("You keep the running summary of a group chat between a user and software agents.\n")
+	b.WriteString("Update the summary with the new messages. Keep decisions, open questions, requested actions, ")
+	b.WriteString("names, file paths and numbers; drop greetings and repetition.\n")
+	fmt.Fprintf(&b, "Answer with ONLY the updated summary, in at most %d words.\n", maxTokens*3/4)
+
+	b.WriteString("\n## Summary so far\n")
+	if previous == "" {
+		previous = "(empty)"
+	}
+	b.WriteString(previous)
+	b.WriteString("\n\n## New messages\n")
+	for _, msg := range messages {
+		b.WriteString(line(msg))
+	}
+	return b.String()
+}
+
+// line renders a message in the summary prompt.
+func line(msg domain.ChatMessage) string {
+	return fmt.Sprintf("%s: %s\n", msg.Sender, msg.Content)
+}
+
+// clip shortens text to about tokens tokens.
+func clip(text string, tokens int) string {
+	r := []rune(text)
+	if n := max(tokens*4-4, 0); len(r) > n {
+		return strings.TrimSpace(string(r[:n])) + "…"
+	}
+	return text
+}
+
+// budget returns the history token budget of an agent.
+func (m *GroupChatManager) budget(agentID string) int {
+	if n, ok := m.cfg.AgentHistoryTokens[agentID]; ok && n > 0 {
+		return n
+	}
+	return m.cfg.HistoryTokens
+}
+
+// window returns what an agent is sent of a session: the rolling summary and the most
+// recent messages within the agent's budget. When older messages no longer fit, they
+// are left out and folded into the summary in the background; later wakes carry the new
+// summary once it is ready.
+func (m *GroupChatManager) window(ctx context.Context, s *session, agentID string) (string, []domain.ChatMessage) {
+	budget := m.budget(agentID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	summary, covered := s.info.Summary, s.info.Summarized
+	pending := append([]domain.ChatMessage(nil), s.history[min(covered, len(s.history)):]...)
+
+	keep := fit(pending, budget-domain.EstimateTokens(summary))
+	if keep < len(pending) && m.cfg.Summarizer != nil && !s.summarizing {
+		// Keep only half the budget of recent messages out of the summary, so that the
+		// next few turns fit without summarizing again
+		summaryTokens := max(budget/4, minSummaryTokens)
+		older := pending[:len(pending)-fit(pending, (budget-summaryTokens)/2)]
+		s.summarizing = true
+		go m.summarize(ctx, s, summary, covered, older, summaryTokens)
+	}
+	return summary, pending[len(pending)-keep:]
+}
+
+// summarize folds older messages into a session's summary, in chunks when the summarizer
+// has a prompt limit. The summary is updated after every chunk, so a later failure keeps
+// the progress made. Without a summarizer (or if it fails) the rest stay left out of the wakes.
+func (m *GroupChatManager) summarize(ctx context.Context, s *session, previous string, covered int, older []domain.ChatMessage, maxTokens int) {
+	for len(older) > 0 {
+		n := len(older)
+		if chunked, ok := m.cfg.Summarizer.(ChunkedSummarizer); ok {
+			n = min(max(chunked.Fit(previous, older, maxTokens), 1), n)
+		}
+		next, err := m.cfg.Summarizer.Summarize(ctx, previous, older[:n], maxTokens)
+
+		s.mu.Lock()
+		if err != nil {
+			s.mu.Unlock()
+			m.logger.Warn("Failed to summarize chat history, leaving older turns out", "session", s.info.ID, "error", err)
+			break
+		}
+		if s.info.Summarized != covered {
+			s.mu.Unlock()
+			break
+		}
+		s.info.Summary, s.info.Summarized = next, covered+n
+		s.changed = true
+		if m.cfg.Store != nil {
+			if err := m.flushLocked(ctx, s); err != nil {
+				m.logger.Warn("Failed to persist chat summary", "session", s.info.ID, "error", err)
+			}
+		}
+		m.logger.Info("Summarized chat history", "session", s.info.ID, "messages", n, "summary_tokens", domain.EstimateTokens(next))
+		s.mu.Unlock()
+
+		previous, covered, older = next, covered+n, older[n:]
+	}
+
+	s.mu.Lock()
+	s.summarizing = false
+	active, id := s.active, s.info.ID
+	s.mu.Unlock()
+
+	// The session was kept in memory while its summary was pending
+	if !active && m.cfg.Store != nil {
+		m.evict(id)
+	}
+}
+
+// fit returns how many of the newest messages fit in budget tokens. The newest
+// message is always kept.
+func fit(messages []domain.ChatMessage, budget int) int {
+	used := 0
+	for i := len(messages) - 1; i >= 0; i-- {
+		used += messageTokens(messages[i])
+		if used > budget && i < len(messages)-1 {
+			return len(messages) - 1 - i
+		}
+	}
+	return len(messages)
+}
+
+// messageTokens is the estimated prompt size of a message, as an agent renders it.
+func messageTokens(msg domain.ChatMessage) int {
+	if msg.Tokens > 0 {
+		return msg.Tokens
+	}
+	return domain.EstimateTokens(msg.Sender + ": " + msg.Content)
+}
@@ -0,0 +1,266 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+)
+
+// countingSummarizer records its calls and summarizes by counting messages.
+type countingSummarizer struct {
+	calls  int
+	folded int
+	err    error
+}
+
+func (s *countingSummarizer) Summarize(_ context.Context, previous string, messages []domain.ChatMessage, maxTokens int) (string, error) {
+	s.calls++
+	if s.err != nil {
+		return "", s.err
+	}
+	s.folded += len(messages)
+	return fmt.Sprintf("%d earlier messages", s.folded), nil
+}
+
+func TestFit(t *testing.T) {
+	msgs := []domain.ChatMessage{{Tokens: 10}, {Tokens: 10}, {Tokens: 10}}
+	cases := []struct{ budget, want int }{{30, 3}, {29, 2}, {10, 1}, {0, 1}, {-5, 1}}
+	for _, c := range cases {
+		if got := fit(msgs, c.budget); got != c.want {
+			t.Errorf("fit(%d) = %d, want %d", c.budget, got, c.want)
+		}
+	}
+	if got := fit(nil, 10); got != 0 {
+		t.Errorf("fit(nil) = %d", got)
+	}
+}
+
+// converse runs rounds of a user question and a Liaison answer and returns the last wake.
+func converse(t *testing.T, m *GroupChatManager, rec *recorder, rounds int) domain.ChatWake {
+	t.Helper()
+	for i := 0; i < rounds; i++ {
+		question := fmt.Sprintf("Question %d: %s", i, strings.Repeat("x", 70))
+		if err := m.HandleUserMessage(ctx, domain.ChatMessage{Session: "s", Sender: "user", Content: question}); err != nil {
+			t.Fatal(err)
+		}
+		settle(t, m)
+		if err := m.ProcessAgentResponse(ctx, chatEvent(t, "Liaison", domain.ChatMessage{Session: "s", Content: "ok"})); err != nil {
+			t.Fatal(err)
+		}
+		settle(t, m)
+	}
+	var wake domain.ChatWake
+	if err := json.Unmarshal(rec.last(t, "agent/Liaison/wake").Data, &wake); err != nil {
+		t.Fatal(err)
+	}
+	return wake
+}
+
+func wakeTokens(w domain.ChatWake) int {
+	n := domain.EstimateTokens(w.Summary)
+	for _, msg := range w.History {
+		n += messageTokens(msg)
+	}
+	return n
+}
+
+func TestGroupChat_SummarizesOlderTurns(t *testing.T) {
+	store := NewMemoryChatStore()
+	sum := &countingSummarizer{}
+	rec := &recorder{}
+	m := NewGroupChatManager(slog.Default(), rec.publish, Config{HistoryTokens: 100, Summarizer: sum, Store: store})
+
+	wake := converse(t, m, rec, 10)
+	if sum.calls == 0 || wake.Summary == "" {
+		t.Fatalf("expected a summary after 10 rounds, got %d calls, %+v", sum.calls, wake)
+	}
+	if sum.calls >= 9 {
+		t.Errorf("summarized on almost every turn (%d calls)", sum.calls)
+	}
+	if n := wakeTokens(wake); n > 100 {
+		t.Errorf("wake uses %d tokens, budget 100", n)
+	}
+	if last := wake.History[len(wake.History)-1]; !strings.HasPrefix(last.Content, "Question 9") {
+		t.Errorf("the latest message is missing: %+v", last)
+	}
+
+	// The summary survives restarts, and covers exactly what History leaves out
+	cs, err := store.GetChatSession(ctx, "s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cs.Summary != wake.Summary || cs.Summarized != sum.folded {
+		t.Errorf("stored summary %q covering %d, want %q covering %d", cs.Summary, cs.Summarized, wake.Summary, sum.folded)
+	}
+	// (the session also holds the reply to the last wake)
+	if got := cs.Summarized + len(wake.History); got != cs.MessageCount-1 {
+		t.Errorf("summary (%d) + history (%d) = %d messages, wake was sent after %d", cs.Summarized, len(wake.History), got, cs.MessageCount-1)
+	}
+}
+
+// gatedSummarizer blocks every summary until release is closed.
+type gatedSummarizer struct {
+	countingSummarizer
+	release chan struct{}
+}
+
+func (s *gatedSummarizer) Summarize(ctx context.Context, previous string, messages []domain.ChatMessage, maxTokens int) (string, error) {
+	<-s.release
+	return s.countingSummarizer.Summarize(ctx, previous, messages, maxTokens)
+}
+
+func TestGroupChat_SummarizesInBackground(t *testing.T) {
+	sum := &gatedSummarizer{release: make(chan struct{})}
+	rec := &recorder{}
+	m := NewGroupChatManager(slog.Default(), rec.publish, Config{HistoryTokens: 100, Summarizer: sum})
+
+	ask := func(question string) domain.ChatWake {
+		t.Helper()
+		if err := m.HandleUserMessage(ctx, domain.ChatMessage{Session: "s", Sender: "user", Content: question}); err != nil {
+			t.Fatal(err)
+		}
+		// Wakes don't wait for the summary
+		waitIdle(t, m, func(s *session) bool { return s.selecting })
+		var wake domain.ChatWake
+		json.Unmarshal(rec.last(t, "agent/Liaison/wake").Data, &wake)
+		if err := m.ProcessAgentResponse(ctx, chatEvent(t, "Liaison", domain.ChatMessage{Session: "s", Content: "ok"})); err != nil {
+			t.Fatal(err)
+		}
+		return wake
+	}
+
+	var wake domain.ChatWake
+	for i := 0; i < 6; i++ {
+		wake = ask(fmt.Sprintf("Question %d: %s", i, strings.Repeat("x", 70)))
+	}
+	if wake.Summary != "" || wakeTokens(wake) > 100 {
+		t.Errorf("expected the older turns left out while summarizing, got %q and %d tokens", wake.Summary, wakeTokens(wake))
+	}
+
+	close(sum.release)
+	settle(t, m)
+	if sum.calls != 1 {
+		t.Errorf("summarized %d times, want once", sum.calls)
+	}
+	if wake = ask("And now?"); wake.Summary == "" {
+		t.Error("expected the next wake to carry the new summary")
+	}
+}
+
+func TestGroupChat_AgentBudgets(t *testing.T) {
+	rec := &recorder{}
+	m := NewGroupChatManager(slog.Default(), rec.publish, Config{
+		HistoryTokens:      60,
+		AgentHistoryTokens: map[string]int{"Liaison": 1000},
+	})
+	wake := converse(t, m, rec, 5)
+	if len(wake.History) != 9 || wake.Summary != "" {
+		t.Errorf("the larger budget should fit the whole conversation, got %d messages", len(wake.History))
+	}
+	if m.budget("SystemArchitect") != 60 {
+		t.Errorf("default budget = %d, want 60", m.budget("SystemArchitect"))
+	}
+}
+
+func TestGroupChat_WithoutSummaryDropsOlderTurns(t *testing.T) {
+	for _, sum := range []Summarizer{nil, &countingSummarizer{err: errors.New("LLM down")}} {
+		rec := &recorder{}
+		m := NewGroupChatManager(slog.Default(), rec.publish, Config{HistoryTokens: 100, Summarizer: sum})
+		wake := converse(t, m, rec, 10)
+		if wake.Summary != "" || len(wake.History) == 0 || wakeTokens(wake) > 100 {
+			t.Errorf("summarizer %v: got summary %q and %d messages (%d tokens)", sum, wake.Summary, len(wake.History), wakeTokens(wake))
+		}
+	}
+}
+
+func TestLLMSummarizer(t *testing.T) {
+	llm := &answerLLM{answer: strings.Repeat("long ", 100)}
+	s := &LLMSummarizer{LLM: llm}
+	summary, err := s.Summarize(ctx, "Login is in auth.go.", []domain.ChatMessage{{Sender: "user", Content: "And logout?"}}, 40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := domain.EstimateTokens(summary); n > 40 {
+		t.Errorf("summary has %d tokens, want at most 40", n)
+	}
+	if !strings.Contains(llm.prompts[0], "Login is in auth.go.") || !strings.Contains(llm.prompts[0], "user: And logout?") {
+		t.Errorf("prompt lacks the previous summary or messages:\n%s", llm.prompts[0])
+	}
+
+	if _, err := (&LLMSummarizer{LLM: &answerLLM{answer: "  "}}).Summarize(ctx, "", nil, 40); err == nil {
+		t.Error("expected an error for an empty summary")
+	}
+
+	// A message longer than the prompt limit is clipped
+	llm = &answerLLM{answer: "Pasted a log."}
+	s = &LLMSummarizer{LLM: llm, MaxPromptTokens: 300}
+	if _, err := s.Summarize(ctx, "", []domain.ChatMessage{{Sender: "user", Content: strings.Repeat("log line ", 500)}}, 40); err != nil {
+		t.Fatal(err)
+	}
+	if n := domain.EstimateTokens(llm.prompts[0]) + promptMargin; n > 300 {
+		t.Errorf("prompt has %d tokens, limit 300", n)
+	}
+}
+
+// limitedLLM summarizes like a provider with a prompt limit.
+type limitedLLM struct {
+	mu      sync.Mutex
+	limit   int
+	calls   int
+	tooLong int
+}
+
+func (l *limitedLLM) GenerateCode(_ context.Context, prompt string) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if domain.EstimateTokens(prompt)+promptMargin > l.limit {
+		l.tooLong++
+		return "", domain.ErrPromptTooLong
+	}
+	l.calls++
+	return fmt.Sprintf("Summary %d", l.calls), nil
+}
+func (l *limitedLLM) CheckHealth(context.Context) error { return nil }
+func (l *limitedLLM) Embed(context.Context, string) ([]float32, error) {
+	return nil, errors.New("not supported")
+}
+
+func TestGroupChat_SummarizesInChunks(t *testing.T) {
+	llm := &limitedLLM{limit: 300}
+	rec := &recorder{}
+	m := NewGroupChatManager(slog.Default(), rec.publish, Config{HistoryTokens: 1000, Summarizer: &LLMSummarizer{LLM: llm, MaxPromptTokens: 300}})
+
+	// The older turns folded by the first summary are far over the prompt limit
+	wake := converse(t, m, rec, 40)
+	llm.mu.Lock()
+	calls, tooLong := llm.calls, llm.tooLong
+	llm.mu.Unlock()
+	if tooLong != 0 {
+		t.Errorf("%d summary prompts exceeded the limit", tooLong)
+	}
+	if calls < 2 || wake.Summary == "" {
+		t.Fatalf("expected the history to be summarized in chunks, got %d calls and summary %q", calls, wake.Summary)
+	}
+
+	// Every older message is covered, and the wake fits the budget
+	tr := history(t, m, "s")
+	m.mu.Lock()
+	s := m.sessions["s"]
+	m.mu.Unlock()
+	s.mu.Lock()
+	covered := s.info.Summarized
+	s.mu.Unlock()
+	if covered+len(wake.History) < len(tr)-1 {
+		t.Errorf("summary covers %d of %d messages, wake has %d", covered, len(tr), len(wake.History))
+	}
+	if n := wakeTokens(wake); n > 1000 {
+		t.Errorf("wake uses %d tokens, budget 1000", n)
+	}
+}
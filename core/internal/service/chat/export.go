@@ -0,0 +1,27 @@
+package chat
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Markdown renders a transcript as a Markdown document, one section per message.
+func (t *Transcript) Markdown() string {
+	var b strings.Builder
+	title := t.Session.Title
+	if title == "" {
+		title = "Chat " + t.Session.ID
+	}
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	fmt.Fprintf(&b, "_Session `%s`, started %s, %d messages._\n", t.Session.ID, t.Session.CreatedAt.Format(time.RFC3339), len(t.Messages))
+
+	for _, msg := range t.Messages {
+		fmt.Fprintf(&b, "\n### %s", msg.Sender)
+		if !msg.Time.IsZero() {
+			fmt.Fprintf(&b, " · %s", msg.Time.Format(time.RFC3339))
+		}
+		fmt.Fprintf(&b, "\n\n%s\n", strings.TrimSpace(msg.Content))
+	}
+	return b.String()
+}
@@ -0,0 +1,32 @@
+package chat
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+)
+
+func TestTranscript_Markdown(t *testing.T) {
+	at := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	tr := &Transcript{
+		Session: domain.ChatSession{ID: "s1", Title: "Login bug", CreatedAt: at},
+		Messages: []domain.ChatMessage{
+			{Sender: "user", Content: "Where is login?", Time: at},
+			{Sender: "Liaison", Content: "In auth.go [1]\n\nSources:\n[1] auth.go\n", Time: at.Add(time.Second)},
+		},
+	}
+	want := "# Login bug\n\n" +
+		"_Session `s1`, started 2026-05-01T12:00:00Z, 2 messages._\n" +
+		"\n### user · 2026-05-01T12:00:00Z\n\nWhere is login?\n" +
+		"\n### Liaison · 2026-05-01T12:00:01Z\n\nIn auth.go [1]\n\nSources:\n[1] auth.go\n"
+	if got := tr.Markdown(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	untitled := &Transcript{Session: domain.ChatSession{ID: "s2"}}
+	if got := untitled.Markdown(); !strings.HasPrefix(got, "# Chat s2\n") {
+		t.Errorf("untitled export starts with %q", strings.SplitN(got, "\n", 2)[0])
+	}
+}
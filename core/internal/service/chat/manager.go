@@ -1,58 +1,696 @@
 package chat
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/datacraft/catalyst/core/internal/domain"
+	"github.com/google/uuid"
 )
 
-// GroupChatManager orchestrates the conversation.
-// Based on AutoGen's GroupChat pattern.
+// MQTT topics of the group chat.
+const (
+	UserMessageTopic = "chat/user/message"   // Users post chat.message events here
+	StreamTopic      = "chat/stream/message" // Every message of every session, for the UI
+	EndedTopic       = "chat/stream/ended"   // chat.ended: the user has the turn again
+)
+
+// Group chat defaults.
+const (
+	DefaultSpeaker          = "Liaison"
+	DefaultMaxTurns         = 10 // Agent turns per user message
+	DefaultTerminateKeyword = "TERMINATE"
+	DefaultIdleTimeout      = 2 * time.Minute
+)
+
+// titleLength is how much of the first user message becomes the session title.
+const titleLength = 60
+
+var (
+	// ErrEmptyMessage is returned for chat messages without content.
+	ErrEmptyMessage = errors.New("empty chat message")
+	// ErrInvalidSession is returned for session IDs that are not 1-64 letters, digits, '.', '_' or '-'.
+	ErrInvalidSession = errors.New("invalid chat session ID")
+)
+
+var sessionIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// Config configures the group chat.
+type Config struct {
+	Roster           []string               // Agents taking part, each woken on agent/<id>/wake (default [DefaultSpeaker])
+	Selector         SpeakerSelector        // Picks the next speaker (default round-robin with @mentions)
+	Describe         func(id string) string // Optional: agent descriptions for the selector
+	MaxTurns         int                    // Agent turns before the user gets the turn back (default DefaultMaxTurns)
+	TerminateKeyword string                 // An agent reply containing it ends the round ("" disables)
+	IdleTimeout      time.Duration          // How long to wait for a woken agent (0 waits forever)
+	Store            domain.ChatStore       // Optional: persists sessions, which are otherwise kept in memory
+
+	// Context window: each wake carries the rolling summary plus the most recent messages
+	// that fit the woken agent's budget
+	HistoryTokens      int            // Default budget (default DefaultHistoryTokens)
+	AgentHistoryTokens map[string]int // Per-agent budgets
+	Summarizer         Summarizer     // Optional: folds older turns into the summary; without it they are left out
+}
+
+// GroupChatManager orchestrates the conversations.
+// Based on AutoGen's GroupChat pattern: each user message starts a round in which the
+// selector wakes one agent at a time, until it hands the turn back to the user or a
+// termination condition (max turns, the termination keyword, idle timeout) is met.
+// Speakers are selected on a goroutine per session and history is summarized in the
+// background, so callers never wait for the LLM.
+//
+// Every message belongs to a session. With a store, sessions are written through as
+// messages arrive, loaded on first use (so they resume after a restart) and dropped
+// from memory once their round ends.
 type GroupChatManager struct {
-	history []domain.CloudEvent
-	logger  *slog.Logger
-	pub     func(topic string, event domain.CloudEvent)
+	cfg    Config
+	logger *slog.Logger
+	pub    func(topic string, event domain.CloudEvent)
+	now    func() time.Time
+
+	mu       sync.Mutex
+	sessions map[string]*session
 }
 
-func NewGroupChatManager(logger *slog.Logger, publisher func(topic string, event domain.CloudEvent)) *GroupChatManager {
+// session is the in-memory state of a chat session. Its fields are guarded by mu.
+type session struct {
+	mu          sync.Mutex
+	loaded      bool
+	evicted     bool                 // Dropped from the manager; lock the current entry instead
+	persisted   bool                 // The session row exists in the store
+	unsaved     []domain.ChatMessage // Messages not written to the store yet, retried in order
+	changed     bool                 // The session info changed since it was last written
+	info        domain.ChatSession
+	history     []domain.ChatMessage
+	lastInput   string      // Latest user message
+	round       int         // Incremented by every user message
+	active      bool        // A round is in progress
+	turns       int         // Agent turns in the current round
+	waiting     string      // Agent whose reply is expected; "" when it's the user's turn
+	idle        *time.Timer // Fires when the awaited agent takes too long
+	pending     *selection  // Latest speaker selection not yet started
+	selecting   bool        // The session's selection goroutine is running
+	summarizing bool        // Older messages are being folded into the summary
+}
+
+// selection asks for the next speaker of a round.
+type selection struct {
+	ctx   context.Context
+	round int
+	turn  Turn
+}
+
+// Transcript is a session with its messages.
+type Transcript struct {
+	Session  domain.ChatSession   `json:"session"`
+	Messages []domain.ChatMessage `json:"messages"`
+}
+
+func NewGroupChatManager(logger *slog.Logger, publisher func(topic string, event domain.CloudEvent), cfg Config) *GroupChatManager {
+	if len(cfg.Roster) == 0 {
+		cfg.Roster = []string{DefaultSpeaker}
+	}
+	if cfg.Selector == nil {
+		cfg.Selector = Mentions{Fallback: RoundRobin{}}
+	}
+	if cfg.MaxTurns <= 0 {
+		cfg.MaxTurns = DefaultMaxTurns
+	}
+	if cfg.HistoryTokens <= 0 {
+		cfg.HistoryTokens = DefaultHistoryTokens
+	}
 	return &GroupChatManager{
-		history: make([]domain.CloudEvent, 0),
-		logger:  logger,
-		pub:     publisher,
+		cfg:      cfg,
+		logger:   logger,
+		pub:      publisher,
+		now:      func() time.Time { return time.Now().UTC() },
+		sessions: make(map[string]*session),
+	}
+}
+
+// HandleUserEvent processes a chat.message event from the Mission Control UI (or any
+// client) posted on UserMessageTopic.
+func (m *GroupChatManager) HandleUserEvent(ctx context.Context, evt domain.CloudEvent) error {
+	msg, err := parseMessage(evt)
+	if err != nil {
+		return err
+	}
+	if msg.Sender == "" {
+		msg.Sender = "user"
+	}
+	return m.HandleUserMessage(ctx, msg)
+}
+
+// HandleUserMessage records a user message and starts a new round in its session. An
+// agent still expected to answer the previous round is no longer waited for.
+func (m *GroupChatManager) HandleUserMessage(ctx context.Context, msg domain.ChatMessage) error {
+	if strings.TrimSpace(msg.Content) == "" {
+		return ErrEmptyMessage
+	}
+	if msg.Session == "" {
+		msg.Session = domain.DefaultChatSession
+	}
+
+	s, err := m.lock(ctx, msg.Session)
+	if err != nil {
+		return err
+	}
+	msg = m.recordLocked(ctx, s, msg, true)
+	s.lastInput = msg.Content
+	s.round++
+	s.active = true
+	s.turns = 0
+	s.waiting = ""
+	s.stopIdleLocked()
+	round, turn := s.round, m.turnLocked(s, msg.Sender)
+	s.mu.Unlock()
+
+	if err := m.broadcast(msg); err != nil {
+		return err
+	}
+	m.schedule(ctx, s, round, turn)
+	return nil
+}
+
+// ProcessAgentResponse handles when an agent speaks back (a chat.message on agent/<id>/output).
+// Only the reply of the agent that was woken advances the round; other messages are
+// recorded and streamed but don't change whose turn it is. Replies without a session
+// go to the session waiting for their sender.
+func (m *GroupChatManager) ProcessAgentResponse(ctx context.Context, evt domain.CloudEvent) error {
+	msg, err := parseMessage(evt)
+	if err != nil {
+		return err
 	}
+	if msg.Sender == "" {
+		msg.Sender = evt.Source
+	}
+	if msg.Session == "" {
+		msg.Session = m.waitingFor(msg.Sender)
+	}
+	return m.respond(ctx, msg)
 }
 
-// HandleUserMessage processes input from the Mission Control UI.
-func (m *GroupChatManager) HandleUserMessage(msg string) {
-	// 1. Record User Message
-	evt, _ := domain.NewEvent("user", "chat.message", map[string]string{"content": msg})
-	m.addToHistory(evt)
+// ProcessToolCall handles a tool.call (published on tool/call) that a woken agent emitted
+// instead of a chat message: its note to the user is recorded as the agent's reply, so
+// the round goes on. Tool calls outside of a chat are ignored.
+func (m *GroupChatManager) ProcessToolCall(ctx context.Context, evt domain.CloudEvent) error {
+	if evt.Type != domain.ToolCallEventType {
+		return fmt.Errorf("unexpected event type %q", evt.Type)
+	}
+	var call domain.ChatToolCall
+	if err := json.Unmarshal(evt.Data, &call); err != nil {
+		return fmt.Errorf("invalid tool call: %w", err)
+	}
 
-	// 2. Select Next Speaker (Round Robin: User -> Liaison)
-	// In the future, this will be smarter (Auto).
-	m.selectNextSpeaker("liaison")
+	msg := domain.ChatMessage{Session: call.Session, Sender: evt.Source, Content: strings.TrimSpace(call.Reply)}
+	if msg.Content == "" {
+		msg.Content = fmt.Sprintf("Running %s.", call.ToolName)
+	}
+	if msg.Session == "" {
+		id, ok := m.sessionWaitingFor(msg.Sender)
+		if !ok {
+			return nil
+		}
+		msg.Session = id
+	}
+	return m.respond(ctx, msg)
 }
 
-func (m *GroupChatManager) addToHistory(evt domain.CloudEvent) {
-	m.history = append(m.history, evt)
-	// Broadcast to UI
-	m.pub("chat/stream/message", evt)
+// respond records an agent message and, if it is the awaited reply, advances the round.
+func (m *GroupChatManager) respond(ctx context.Context, msg domain.ChatMessage) error {
+	s, err := m.lock(ctx, msg.Session)
+	if err != nil {
+		return err
+	}
+	msg = m.recordLocked(ctx, s, msg, false)
+	expected := s.waiting != "" && s.waiting == msg.Sender
+	var (
+		reason string
+		round  = s.round
+		turns  int
+		turn   Turn
+	)
+	if expected {
+		s.waiting = ""
+		s.stopIdleLocked()
+		s.turns++
+		turns = s.turns
+		switch {
+		case m.cfg.TerminateKeyword != "" && strings.Contains(msg.Content, m.cfg.TerminateKeyword):
+			reason = domain.ChatEndTerminate
+		case s.turns >= m.cfg.MaxTurns:
+			reason = domain.ChatEndMaxTurns
+		default:
+			turn = m.turnLocked(s, msg.Sender)
+		}
+	}
+	s.mu.Unlock()
+
+	if err := m.broadcast(msg); err != nil {
+		return err
+	}
+	switch {
+	case !expected:
+		m.logger.Info("Chat message outside the speaker's turn", "session", msg.Session, "sender", msg.Sender)
+		return nil
+	case reason != "":
+		return m.end(ctx, s, round, reason, turns)
+	}
+	m.schedule(ctx, s, round, turn)
+	return nil
 }
 
-func (m *GroupChatManager) selectNextSpeaker(agentID string) {
-	// Trigger the agent to speak
-	// We use specific type "agent.liaison.wake" so MissionManager can route it exactly.
-	eventType := fmt.Sprintf("agent.%s.wake", agentID)
-	cmd, _ := domain.NewEvent("chat-manager", eventType, map[string]string{
-		"target_agent": agentID,
-		"context_len":  fmt.Sprintf("%d", len(m.history)),
-	})
-	m.pub(fmt.Sprintf("agent/%s/wake", agentID), cmd)
+// CreateSession starts an empty session with a generated ID.
+func (m *GroupChatManager) CreateSession(ctx context.Context, title string) (domain.ChatSession, error) {
+	now := m.now()
+	s := &session{
+		loaded: true,
+		info:   domain.ChatSession{ID: uuid.NewString(), Title: title, CreatedAt: now, UpdatedAt: now},
+	}
+	if m.cfg.Store != nil {
+		if err := m.cfg.Store.SaveChatSession(ctx, s.info); err != nil {
+			return domain.ChatSession{}, err
+		}
+		s.persisted = true
+	}
+
+	m.mu.Lock()
+	m.sessions[s.info.ID] = s
+	m.mu.Unlock()
+	return s.info, nil
+}
+
+// Sessions lists sessions, most recently updated first.
+func (m *GroupChatManager) Sessions(ctx context.Context, limit int) ([]domain.ChatSession, error) {
+	if m.cfg.Store != nil {
+		return m.cfg.Store.ListChatSessions(ctx, limit)
+	}
+
+	m.mu.Lock()
+	cached := make([]*session, 0, len(m.sessions))
+	for _, s := range m.sessions {
+		cached = append(cached, s)
+	}
+	m.mu.Unlock()
+
+	sessions := make([]domain.ChatSession, 0, len(cached))
+	for _, s := range cached {
+		s.mu.Lock()
+		if s.loaded {
+			sessions = append(sessions, s.infoLocked())
+		}
+		s.mu.Unlock()
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].UpdatedAt.After(sessions[j].UpdatedAt) })
+	if limit > 0 && len(sessions) > limit {
+		sessions = sessions[:limit]
+	}
+	return sessions, nil
+}
+
+// Transcript returns a session with its messages, or domain.ErrChatSessionNotFound.
+func (m *GroupChatManager) Transcript(ctx context.Context, id string) (*Transcript, error) {
+	m.mu.Lock()
+	s := m.sessions[id]
+	m.mu.Unlock()
+	if s != nil {
+		s.mu.Lock()
+		if s.loaded && !s.evicted {
+			t := &Transcript{Session: s.infoLocked(), Messages: s.snapshot()}
+			s.mu.Unlock()
+			return t, nil
+		}
+		s.mu.Unlock()
+	}
+	if m.cfg.Store == nil {
+		return nil, domain.ErrChatSessionNotFound
+	}
+
+	info, err := m.cfg.Store.GetChatSession(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	messages, err := m.cfg.Store.ListChatMessages(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &Transcript{Session: *info, Messages: messages}, nil
+}
+
+// lock returns the session, created and loaded on first use, with its mutex held.
+func (m *GroupChatManager) lock(ctx context.Context, id string) (*session, error) {
+	if !sessionIDPattern.MatchString(id) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidSession, id)
+	}
+	for {
+		m.mu.Lock()
+		s, ok := m.sessions[id]
+		if !ok {
+			s = &session{info: domain.ChatSession{ID: id}}
+			m.sessions[id] = s
+		}
+		m.mu.Unlock()
+
+		s.mu.Lock()
+		if s.evicted {
+			s.mu.Unlock()
+			continue
+		}
+		if !s.loaded {
+			if err := m.loadLocked(ctx, s); err != nil {
+				s.mu.Unlock()
+				return nil, err
+			}
+		}
+		return s, nil
+	}
+}
+
+// loadLocked reads a session from the store, or starts a new one. Callers hold s.mu.
+func (m *GroupChatManager) loadLocked(ctx context.Context, s *session) error {
+	if m.cfg.Store != nil {
+		info, err := m.cfg.Store.GetChatSession(ctx, s.info.ID)
+		switch {
+		case err == nil:
+			messages, err := m.cfg.Store.ListChatMessages(ctx, s.info.ID)
+			if err != nil {
+				return fmt.Errorf("failed to load chat session %s: %w", s.info.ID, err)
+			}
+			for i := range messages {
+				messages[i].Tokens = messageTokens(messages[i])
+			}
+			s.info, s.history, s.persisted, s.loaded = *info, messages, true, true
+			return nil
+		case !errors.Is(err, domain.ErrChatSessionNotFound):
+			return fmt.Errorf("failed to load chat session %s: %w", s.info.ID, err)
+		}
+	}
+	now := m.now()
+	s.info.CreatedAt, s.info.UpdatedAt = now, now
+	s.loaded = true
+	return nil
 }
 
-// ProcessAgentResponse handles when an agent speaks back.
-func (m *GroupChatManager) ProcessAgentResponse(evt domain.CloudEvent) {
-	m.addToHistory(evt)
-	// Logic to decide if we stop or pick another speaker would go here.
+// recordLocked adds a message to the session and writes it through to the store. The
+// first user message titles untitled sessions. Store failures are logged: the
+// conversation goes on in memory, and the writes are retried with the next message or
+// when the round ends. Callers hold s.mu.
+func (m *GroupChatManager) recordLocked(ctx context.Context, s *session, msg domain.ChatMessage, fromUser bool) domain.ChatMessage {
+	msg.Session = s.info.ID
+	msg.Time = m.now()
+	msg.Tokens = messageTokens(domain.ChatMessage{Sender: msg.Sender, Content: msg.Content})
+	if fromUser && s.info.Title == "" {
+		s.info.Title = titleOf(msg.Content)
+		s.changed = true
+	}
+	s.info.UpdatedAt = msg.Time
+	s.history = append(s.history, msg)
+
+	if m.cfg.Store != nil {
+		s.unsaved = append(s.unsaved, msg)
+		if err := m.flushLocked(ctx, s); err != nil {
+			m.logger.Warn("Failed to persist chat message", "session", s.info.ID, "unsaved", len(s.unsaved), "error", err)
+		}
+	}
+	return msg
+}
+
+// flushLocked writes what the store is missing: the session row, the unsaved messages
+// in order, then the session info if it changed. The info goes last so that the stored
+// Summarized count never covers messages the store does not have. Callers hold s.mu.
+func (m *GroupChatManager) flushLocked(ctx context.Context, s *session) error {
+	if !s.persisted {
+		info := s.info
+		info.Summary, info.Summarized = "", 0
+		if err := m.cfg.Store.SaveChatSession(ctx, info); err != nil {
+			return err
+		}
+		s.persisted = true
+		s.changed = s.changed || s.info.Summarized > 0
+	}
+	for len(s.unsaved) > 0 {
+		if err := m.cfg.Store.AppendChatMessage(ctx, s.unsaved[0]); err != nil {
+			return err
+		}
+		s.unsaved = s.unsaved[1:]
+	}
+	if s.changed {
+		if err := m.cfg.Store.SaveChatSession(ctx, s.info); err != nil {
+			return err
+		}
+		s.changed = false
+	}
+	return nil
+}
+
+// waitingFor returns the session waiting for an agent's reply, or the default session.
+func (m *GroupChatManager) waitingFor(agentID string) string {
+	if id, ok := m.sessionWaitingFor(agentID); ok {
+		return id
+	}
+	return domain.DefaultChatSession
+}
+
+// sessionWaitingFor returns the session waiting for an agent's reply, if any.
+func (m *GroupChatManager) sessionWaitingFor(agentID string) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, s := range m.sessions {
+		s.mu.Lock()
+		waiting := s.waiting == agentID
+		s.mu.Unlock()
+		if waiting {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// schedule queues the selection of a round's next speaker. The selector may ask the LLM,
+// so selection runs on a goroutine of the session instead of the caller's (an MQTT
+// callback). It runs one selection at a time, and only the latest request is kept:
+// earlier ones belong to rounds that have moved on.
+func (m *GroupChatManager) schedule(ctx context.Context, s *session, round int, turn Turn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending = &selection{ctx: ctx, round: round, turn: turn}
+	if !s.selecting {
+		s.selecting = true
+		go m.selectSpeakers(s)
+	}
+}
+
+// selectSpeakers runs the session's pending selections until there are none left.
+func (m *GroupChatManager) selectSpeakers(s *session) {
+	for {
+		s.mu.Lock()
+		sel := s.pending
+		s.pending = nil
+		if sel == nil {
+			s.selecting = false
+			s.mu.Unlock()
+			return
+		}
+		s.mu.Unlock()
+
+		if err := m.next(sel.ctx, s, sel.round, sel.turn); err != nil {
+			m.logger.Warn("Failed to pass the chat turn on", "session", s.info.ID, "error", err)
+		}
+	}
+}
+
+// next asks the selector for the next speaker of a round and wakes it, or hands the
+// turn back to the user. Nothing happens if the round ended or a newer one started meanwhile.
+func (m *GroupChatManager) next(ctx context.Context, s *session, round int, turn Turn) error {
+	speaker, err := m.cfg.Selector.NextSpeaker(ctx, turn)
+	if err != nil {
+		m.logger.Warn("Speaker selection failed, handing the turn back to the user", "error", err)
+		speaker = ""
+	}
+	if speaker == "" {
+		s.mu.Lock()
+		stale, turns := s.staleLocked(round), s.turns
+		s.mu.Unlock()
+		if stale {
+			return nil
+		}
+		return m.end(ctx, s, round, domain.ChatEndHandoff, turns)
+	}
+
+	summary, recent := m.window(ctx, s, speaker)
+
+	s.mu.Lock()
+	if s.staleLocked(round) {
+		s.mu.Unlock()
+		return nil
+	}
+	s.waiting = speaker
+	if m.cfg.IdleTimeout > 0 {
+		s.idle = time.AfterFunc(m.cfg.IdleTimeout, func() { m.timeout(s, round, speaker) })
+	}
+	wake := domain.ChatWake{Session: s.info.ID, TargetAgent: speaker, UserInput: s.lastInput, Summary: summary, History: recent}
+	s.mu.Unlock()
+
+	return m.wake(wake)
+}
+
+// timeout ends a round whose woken agent did not answer in time.
+func (m *GroupChatManager) timeout(s *session, round int, speaker string) {
+	s.mu.Lock()
+	if round != s.round || s.waiting != speaker {
+		s.mu.Unlock()
+		return
+	}
+	s.waiting = ""
+	s.idle = nil
+	turns := s.turns
+	s.mu.Unlock()
+
+	m.logger.Warn("Speaker did not answer in time", "session", s.info.ID, "agent", speaker, "timeout", m.cfg.IdleTimeout)
+	if err := m.end(context.Background(), s, round, domain.ChatEndIdleTimeout, turns); err != nil {
+		m.logger.Warn("Failed to end chat round", "error", err)
+	}
+}
+
+// end tells the UI the agents are done and the user has the turn. Writes that failed
+// during the round are retried, and persisted sessions are then dropped from memory
+// until their next message.
+func (m *GroupChatManager) end(ctx context.Context, s *session, round int, reason string, turns int) error {
+	s.mu.Lock()
+	if s.round == round {
+		s.active = false
+	}
+	id := s.info.ID
+	if m.cfg.Store != nil && (len(s.unsaved) > 0 || s.changed) {
+		if err := m.flushLocked(ctx, s); err != nil {
+			m.logger.Warn("Failed to persist chat session, keeping it in memory", "session", id, "unsaved", len(s.unsaved), "error", err)
+		}
+	}
+	s.mu.Unlock()
+
+	evt, err := domain.NewEvent("chat-manager", domain.ChatEndedEventType, domain.ChatEnded{Session: id, Reason: reason, Turns: turns})
+	if err != nil {
+		return err
+	}
+	m.logger.Info("Chat round ended", "session", id, "reason", reason, "turns", turns)
+	m.pub(EndedTopic, evt)
+
+	if m.cfg.Store != nil {
+		m.evict(id)
+	}
+	return nil
+}
+
+// evict drops an idle, fully persisted session from memory. Sessions with unsaved
+// writes stay until a later write goes through.
+func (m *GroupChatManager) evict(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[id]
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.active || !s.persisted || len(s.unsaved) > 0 || s.changed || s.summarizing {
+		return
+	}
+	s.evicted = true
+	delete(m.sessions, id)
+}
+
+// turnLocked describes the current turn for the selector. Callers hold s.mu.
+func (m *GroupChatManager) turnLocked(s *session, lastSpeaker string) Turn {
+	roster := make([]Participant, len(m.cfg.Roster))
+	for i, id := range m.cfg.Roster {
+		roster[i] = Participant{ID: id}
+		if m.cfg.Describe != nil {
+			roster[i].Description = m.cfg.Describe(id)
+		}
+	}
+	return Turn{Roster: roster, History: s.snapshot(), LastSpeaker: lastSpeaker}
+}
+
+// staleLocked reports whether a round no longer needs a speaker: it ended, a newer
+// one started, or a speaker was already woken. Callers hold s.mu.
+func (s *session) staleLocked(round int) bool {
+	return round != s.round || !s.active || s.evicted || s.waiting != ""
+}
+
+// stopIdleLocked cancels the idle timer. Callers hold s.mu.
+func (s *session) stopIdleLocked() {
+	if s.idle != nil {
+		s.idle.Stop()
+		s.idle = nil
+	}
+}
+
+// infoLocked returns the session's info with its message count. Callers hold s.mu.
+func (s *session) infoLocked() domain.ChatSession {
+	info := s.info
+	info.MessageCount = len(s.history)
+	return info
+}
+
+// snapshot copies the history. Callers hold s.mu.
+func (s *session) snapshot() []domain.ChatMessage {
+	return append([]domain.ChatMessage(nil), s.history...)
+}
+
+// broadcast streams a message to the UI.
+func (m *GroupChatManager) broadcast(msg domain.ChatMessage) error {
+	evt, err := domain.NewEvent(msg.Sender, domain.ChatMessageEventType, msg)
+	if err != nil {
+		return err
+	}
+	m.pub(StreamTopic, evt)
+	return nil
+}
+
+// wake triggers the agent to speak. The mission triggered by agent/<id>/wake runs it,
+// and its reply comes back through ProcessAgentResponse.
+func (m *GroupChatManager) wake(wake domain.ChatWake) error {
+	cmd, err := domain.NewEvent("chat-manager", domain.ChatWakeEventType, wake)
+	if err != nil {
+		return err
+	}
+	m.logger.Info("Waking speaker", "session", wake.Session, "agent", wake.TargetAgent, "history", len(wake.History))
+	m.pub(fmt.Sprintf("agent/%s/wake", wake.TargetAgent), cmd)
+	return nil
+}
+
+// titleOf shortens the first user message of a session to a title.
+func titleOf(content string) string {
+	content = strings.Join(strings.Fields(content), " ")
+	if r := []rune(content); len(r) > titleLength {
+		return strings.TrimSpace(string(r[:titleLength])) + "…"
+	}
+	return content
+}
+
+// parseMessage reads a chat.message whose data is a ChatMessage or a bare JSON string.
+func parseMessage(evt domain.CloudEvent) (domain.ChatMessage, error) {
+	if evt.Type != domain.ChatMessageEventType {
+		return domain.ChatMessage{}, fmt.Errorf("unexpected event type %q", evt.Type)
+	}
+	var msg domain.ChatMessage
+	if err := json.Unmarshal(evt.Data, &msg); err != nil {
+		var content string
+		if json.Unmarshal(evt.Data, &content) != nil {
+			return domain.ChatMessage{}, fmt.Errorf("invalid chat message: %w", err)
+		}
+		msg.Content = content
+	}
+	if strings.TrimSpace(msg.Content) == "" {
+		return domain.ChatMessage{}, ErrEmptyMessage
+	}
+	return msg, nil
 }
@@ -0,0 +1,569 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+)
+
+// recorder captures published events by topic.
+type recorder struct {
+	mu     sync.Mutex
+	events map[string][]domain.CloudEvent
+	wakes  []string // Woken agents, in order
+}
+
+func (r *recorder) publish(topic string, evt domain.CloudEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.events == nil {
+		r.events = make(map[string][]domain.CloudEvent)
+	}
+	r.events[topic] = append(r.events[topic], evt)
+	if evt.Type == domain.ChatWakeEventType {
+		var wake domain.ChatWake
+		json.Unmarshal(evt.Data, &wake)
+		r.wakes = append(r.wakes, wake.TargetAgent)
+	}
+}
+
+// reasons returns why each round ended, in order.
+func (r *recorder) reasons() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var reasons []string
+	for _, evt := range r.events[EndedTopic] {
+		var ended domain.ChatEnded
+		json.Unmarshal(evt.Data, &ended)
+		reasons = append(reasons, ended.Reason)
+	}
+	return reasons
+}
+
+func (r *recorder) last(t *testing.T, topic string) domain.CloudEvent {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.events[topic]) == 0 {
+		t.Fatalf("nothing published on %s", topic)
+	}
+	return r.events[topic][len(r.events[topic])-1]
+}
+
+var ctx = context.Background()
+
+func chatEvent(t *testing.T, source string, data interface{}) domain.CloudEvent {
+	t.Helper()
+	evt, err := domain.NewEvent(source, domain.ChatMessageEventType, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return evt
+}
+
+func history(t *testing.T, m *GroupChatManager, session string) []domain.ChatMessage {
+	t.Helper()
+	tr, err := m.Transcript(ctx, session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tr.Messages
+}
+
+// settle waits until no session is selecting a speaker or summarizing, so that the
+// wakes, round ends and summaries of the messages handled so far are done.
+func settle(t *testing.T, m *GroupChatManager) {
+	t.Helper()
+	waitIdle(t, m, func(s *session) bool { return s.selecting || s.summarizing })
+}
+
+// waitIdle waits until no session is busy.
+func waitIdle(t *testing.T, m *GroupChatManager, busy func(s *session) bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		idle := true
+		m.mu.Lock()
+		for _, s := range m.sessions {
+			s.mu.Lock()
+			idle = idle && !busy(s)
+			s.mu.Unlock()
+		}
+		m.mu.Unlock()
+		if idle {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("chat sessions did not settle")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestGroupChat_MultiTurn(t *testing.T) {
+	rec := &recorder{}
+	m := NewGroupChatManager(slog.Default(), rec.publish, Config{})
+
+	if err := m.HandleUserEvent(ctx, chatEvent(t, "ui", domain.ChatMessage{Content: "Where is login?"})); err != nil {
+		t.Fatal(err)
+	}
+	settle(t, m)
+	wakeEvt := rec.last(t, "agent/Liaison/wake")
+	if wakeEvt.Type != domain.ChatWakeEventType {
+		t.Errorf("wake type = %q, want %q", wakeEvt.Type, domain.ChatWakeEventType)
+	}
+
+	if err := m.ProcessAgentResponse(ctx, chatEvent(t, "Liaison", domain.ChatMessage{Sender: "Liaison", Content: "In auth.go [1]"})); err != nil {
+		t.Fatal(err)
+	}
+	// Second turn, sent as a bare string
+	settle(t, m)
+	if err := m.HandleUserEvent(ctx, chatEvent(t, "ui", "Thanks, open an issue")); err != nil {
+		t.Fatal(err)
+	}
+	settle(t, m)
+
+	var wake domain.ChatWake
+	if err := json.Unmarshal(rec.last(t, "agent/Liaison/wake").Data, &wake); err != nil {
+		t.Fatal(err)
+	}
+	if wake.TargetAgent != "Liaison" || wake.UserInput != "Thanks, open an issue" {
+		t.Errorf("unexpected wake %+v", wake)
+	}
+	want := []string{"user: Where is login?", "Liaison: In auth.go [1]", "user: Thanks, open an issue"}
+	if len(wake.History) != len(want) {
+		t.Fatalf("history has %d messages, want %d", len(wake.History), len(want))
+	}
+	for i, msg := range wake.History {
+		if got := msg.Sender + ": " + msg.Content; got != want[i] {
+			t.Errorf("history[%d] = %q, want %q", i, got, want[i])
+		}
+	}
+	if n := len(rec.events[StreamTopic]); n != 3 {
+		t.Errorf("streamed %d messages, want 3", n)
+	}
+}
+
+func TestGroupChat_RejectsInvalidMessages(t *testing.T) {
+	rec := &recorder{}
+	m := NewGroupChatManager(slog.Default(), rec.publish, Config{Roster: []string{"Liaison"}})
+
+	if err := m.HandleUserEvent(ctx, chatEvent(t, "ui", domain.ChatMessage{Content: "  "})); !errors.Is(err, ErrEmptyMessage) {
+		t.Errorf("expected ErrEmptyMessage, got %v", err)
+	}
+	wrongType, _ := domain.NewEvent("Liaison", "tool.call", map[string]string{"content": "x"})
+	if err := m.ProcessAgentResponse(ctx, wrongType); err == nil {
+		t.Error("expected an error for a non chat.message event")
+	}
+	if _, err := m.Transcript(ctx, domain.DefaultChatSession); !errors.Is(err, domain.ErrChatSessionNotFound) || len(rec.events) != 0 {
+		t.Errorf("invalid messages should not be recorded or published, got %v / %v", err, rec.events)
+	}
+}
+
+func TestGroupChat_AgentSenderDefaultsToSource(t *testing.T) {
+	m := NewGroupChatManager(slog.Default(), (&recorder{}).publish, Config{})
+	if err := m.ProcessAgentResponse(ctx, chatEvent(t, "SystemArchitect", map[string]string{"content": "LGTM"})); err != nil {
+		t.Fatal(err)
+	}
+	if h := history(t, m, domain.DefaultChatSession); len(h) != 1 || h[0].Sender != "SystemArchitect" {
+		t.Errorf("unexpected history %+v", h)
+	}
+}
+
+func TestGroupChat_ToolCallIsTheTurn(t *testing.T) {
+	rec := &recorder{}
+	m := NewGroupChatManager(slog.Default(), rec.publish, Config{})
+	if err := m.HandleUserEvent(ctx, chatEvent(t, "ui", "File an issue for the login refactor")); err != nil {
+		t.Fatal(err)
+	}
+	settle(t, m)
+
+	call := domain.ChatToolCall{Reply: "Filing the issue."}
+	call.ToolName = "git_create_issue"
+	evt, err := domain.NewEvent("Liaison", domain.ToolCallEventType, call)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.ProcessToolCall(ctx, evt); err != nil {
+		t.Fatal(err)
+	}
+	settle(t, m)
+	h := history(t, m, domain.DefaultChatSession)
+	if len(h) != 2 || h[1].Sender != "Liaison" || h[1].Content != "Filing the issue." {
+		t.Fatalf("expected the tool call's note as Liaison's reply, got %+v", h)
+	}
+
+	// Nobody waits for Liaison any more: a second call is not part of the chat
+	if err := m.ProcessToolCall(ctx, evt); err != nil {
+		t.Fatal(err)
+	}
+	if h := history(t, m, domain.DefaultChatSession); len(h) != 2 {
+		t.Errorf("tool call outside of a turn was recorded: %+v", h)
+	}
+}
+
+// gatedSelector blocks every selection until release is closed.
+type gatedSelector struct {
+	release chan struct{}
+	calls   chan Turn
+}
+
+func (s *gatedSelector) NextSpeaker(ctx context.Context, turn Turn) (string, error) {
+	s.calls <- turn
+	<-s.release
+	return Mentions{Fallback: RoundRobin{}}.NextSpeaker(ctx, turn)
+}
+
+func TestGroupChat_SelectionDoesNotBlockCaller(t *testing.T) {
+	rec := &recorder{}
+	sel := &gatedSelector{release: make(chan struct{}), calls: make(chan Turn, 10)}
+	m := NewGroupChatManager(slog.Default(), rec.publish, Config{Roster: []string{"Liaison", "SystemArchitect"}, Selector: sel})
+
+	// Both messages are handled while the first selection is stuck
+	if err := m.HandleUserMessage(ctx, domain.ChatMessage{Sender: "user", Content: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+	<-sel.calls
+	if err := m.HandleUserMessage(ctx, domain.ChatMessage{Sender: "user", Content: "@SystemArchitect actually, you"}); err != nil {
+		t.Fatal(err)
+	}
+	close(sel.release)
+	settle(t, m)
+
+	// Only the latest round wakes a speaker
+	if len(sel.calls) != 1 || !reflect.DeepEqual(rec.wakes, []string{"SystemArchitect"}) {
+		t.Errorf("%d more selections, wakes %v", len(sel.calls), rec.wakes)
+	}
+}
+
+func TestGroupChat_Termination(t *testing.T) {
+	reply := func(t *testing.T, m *GroupChatManager, sender, content string) {
+		t.Helper()
+		if err := m.ProcessAgentResponse(ctx, chatEvent(t, sender, domain.ChatMessage{Sender: sender, Content: content})); err != nil {
+			t.Fatal(err)
+		}
+		settle(t, m)
+	}
+	ask := func(t *testing.T, m *GroupChatManager, content string) {
+		t.Helper()
+		if err := m.HandleUserMessage(ctx, domain.ChatMessage{Sender: "user", Content: content}); err != nil {
+			t.Fatal(err)
+		}
+		settle(t, m)
+	}
+	roster := []string{"Liaison", "SystemArchitect"}
+
+	t.Run("single agent hands back", func(t *testing.T) {
+		rec := &recorder{}
+		m := NewGroupChatManager(slog.Default(), rec.publish, Config{})
+		ask(t, m, "hi")
+		reply(t, m, "Liaison", "hello")
+		if !reflect.DeepEqual(rec.wakes, []string{"Liaison"}) || !reflect.DeepEqual(rec.reasons(), []string{domain.ChatEndHandoff}) {
+			t.Errorf("wakes %v, ended %v", rec.wakes, rec.reasons())
+		}
+	})
+
+	t.Run("keyword", func(t *testing.T) {
+		rec := &recorder{}
+		m := NewGroupChatManager(slog.Default(), rec.publish, Config{Roster: roster, TerminateKeyword: DefaultTerminateKeyword})
+		ask(t, m, "Should we split the service?")
+		reply(t, m, "Liaison", "Let's ask the architect.")
+		reply(t, m, "SystemArchitect", "No. TERMINATE")
+		if !reflect.DeepEqual(rec.wakes, []string{"Liaison", "SystemArchitect"}) || !reflect.DeepEqual(rec.reasons(), []string{domain.ChatEndTerminate}) {
+			t.Errorf("wakes %v, ended %v", rec.wakes, rec.reasons())
+		}
+	})
+
+	t.Run("max turns", func(t *testing.T) {
+		rec := &recorder{}
+		m := NewGroupChatManager(slog.Default(), rec.publish, Config{Roster: roster, MaxTurns: 3})
+		ask(t, m, "Debate")
+		reply(t, m, "Liaison", "A")
+		reply(t, m, "SystemArchitect", "B")
+		reply(t, m, "Liaison", "C")
+		if !reflect.DeepEqual(rec.wakes, []string{"Liaison", "SystemArchitect", "Liaison"}) || !reflect.DeepEqual(rec.reasons(), []string{domain.ChatEndMaxTurns}) {
+			t.Errorf("wakes %v, ended %v", rec.wakes, rec.reasons())
+		}
+	})
+
+	t.Run("mention and unsolicited replies", func(t *testing.T) {
+		rec := &recorder{}
+		m := NewGroupChatManager(slog.Default(), rec.publish, Config{Roster: roster, Selector: Mentions{}})
+		ask(t, m, "@SystemArchitect, is this design ok?")
+		reply(t, m, "Liaison", "I wasn't asked") // Recorded, but not its turn
+		if !reflect.DeepEqual(rec.wakes, []string{"SystemArchitect"}) || len(rec.reasons()) != 0 {
+			t.Errorf("wakes %v, ended %v", rec.wakes, rec.reasons())
+		}
+		if n := len(history(t, m, domain.DefaultChatSession)); n != 2 {
+			t.Errorf("history has %d messages, want 2", n)
+		}
+	})
+
+	t.Run("idle timeout", func(t *testing.T) {
+		rec := &recorder{}
+		m := NewGroupChatManager(slog.Default(), rec.publish, Config{IdleTimeout: 10 * time.Millisecond})
+		ask(t, m, "anyone there?")
+		deadline := time.Now().Add(time.Second)
+		for len(rec.reasons()) == 0 && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+		if !reflect.DeepEqual(rec.reasons(), []string{domain.ChatEndIdleTimeout}) {
+			t.Fatalf("ended %v, want idle_timeout", rec.reasons())
+		}
+		// A late reply no longer advances the round
+		reply(t, m, "Liaison", "sorry, I was busy")
+		if len(rec.wakes) != 1 || len(rec.reasons()) != 1 {
+			t.Errorf("late reply changed the round: wakes %v, ended %v", rec.wakes, rec.reasons())
+		}
+	})
+}
+
+// MemoryChatStore is an in-memory domain.ChatStore.
+type MemoryChatStore struct {
+	mu       sync.Mutex
+	sessions map[string]domain.ChatSession
+	messages map[string][]domain.ChatMessage
+}
+
+func NewMemoryChatStore() *MemoryChatStore {
+	return &MemoryChatStore{sessions: make(map[string]domain.ChatSession), messages: make(map[string][]domain.ChatMessage)}
+}
+
+func (s *MemoryChatStore) SaveChatSession(_ context.Context, cs domain.ChatSession) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[cs.ID] = cs
+	return nil
+}
+
+func (s *MemoryChatStore) GetChatSession(_ context.Context, id string) (*domain.ChatSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cs, ok := s.sessions[id]
+	if !ok {
+		return nil, domain.ErrChatSessionNotFound
+	}
+	cs.MessageCount = len(s.messages[id])
+	return &cs, nil
+}
+
+func (s *MemoryChatStore) ListChatSessions(_ context.Context, limit int) ([]domain.ChatSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sessions := make([]domain.ChatSession, 0, len(s.sessions))
+	for id, cs := range s.sessions {
+		cs.MessageCount = len(s.messages[id])
+		sessions = append(sessions, cs)
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].UpdatedAt.After(sessions[j].UpdatedAt) })
+	if limit > 0 && len(sessions) > limit {
+		sessions = sessions[:limit]
+	}
+	return sessions, nil
+}
+
+func (s *MemoryChatStore) AppendChatMessage(_ context.Context, msg domain.ChatMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cs, ok := s.sessions[msg.Session]
+	if !ok {
+		return domain.ErrChatSessionNotFound
+	}
+	if msg.Time.After(cs.UpdatedAt) {
+		cs.UpdatedAt = msg.Time
+		s.sessions[msg.Session] = cs
+	}
+	s.messages[msg.Session] = append(s.messages[msg.Session], msg)
+	return nil
+}
+
+func (s *MemoryChatStore) ListChatMessages(_ context.Context, sessionID string) ([]domain.ChatMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]domain.ChatMessage(nil), s.messages[sessionID]...), nil
+}
+
+func TestGroupChat_Sessions(t *testing.T) {
+	rec := &recorder{}
+	m := NewGroupChatManager(slog.Default(), rec.publish, Config{Roster: []string{"Liaison", "SystemArchitect"}, Selector: Mentions{}})
+
+	for _, msg := range []domain.ChatMessage{
+		{Session: "a", Sender: "user", Content: "@Liaison where is login?"},
+		{Session: "b", Sender: "user", Content: "@SystemArchitect review this"},
+	} {
+		if err := m.HandleUserMessage(ctx, msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	settle(t, m)
+	var wake domain.ChatWake
+	json.Unmarshal(rec.last(t, "agent/SystemArchitect/wake").Data, &wake)
+	if wake.Session != "b" || len(wake.History) != 1 {
+		t.Errorf("wake for b = %+v", wake)
+	}
+
+	// An explicit session, and one routed to the session waiting for its sender
+	if err := m.ProcessAgentResponse(ctx, chatEvent(t, "SystemArchitect", domain.ChatMessage{Session: "b", Content: "Looks fine"})); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.ProcessAgentResponse(ctx, chatEvent(t, "Liaison", "In auth.go")); err != nil {
+		t.Fatal(err)
+	}
+	settle(t, m)
+
+	a, b := history(t, m, "a"), history(t, m, "b")
+	if len(a) != 2 || a[1].Content != "In auth.go" || a[1].Session != "a" {
+		t.Errorf("session a = %+v", a)
+	}
+	if len(b) != 2 || b[1].Content != "Looks fine" {
+		t.Errorf("session b = %+v", b)
+	}
+	if len(rec.reasons()) != 2 {
+		t.Errorf("expected both rounds to end, got %v", rec.reasons())
+	}
+
+	sessions, err := m.Sessions(ctx, 10)
+	if err != nil || len(sessions) != 2 {
+		t.Fatalf("sessions = %+v, %v", sessions, err)
+	}
+	if sessions[0].Title == "" || sessions[0].MessageCount != 2 {
+		t.Errorf("unexpected session %+v", sessions[0])
+	}
+
+	if err := m.HandleUserMessage(ctx, domain.ChatMessage{Session: "../etc", Content: "hi"}); !errors.Is(err, ErrInvalidSession) {
+		t.Errorf("expected ErrInvalidSession, got %v", err)
+	}
+	if _, err := m.Transcript(ctx, "missing"); !errors.Is(err, domain.ErrChatSessionNotFound) {
+		t.Errorf("expected ErrChatSessionNotFound, got %v", err)
+	}
+}
+
+func TestGroupChat_ResumesFromStore(t *testing.T) {
+	store := NewMemoryChatStore()
+	first := NewGroupChatManager(slog.Default(), (&recorder{}).publish, Config{Store: store})
+
+	created, err := first.CreateSession(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := first.HandleUserMessage(ctx, domain.ChatMessage{Session: created.ID, Sender: "user", Content: "Where is the login handler?"}); err != nil {
+		t.Fatal(err)
+	}
+	settle(t, first)
+	if err := first.ProcessAgentResponse(ctx, chatEvent(t, "Liaison", domain.ChatMessage{Session: created.ID, Content: "In auth.go"})); err != nil {
+		t.Fatal(err)
+	}
+	settle(t, first)
+
+	// The round ended, so the session was dropped from memory
+	first.mu.Lock()
+	cached := len(first.sessions)
+	first.mu.Unlock()
+	if cached != 0 {
+		t.Errorf("%d sessions still cached after their round ended", cached)
+	}
+
+	// A restarted manager resumes the conversation
+	rec := &recorder{}
+	second := NewGroupChatManager(slog.Default(), rec.publish, Config{Store: store})
+	tr, err := second.Transcript(ctx, created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.Session.Title != "Where is the login handler?" || tr.Session.MessageCount != 2 || len(tr.Messages) != 2 {
+		t.Errorf("unexpected transcript %+v", tr)
+	}
+
+	if err := second.HandleUserMessage(ctx, domain.ChatMessage{Session: created.ID, Sender: "user", Content: "And logout?"}); err != nil {
+		t.Fatal(err)
+	}
+	settle(t, second)
+	var wake domain.ChatWake
+	json.Unmarshal(rec.last(t, "agent/Liaison/wake").Data, &wake)
+	if len(wake.History) != 3 || wake.History[1].Content != "In auth.go" {
+		t.Errorf("resumed wake = %+v", wake)
+	}
+
+	sessions, err := second.Sessions(ctx, 0)
+	if err != nil || len(sessions) != 1 || sessions[0].MessageCount != 3 {
+		t.Errorf("sessions = %+v, %v", sessions, err)
+	}
+}
+
+// flakyChatStore fails the next failures message writes.
+type flakyChatStore struct {
+	*MemoryChatStore
+	mu       sync.Mutex
+	failures int
+}
+
+func (s *flakyChatStore) AppendChatMessage(ctx context.Context, msg domain.ChatMessage) error {
+	s.mu.Lock()
+	if s.failures > 0 {
+		s.failures--
+		s.mu.Unlock()
+		return errors.New("connection reset")
+	}
+	s.mu.Unlock()
+	return s.MemoryChatStore.AppendChatMessage(ctx, msg)
+}
+
+func TestGroupChat_RetriesFailedWrites(t *testing.T) {
+	// The user message, the reply and the retry at the end of the round all fail
+	store := &flakyChatStore{MemoryChatStore: NewMemoryChatStore(), failures: 3}
+	m := NewGroupChatManager(slog.Default(), (&recorder{}).publish, Config{Store: store})
+
+	if err := m.HandleUserMessage(ctx, domain.ChatMessage{Session: "s", Sender: "user", Content: "Where is login?"}); err != nil {
+		t.Fatal(err)
+	}
+	settle(t, m)
+	if err := m.ProcessAgentResponse(ctx, chatEvent(t, "Liaison", domain.ChatMessage{Session: "s", Content: "In auth.go"})); err != nil {
+		t.Fatal(err)
+	}
+	settle(t, m)
+
+	// The unsaved session stays in memory
+	if got := history(t, m, "s"); len(got) != 2 {
+		t.Fatalf("expected the session to stay in memory, got %+v", got)
+	}
+	if stored, _ := store.ListChatMessages(ctx, "s"); len(stored) != 0 {
+		t.Fatalf("expected nothing stored yet, got %+v", stored)
+	}
+
+	// The next message writes the backlog, in order
+	if err := m.HandleUserMessage(ctx, domain.ChatMessage{Session: "s", Sender: "user", Content: "And logout?"}); err != nil {
+		t.Fatal(err)
+	}
+	settle(t, m)
+	stored, _ := store.ListChatMessages(ctx, "s")
+	var contents []string
+	for _, msg := range stored {
+		contents = append(contents, msg.Content)
+	}
+	if want := []string{"Where is login?", "In auth.go", "And logout?"}; !reflect.DeepEqual(contents, want) {
+		t.Errorf("stored messages = %q, want %q", contents, want)
+	}
+
+	// Once written, the session is dropped from memory when its round ends
+	if err := m.ProcessAgentResponse(ctx, chatEvent(t, "Liaison", domain.ChatMessage{Session: "s", Content: "In auth.go too"})); err != nil {
+		t.Fatal(err)
+	}
+	settle(t, m)
+	m.mu.Lock()
+	cached := len(m.sessions)
+	m.mu.Unlock()
+	if cached != 0 {
+		t.Errorf("%d sessions still cached after their writes went through", cached)
+	}
+	if stored, _ := store.ListChatMessages(ctx, "s"); len(stored) != 4 {
+		t.Errorf("expected 4 stored messages, got %d", len(stored))
+	}
+}
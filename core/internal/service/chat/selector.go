@@ -0,0 +1,181 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+)
+
+// Speaker selection strategies (CHAT_SELECTION).
+const (
+	SelectRoundRobin = "round_robin" // Cycle through the roster
+	SelectAuto       = "auto"        // The LLM picks from the history and agent descriptions
+	SelectManual     = "manual"      // Only @mentioned agents speak
+)
+
+// userSpeaker is what the LLM answers to hand the turn back to the user.
+const userSpeaker = "USER"
+
+// selectorHistory is how many recent messages the LLM sees when picking a speaker.
+const selectorHistory = 20
+
+// Participant is an agent of the group chat roster.
+type Participant struct {
+	ID          string
+	Description string
+}
+
+// Turn is what a selector knows when picking the next speaker.
+type Turn struct {
+	Roster      []Participant
+	History     []domain.ChatMessage // Oldest first; the last message was just said
+	LastSpeaker string               // Sender of the last message ("user" or an agent ID)
+}
+
+// SpeakerSelector picks who speaks next in a group chat.
+type SpeakerSelector interface {
+	// NextSpeaker returns the ID of the agent to wake, or "" to give the turn back to the user.
+	NextSpeaker(ctx context.Context, turn Turn) (string, error)
+}
+
+// NewSelector returns the named strategy. Explicit @mentions take precedence in all of them.
+func NewSelector(name string, llm domain.LLMProvider) (SpeakerSelector, error) {
+	switch name {
+	case "", SelectRoundRobin:
+		return Mentions{Fallback: RoundRobin{}}, nil
+	case SelectAuto:
+		if llm == nil {
+			return nil, fmt.Errorf("speaker selection %q needs a language model", name)
+		}
+		return Mentions{Fallback: &LLMSelector{LLM: llm, Fallback: RoundRobin{}}}, nil
+	case SelectManual:
+		return Mentions{}, nil
+	}
+	return nil, fmt.Errorf("unknown speaker selection %q (want %s, %s or %s)", name, SelectRoundRobin, SelectAuto, SelectManual)
+}
+
+// RoundRobin wakes the roster agent after the last one that spoke. An agent never
+// follows itself: a single-agent roster hands the turn back to the user.
+type RoundRobin struct{}
+
+func (RoundRobin) NextSpeaker(_ context.Context, turn Turn) (string, error) {
+	if len(turn.Roster) == 0 {
+		return "", nil
+	}
+	next := turn.Roster[0].ID
+	for i := len(turn.History) - 1; i >= 0; i-- {
+		if idx := rosterIndex(turn.Roster, turn.History[i].Sender); idx >= 0 {
+			next = turn.Roster[(idx+1)%len(turn.Roster)].ID
+			break
+		}
+	}
+	if next == turn.LastSpeaker {
+		return "", nil
+	}
+	return next, nil
+}
+
+// mentionPattern matches "@AgentID".
+var mentionPattern = regexp.MustCompile(`@([\w.-]+)`)
+
+// Mentions wakes the first roster agent @mentioned in the last message, other than its
+// sender. Without a mention it defers to Fallback, or gives the turn back to the user.
+type Mentions struct {
+	Fallback SpeakerSelector
+}
+
+func (s Mentions) NextSpeaker(ctx context.Context, turn Turn) (string, error) {
+	if len(turn.History) > 0 {
+		for _, m := range mentionPattern.FindAllStringSubmatch(turn.History[len(turn.History)-1].Content, -1) {
+			if idx := rosterIndex(turn.Roster, strings.TrimRight(m[1], ".-")); idx >= 0 && turn.Roster[idx].ID != turn.LastSpeaker {
+				return turn.Roster[idx].ID, nil
+			}
+		}
+	}
+	if s.Fallback == nil {
+		return "", nil
+	}
+	return s.Fallback.NextSpeaker(ctx, turn)
+}
+
+// LLMSelector asks the LLM who should speak next, given the conversation and the
+// roster's descriptions. Failed requests and unusable answers go to Fallback.
+type LLMSelector struct {
+	LLM      domain.LLMProvider
+	Fallback SpeakerSelector
+}
+
+func (s *LLMSelector) NextSpeaker(ctx context.Context, turn Turn) (string, error) {
+	if len(turn.Roster) == 0 {
+		return "", nil
+	}
+	answer, err := s.LLM.GenerateCode(ctx, selectorPrompt(turn))
+	if err == nil {
+		if id, ok := parseSpeaker(answer, turn.Roster); ok {
+			return id, nil
+		}
+		err = fmt.Errorf("unusable answer %q", strings.TrimSpace(answer))
+	}
+	if s.Fallback == nil {
+		return "", fmt.Errorf("speaker selection: %w", err)
+	}
+	return s.Fallback.NextSpeaker(ctx, turn)
+}
+
+func selectorPrompt(turn Turn) string {
+	var b strings.Builder
+	b.WriteString("You coordinate a group chat between a human user and these agents:\n")
+	for _, p := range turn.Roster {
+		desc := p.Description
+		if desc == "" {
+			desc = "(no description)"
+		}
+		fmt.Fprintf(&b, "- %s: %s\n", p.ID, desc)
+	}
+
+	b.WriteString("\n## Conversation\n")
+	history := turn.History
+	if len(history) > selectorHistory {
+		history = history[len(history)-selectorHistory:]
+	}
+	for _, msg := range history {
+		fmt.Fprintf(&b, "%s: %s\n", msg.Sender, msg.Content)
+	}
+
+	fmt.Fprintf(&b, "\nWho should speak next? Answer with ONLY one agent ID from the list, or %s if the user should answer.\n", userSpeaker)
+	return b.String()
+}
+
+// parseSpeaker finds the chosen agent in an LLM answer: an exact ID, or else the
+// first roster ID the answer mentions. USER means the user's turn.
+func parseSpeaker(answer string, roster []Participant) (string, bool) {
+	answer = strings.Trim(strings.TrimSpace(answer), "@.\"'`*")
+	if strings.EqualFold(answer, userSpeaker) {
+		return "", true
+	}
+	if idx := rosterIndex(roster, answer); idx >= 0 {
+		return roster[idx].ID, true
+	}
+
+	first, pos := "", -1
+	lower := strings.ToLower(answer)
+	for _, p := range roster {
+		if i := strings.Index(lower, strings.ToLower(p.ID)); i >= 0 && (pos < 0 || i < pos) {
+			first, pos = p.ID, i
+		}
+	}
+	return first, pos >= 0
+}
+
+// rosterIndex returns the position of an agent in the roster (case-insensitive), or -1.
+func rosterIndex(roster []Participant, id string) int {
+	for i, p := range roster {
+		if strings.EqualFold(p.ID, id) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,134 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+)
+
+// answerLLM answers every prompt with the same text (or error) and records the prompts.
+type answerLLM struct {
+	answer  string
+	err     error
+	prompts []string
+}
+
+func (l *answerLLM) GenerateCode(_ context.Context, prompt string) (string, error) {
+	l.prompts = append(l.prompts, prompt)
+	return l.answer, l.err
+}
+func (l *answerLLM) CheckHealth(context.Context) error { return nil }
+func (l *answerLLM) Embed(context.Context, string) ([]float32, error) {
+	return nil, errors.New("not supported")
+}
+
+var testRoster = []Participant{
+	{ID: "Liaison", Description: "Talks to the user"},
+	{ID: "SystemArchitect", Description: "Reviews designs"},
+	{ID: "SoftwareEngineer", Description: "Writes code"},
+}
+
+func turnAfter(messages ...string) Turn {
+	var turn Turn
+	turn.Roster = testRoster
+	for _, m := range messages {
+		sender, content, _ := strings.Cut(m, ": ")
+		turn.History = append(turn.History, domain.ChatMessage{Sender: sender, Content: content})
+		turn.LastSpeaker = sender
+	}
+	return turn
+}
+
+func TestRoundRobin(t *testing.T) {
+	cases := []struct {
+		name string
+		turn Turn
+		want string
+	}{
+		{"first message", turnAfter("user: hi"), "Liaison"},
+		{"cycles", turnAfter("user: hi", "Liaison: hello"), "SystemArchitect"},
+		{"wraps", turnAfter("user: hi", "SoftwareEngineer: done"), "Liaison"},
+		{"continues after the user", turnAfter("user: hi", "Liaison: hello", "user: and?"), "SystemArchitect"},
+		{"no repeat", Turn{Roster: testRoster[:1], History: turnAfter("Liaison: hi").History, LastSpeaker: "Liaison"}, ""},
+	}
+	for _, c := range cases {
+		got, err := RoundRobin{}.NextSpeaker(context.Background(), c.turn)
+		if err != nil || got != c.want {
+			t.Errorf("%s: got %q, %v, want %q", c.name, got, err, c.want)
+		}
+	}
+}
+
+func TestMentions(t *testing.T) {
+	sel := Mentions{Fallback: RoundRobin{}}
+	cases := []struct {
+		name string
+		turn Turn
+		want string
+	}{
+		{"mention", turnAfter("user: @softwareengineer please fix it."), "SoftwareEngineer"},
+		{"trailing punctuation", turnAfter("user: what do you think, @SystemArchitect?"), "SystemArchitect"},
+		{"self mention skipped", turnAfter("user: hi", "Liaison: I'm @Liaison, asking @SystemArchitect."), "SystemArchitect"},
+		{"unknown falls back", turnAfter("user: @nobody hi"), "Liaison"},
+	}
+	for _, c := range cases {
+		got, err := sel.NextSpeaker(context.Background(), c.turn)
+		if err != nil || got != c.want {
+			t.Errorf("%s: got %q, %v, want %q", c.name, got, err, c.want)
+		}
+	}
+
+	// Without a fallback, no mention means the user's turn
+	if got, _ := (Mentions{}).NextSpeaker(context.Background(), turnAfter("user: hi")); got != "" {
+		t.Errorf("manual selection without a mention picked %q", got)
+	}
+}
+
+func TestLLMSelector(t *testing.T) {
+	cases := []struct {
+		answer string
+		err    error
+		want   string
+	}{
+		{answer: "SystemArchitect", want: "SystemArchitect"},
+		{answer: "  `@softwareengineer`.", want: "SoftwareEngineer"},
+		{answer: "The SystemArchitect should review, then SoftwareEngineer.", want: "SystemArchitect"},
+		{answer: "USER", want: ""},
+		{answer: "nobody in particular", want: "SystemArchitect"}, // Round-robin fallback
+		{err: errors.New("down"), want: "SystemArchitect"},
+	}
+	for _, c := range cases {
+		llm := &answerLLM{answer: c.answer, err: c.err}
+		sel := &LLMSelector{LLM: llm, Fallback: RoundRobin{}}
+		got, err := sel.NextSpeaker(context.Background(), turnAfter("user: hi", "Liaison: hello"))
+		if err != nil || got != c.want {
+			t.Errorf("answer %q / %v: got %q, %v, want %q", c.answer, c.err, got, err, c.want)
+		}
+		if len(llm.prompts) != 1 || !strings.Contains(llm.prompts[0], "- SystemArchitect: Reviews designs") || !strings.Contains(llm.prompts[0], "Liaison: hello") {
+			t.Errorf("prompt lacks the roster or history:\n%s", llm.prompts)
+		}
+	}
+
+	// Without a fallback, failures are reported
+	sel := &LLMSelector{LLM: &answerLLM{answer: "?"}}
+	if _, err := sel.NextSpeaker(context.Background(), turnAfter("user: hi")); err == nil {
+		t.Error("expected an error for an unusable answer")
+	}
+}
+
+func TestNewSelector(t *testing.T) {
+	if _, err := NewSelector(SelectAuto, nil); err == nil {
+		t.Error("auto selection without an LLM should fail")
+	}
+	if _, err := NewSelector("random", nil); err == nil {
+		t.Error("unknown selection should fail")
+	}
+	for _, name := range []string{"", SelectRoundRobin, SelectManual} {
+		if _, err := NewSelector(name, nil); err != nil {
+			t.Errorf("%q: %v", name, err)
+		}
+	}
+}
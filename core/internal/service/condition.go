@@ -0,0 +1,556 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+)
+
+// Condition is a compiled mission step "when:" expression.
+//
+// Grammar (whitespace insensitive):
+//
+//	expr    := and ( "||" and )*
+//	and     := unary ( "&&" unary )*
+//	unary   := "!" unary | compare
+//	compare := operand [ ( "==" | "!=" | "<" | "<=" | ">" | ">=" ) operand ]
+//	operand := "(" expr ")" | string | number | true | false | null | path
+//	path    := [ "$." ] ident ( "." ident | "[" index "]" )*
+//
+// Paths resolve against the CloudEvent envelope (type, source, id, time, specversion)
+// and its decoded payload under "data", e.g. `data.severity == "critical"` or `$.data.readings[0] > 80`.
+// A bare operand is truthy when it exists and is not false, null, "" or 0.
+// A missing field or index is null. Evaluation fails when a path goes through a value
+// that is neither an object nor an array, or when "<", "<=", ">" or ">=" compares
+// anything but two numbers or two strings (null included: guard with `x != null && x > 1`).
+type Condition struct {
+	source string
+	root   condNode
+}
+
+// ParseCondition compiles an expression. An empty expression always evaluates to true.
+func ParseCondition(expr string) (*Condition, error) {
+	c := &Condition{source: expr}
+	if strings.TrimSpace(expr) == "" {
+		return c, nil
+	}
+
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition %q: %w", expr, err)
+	}
+	p := &condParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition %q: %w", expr, err)
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("invalid condition %q: unexpected %q", expr, p.peek().text)
+	}
+	c.root = root
+	return c, nil
+}
+
+// String returns the original expression.
+func (c *Condition) String() string {
+	return c.source
+}
+
+// Eval evaluates the condition against an event.
+func (c *Condition) Eval(event domain.CloudEvent) (bool, error) {
+	if c.root == nil {
+		return true, nil
+	}
+
+	env := map[string]interface{}{
+		"id":          event.ID,
+		"source":      event.Source,
+		"type":        event.Type,
+		"specversion": event.SpecVersion,
+		"time":        event.Time.Format(time.RFC3339Nano),
+	}
+	if len(event.Data) > 0 {
+		var data interface{}
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			// Non-JSON payloads are exposed as a raw string
+			data = string(event.Data)
+		}
+		env["data"] = data
+	}
+
+	v, err := c.root.eval(env)
+	if err != nil {
+		return false, err
+	}
+	return truthy(v), nil
+}
+
+// --- AST ---
+
+type condNode interface {
+	eval(env map[string]interface{}) (interface{}, error)
+}
+
+type literalNode struct{ value interface{} }
+
+func (n literalNode) eval(map[string]interface{}) (interface{}, error) { return n.value, nil }
+
+// pathSegment is either a map key or (when isIndex) an array index.
+type pathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+type pathNode struct{ segments []pathSegment }
+
+func (n pathNode) eval(env map[string]interface{}) (interface{}, error) {
+	var cur interface{} = env
+	for i, seg := range n.segments {
+		if seg.isIndex {
+			arr, ok := cur.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("cannot index %s (%s) with [%d]", n.prefix(i), typeName(cur), seg.index)
+			}
+			if seg.index < 0 || seg.index >= len(arr) {
+				return nil, nil
+			}
+			cur = arr[seg.index]
+			continue
+		}
+		obj, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot read field %q of %s (%s)", seg.key, n.prefix(i), typeName(cur))
+		}
+		if cur, ok = obj[seg.key]; !ok {
+			return nil, nil
+		}
+	}
+	return cur, nil
+}
+
+// prefix renders the first n segments of the path, for errors.
+func (n pathNode) prefix(count int) string {
+	var b strings.Builder
+	for i, seg := range n.segments[:count] {
+		switch {
+		case seg.isIndex:
+			fmt.Fprintf(&b, "[%d]", seg.index)
+		case i > 0:
+			b.WriteString("." + seg.key)
+		default:
+			b.WriteString(seg.key)
+		}
+	}
+	return b.String()
+}
+
+type notNode struct{ operand condNode }
+
+func (n notNode) eval(env map[string]interface{}) (interface{}, error) {
+	v, err := n.operand.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	return !truthy(v), nil
+}
+
+type logicalNode struct {
+	op          string // "&&" or "||"
+	left, right condNode
+}
+
+func (n logicalNode) eval(env map[string]interface{}) (interface{}, error) {
+	l, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	// Short-circuit
+	if n.op == "&&" && !truthy(l) {
+		return false, nil
+	}
+	if n.op == "||" && truthy(l) {
+		return true, nil
+	}
+	r, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	return truthy(r), nil
+}
+
+type compareNode struct {
+	op          string
+	left, right condNode
+}
+
+func (n compareNode) eval(env map[string]interface{}) (interface{}, error) {
+	l, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	r, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return equal(l, r), nil
+	case "!=":
+		return !equal(l, r), nil
+	}
+
+	// Ordering: numbers with numbers, strings with strings
+	if lf, ok := toNumber(l); ok {
+		if rf, ok := toNumber(r); ok {
+			return compareOrdered(n.op, lf < rf, lf == rf), nil
+		}
+	}
+	ls, lok := l.(string)
+	rs, rok := r.(string)
+	if lok && rok {
+		return compareOrdered(n.op, ls < rs, ls == rs), nil
+	}
+	return nil, fmt.Errorf("cannot compare %s %s %s", typeName(l), n.op, typeName(r))
+}
+
+func compareOrdered(op string, less, eq bool) bool {
+	switch op {
+	case "<":
+		return less
+	case "<=":
+		return less || eq
+	case ">":
+		return !less && !eq
+	case ">=":
+		return !less
+	}
+	return false
+}
+
+func equal(a, b interface{}) bool {
+	if af, ok := toNumber(a); ok {
+		bf, ok := toNumber(b)
+		return ok && af == bf
+	}
+	switch av := a.(type) {
+	case nil:
+		return b == nil
+	case string:
+		bv, ok := b.(string)
+		return ok && av == bv
+	case bool:
+		bv, ok := b.(bool)
+		return ok && av == bv
+	}
+	// Objects and arrays compare structurally via JSON
+	aj, _ := json.Marshal(a)
+	bj, _ := json.Marshal(b)
+	return string(aj) == string(bj)
+}
+
+func toNumber(v interface{}) (float64, bool) {
+	f, ok := v.(float64)
+	return f, ok
+}
+
+// typeName names the JSON type of a value, for errors.
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case []interface{}:
+		return "array"
+	}
+	return "object"
+}
+
+func truthy(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return t
+	case string:
+		return t != ""
+	case float64:
+		return t != 0
+	}
+	return true
+}
+
+// --- Lexer ---
+
+type tokenKind int
+
+const (
+	tokOp tokenKind = iota
+	tokString
+	tokNumber
+	tokIdent
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case c == '"' || c == '\'':
+			// Quoted string with backslash escapes
+			var sb strings.Builder
+			j := i + 1
+			for ; j < len(s) && s[j] != c; j++ {
+				if s[j] == '\\' && j+1 < len(s) {
+					j++
+				}
+				sb.WriteByte(s[j])
+			}
+			if j >= len(s) {
+				return nil, fmt.Errorf("unterminated string at offset %d", i)
+			}
+			tokens = append(tokens, token{tokString, sb.String()})
+			i = j + 1
+
+		case strings.HasPrefix(s[i:], "&&"), strings.HasPrefix(s[i:], "||"),
+			strings.HasPrefix(s[i:], "=="), strings.HasPrefix(s[i:], "!="),
+			strings.HasPrefix(s[i:], "<="), strings.HasPrefix(s[i:], ">="):
+			tokens = append(tokens, token{tokOp, s[i : i+2]})
+			i += 2
+
+		case strings.ContainsRune("!<>()[].", rune(c)):
+			tokens = append(tokens, token{tokOp, string(c)})
+			i++
+
+		case c == '-' || (c >= '0' && c <= '9'):
+			j := i + 1
+			for j < len(s) && (s[j] == '.' || s[j] == 'e' || s[j] == 'E' || (s[j] >= '0' && s[j] <= '9')) {
+				j++
+			}
+			tokens = append(tokens, token{tokNumber, s[i:j]})
+			i = j
+
+		case c == '$' || c == '_' || unicode.IsLetter(rune(c)):
+			j := i + 1
+			for j < len(s) && (s[j] == '_' || s[j] == '-' || unicode.IsLetter(rune(s[j])) || unicode.IsDigit(rune(s[j]))) {
+				j++
+			}
+			tokens = append(tokens, token{tokIdent, s[i:j]})
+			i = j
+
+		default:
+			return nil, fmt.Errorf("unexpected character %q at offset %d", c, i)
+		}
+	}
+	return tokens, nil
+}
+
+// --- Parser ---
+
+type condParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *condParser) done() bool { return p.pos >= len(p.tokens) }
+
+func (p *condParser) peek() token {
+	if p.done() {
+		return token{}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *condParser) acceptOp(ops ...string) (string, bool) {
+	t := p.peek()
+	if p.done() || t.kind != tokOp {
+		return "", false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *condParser) parseOr() (condNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.acceptOp("||"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalNode{op: "||", left: left, right: right}
+	}
+}
+
+func (p *condParser) parseAnd() (condNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.acceptOp("&&"); !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalNode{op: "&&", left: left, right: right}
+	}
+}
+
+func (p *condParser) parseUnary() (condNode, error) {
+	if _, ok := p.acceptOp("!"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand: operand}, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *condParser) parseCompare() (condNode, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.acceptOp("==", "!=", "<=", ">=", "<", ">")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return compareNode{op: op, left: left, right: right}, nil
+}
+
+func (p *condParser) parseOperand() (condNode, error) {
+	if p.done() {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+
+	if _, ok := p.acceptOp("("); ok {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := p.acceptOp(")"); !ok {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		return inner, nil
+	}
+
+	t := p.peek()
+	switch t.kind {
+	case tokString:
+		p.pos++
+		return literalNode{value: t.text}, nil
+	case tokNumber:
+		p.pos++
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", t.text)
+		}
+		return literalNode{value: f}, nil
+	case tokIdent:
+		switch t.text {
+		case "true":
+			p.pos++
+			return literalNode{value: true}, nil
+		case "false":
+			p.pos++
+			return literalNode{value: false}, nil
+		case "null":
+			p.pos++
+			return literalNode{value: nil}, nil
+		}
+		return p.parsePath()
+	}
+	return nil, fmt.Errorf("unexpected %q", t.text)
+}
+
+func (p *condParser) parsePath() (condNode, error) {
+	t := p.peek()
+	p.pos++
+
+	name := t.text
+	if name == "$" {
+		// JSONPath-style root: "$.data.x"
+		if _, ok := p.acceptOp("."); !ok {
+			return nil, fmt.Errorf("expected '.' after '$'")
+		}
+		next := p.peek()
+		if p.done() || next.kind != tokIdent {
+			return nil, fmt.Errorf("expected field name after '$.'")
+		}
+		p.pos++
+		name = next.text
+	}
+
+	segments := []pathSegment{{key: name}}
+	for {
+		if _, ok := p.acceptOp("."); ok {
+			next := p.peek()
+			if p.done() || next.kind != tokIdent {
+				return nil, fmt.Errorf("expected field name after '.'")
+			}
+			p.pos++
+			segments = append(segments, pathSegment{key: next.text})
+			continue
+		}
+		if _, ok := p.acceptOp("["); ok {
+			next := p.peek()
+			if p.done() {
+				return nil, fmt.Errorf("unexpected end of expression")
+			}
+			p.pos++
+			seg := pathSegment{key: next.text}
+			if next.kind == tokNumber {
+				idx, err := strconv.Atoi(next.text)
+				if err != nil {
+					return nil, fmt.Errorf("invalid index %q", next.text)
+				}
+				seg = pathSegment{index: idx, isIndex: true}
+			} else if next.kind != tokString {
+				return nil, fmt.Errorf("invalid index %q", next.text)
+			}
+			if _, ok := p.acceptOp("]"); !ok {
+				return nil, fmt.Errorf("missing closing bracket")
+			}
+			segments = append(segments, seg)
+			continue
+		}
+		return pathNode{segments: segments}, nil
+	}
+}
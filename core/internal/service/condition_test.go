@@ -0,0 +1,97 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+)
+
+func TestCondition_Eval(t *testing.T) {
+	alert, _ := domain.NewEvent("agent.trend_scout", "swarm.security.alert", map[string]interface{}{
+		"severity": "critical",
+		"value":    91.5,
+		"tags":     []string{"cpu", "thermal"},
+		"meta":     map[string]interface{}{"acked": false},
+	})
+
+	cases := []struct {
+		expr string
+		want bool
+	}{
+		{``, true},
+		{`type == "swarm.security.alert"`, true},
+		{`type == "swarm.security.alert" && data.severity == "critical"`, true},
+		{`type == "swarm.security.alert" && data.severity == 'warning'`, false},
+		{`$.data.value > 90`, true},
+		{`data.value >= 91.5 && data.value < 100`, true},
+		{`data.tags[1] == "thermal"`, true},
+		{`data.tags[5] == "thermal"`, false},
+		{`data["severity"] != "info"`, true},
+		{`!data.meta.acked`, true},
+		{`data.missing`, false},
+		{`data.missing == null`, true},
+		{`source == "device-mock" || (data.severity == "critical" && data.value > 90)`, true},
+		{`!(type == "swarm.security.alert")`, false},
+	}
+
+	for _, c := range cases {
+		cond, err := ParseCondition(c.expr)
+		if err != nil {
+			t.Errorf("ParseCondition(%q) failed: %v", c.expr, err)
+			continue
+		}
+		got, err := cond.Eval(alert)
+		if err != nil {
+			t.Errorf("Eval(%q) failed: %v", c.expr, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("Eval(%q) = %v, want %v", c.expr, got, c.want)
+		}
+	}
+}
+
+func TestCondition_EvalErrors(t *testing.T) {
+	alert, _ := domain.NewEvent("agent.trend_scout", "swarm.security.alert", map[string]interface{}{
+		"severity": "critical",
+		"value":    91.5,
+	})
+
+	for _, expr := range []string{
+		`data.value > "a"`,
+		`data.severity >= 3`,
+		`data.missing > 90`,
+		`data.severity.level == "high"`,
+		`data.value[0] == 1`,
+		`type == "x" || data.value < true`,
+	} {
+		cond, err := ParseCondition(expr)
+		if err != nil {
+			t.Fatalf("ParseCondition(%q) failed: %v", expr, err)
+		}
+		if _, err := cond.Eval(alert); err == nil {
+			t.Errorf("Expected Eval(%q) to fail", expr)
+		}
+	}
+
+	// Short-circuiting guards the comparison
+	cond, _ := ParseCondition(`data.missing != null && data.missing > 90`)
+	if ok, err := cond.Eval(alert); err != nil || ok {
+		t.Errorf("guarded comparison = %v, %v", ok, err)
+	}
+}
+
+func TestParseCondition_Errors(t *testing.T) {
+	for _, expr := range []string{
+		`type ==`,
+		`(type == "a"`,
+		`data.tags[`,
+		`type == "unterminated`,
+		`type = "a"`,
+		`type == "a" "b"`,
+	} {
+		if _, err := ParseCondition(expr); err == nil {
+			t.Errorf("Expected ParseCondition(%q) to fail", expr)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+)
+
+// DefaultConfigWatchInterval is how often the config file is checked for changes.
+const DefaultConfigWatchInterval = 2 * time.Second
+
+// AgentBuilder constructs an agent from its configuration.
+type AgentBuilder func(cfg domain.AgentConfig) (domain.Agent, error)
+
+// ReloadResult lists what a reload changed.
+type ReloadResult struct {
+	AgentsAdded     []string `json:"agents_added"`
+	AgentsUpdated   []string `json:"agents_updated"`
+	AgentsRemoved   []string `json:"agents_removed"`
+	MissionsAdded   []string `json:"missions_added"`
+	MissionsUpdated []string `json:"missions_updated"`
+	MissionsRemoved []string `json:"missions_removed"`
+}
+
+// ConfigReloader (re)applies agents.yaml without restarting the process.
+// A reload is all-or-nothing: the new config is parsed, every new or changed agent is
+// built and every mission is validated before anything is swapped, and if the missions
+// then fail to apply, the previous agents are put back. Agents whose configuration did
+// not change keep their instance (and therefore their state).
+//
+// With a catalog, missions follow one rule, at startup as while running: the file is
+// diffed against the missions it last applied (stored with the catalog), and only the
+// missions it adds, changes or removes are written to the store (see ApplyConfig).
+// Missions the file did not change keep whatever the API did to them.
+type ConfigReloader struct {
+	path     string
+	build    AgentBuilder
+	registry *AgentRegistry
+	missions *MissionManager
+	catalog  *MissionCatalog // Optional: with a store, missions are applied to the missions table
+
+	mu       sync.Mutex
+	agents   map[string]domain.AgentConfig // Applied agent configs
+	instance map[string]domain.Agent       // Agents built from them
+	applied  map[string]domain.Mission     // Applied configured missions
+	resumed  bool                          // applied was loaded from the catalog
+	checksum [sha256.Size]byte             // Checksum of the last file seen by Watch
+}
+
+func NewConfigReloader(path string, build AgentBuilder, registry *AgentRegistry, missions *MissionManager) *ConfigReloader {
+	return &ConfigReloader{
+		path:     path,
+		build:    build,
+		registry: registry,
+		missions: missions,
+		agents:   make(map[string]domain.AgentConfig),
+		instance: make(map[string]domain.Agent),
+		applied:  make(map[string]domain.Mission),
+	}
+}
+
+// SetCatalog makes reloads apply mission changes to the mission store instead of
+// loading them into the MissionManager directly.
+func (r *ConfigReloader) SetCatalog(catalog *MissionCatalog) {
+	r.catalog = catalog
+}
+
+// Reload reads the config file and applies the differences to the running system.
+// If the new config is invalid or cannot be applied, the running agents are kept and
+// the error explains why.
+func (r *ConfigReloader) Reload(ctx context.Context) (*ReloadResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sysCfg, err := ReadConfig(r.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", r.path, err)
+	}
+	if r.catalog != nil && !r.resumed {
+		// Diff against what the file last applied, even if that was before a restart
+		configured, err := r.catalog.Configured(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the applied missions: %w", err)
+		}
+		for _, m := range configured {
+			r.applied[m.ID] = m
+		}
+		r.resumed = true
+	}
+
+	// 1. Build new and changed agents (nothing is swapped yet)
+	result := &ReloadResult{}
+	nextCfg := make(map[string]domain.AgentConfig, len(sysCfg.Agents))
+	nextAgents := make([]domain.Agent, 0, len(sysCfg.Agents))
+	built := make(map[string]domain.Agent, len(sysCfg.Agents))
+	for _, cfg := range sysCfg.Agents {
+		nextCfg[cfg.ID] = cfg
+
+		old, exists := r.agents[cfg.ID]
+		if exists && reflect.DeepEqual(old, cfg) {
+			built[cfg.ID] = r.instance[cfg.ID]
+			nextAgents = append(nextAgents, r.instance[cfg.ID])
+			continue
+		}
+
+		a, err := r.build(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("agent %q: %w", cfg.ID, err)
+		}
+		built[cfg.ID] = a
+		nextAgents = append(nextAgents, a)
+		if exists {
+			result.AgentsUpdated = append(result.AgentsUpdated, cfg.ID)
+		} else {
+			result.AgentsAdded = append(result.AgentsAdded, cfg.ID)
+		}
+	}
+	for id := range r.agents {
+		if _, ok := nextCfg[id]; !ok {
+			result.AgentsRemoved = append(result.AgentsRemoved, id)
+		}
+	}
+
+	// 2. Validate missions against the new agent set
+	known := make(map[string]bool, len(nextCfg))
+	for id := range nextCfg {
+		known[id] = true
+	}
+	missions, err := BuildMissions(sysCfg, known)
+	if err != nil {
+		return nil, err
+	}
+
+	nextMissions := make(map[string]domain.Mission, len(missions))
+	var added, changed []domain.Mission
+	for _, m := range missions {
+		nextMissions[m.ID] = m
+
+		old, exists := r.applied[m.ID]
+		switch {
+		case !exists:
+			added = append(added, m)
+			result.MissionsAdded = append(result.MissionsAdded, m.ID)
+		case !sameMission(old, m):
+			changed = append(changed, m)
+			result.MissionsUpdated = append(result.MissionsUpdated, m.ID)
+		}
+	}
+	for id := range r.applied {
+		if _, ok := nextMissions[id]; !ok {
+			result.MissionsRemoved = append(result.MissionsRemoved, id)
+		}
+	}
+
+	// 3. Swap (new agents are started first; a failing start refuses the reload)
+	previous := r.registry.List()
+	if err := r.registry.Replace(nextAgents); err != nil {
+		return nil, err
+	}
+	for _, cfg := range sysCfg.Agents {
+		r.registry.SetConfig(cfg)
+	}
+
+	if r.catalog != nil {
+		err = r.catalog.ApplyConfig(ctx, added, changed, result.MissionsRemoved)
+	} else {
+		err = r.missions.SyncMissions(missions)
+	}
+	if err != nil {
+		// Put the previous agents back; the applied missions are kept so the next reload retries
+		if rerr := r.registry.Replace(previous); rerr != nil {
+			log.Printf("[CONFIG] Failed to restore previous agents: %v", rerr)
+		}
+		for _, cfg := range r.agents {
+			r.registry.SetConfig(cfg)
+		}
+		return nil, fmt.Errorf("missions failed to apply: %w", err)
+	}
+	r.agents = nextCfg
+	r.instance = built
+	r.applied = nextMissions
+
+	log.Printf("[CONFIG] Reloaded %s: agents +%d ~%d -%d, missions +%d ~%d -%d", r.path,
+		len(result.AgentsAdded), len(result.AgentsUpdated), len(result.AgentsRemoved),
+		len(result.MissionsAdded), len(result.MissionsUpdated), len(result.MissionsRemoved))
+	return result, nil
+}
+
+// Watch reloads the config whenever the file content changes, until ctx is cancelled.
+// An invalid file is reported once and the running config is kept.
+func (r *ConfigReloader) Watch(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultConfigWatchInterval
+	}
+	if sum, err := r.fileChecksum(); err == nil {
+		r.checksum = sum
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		sum, err := r.fileChecksum()
+		if err != nil || sum == r.checksum {
+			continue
+		}
+		r.checksum = sum
+
+		if _, err := r.Reload(ctx); err != nil {
+			log.Printf("[CONFIG] Reload refused: %v", err)
+		}
+	}
+}
+
+// sameMission compares missions as they are stored (nil and empty lists are alike).
+func sameMission(a, b domain.Mission) bool {
+	aj, aerr := json.Marshal(a)
+	bj, berr := json.Marshal(b)
+	return aerr == nil && berr == nil && bytes.Equal(aj, bj)
+}
+
+func (r *ConfigReloader) fileChecksum() ([sha256.Size]byte, error) {
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	return sha256.Sum256(data), nil
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+)
+
+const reloadConfigV1 = `
+agents:
+  - id: "Scout"
+    type: "trend-scout"
+    config: { threshold: 80 }
+  - id: "Reporter"
+    type: "console-reporter"
+missions:
+  - id: "m1"
+    name: "Watch"
+    trigger_topic: "sensor/cpu"
+    agents: ["Scout", "Reporter"]
+`
+
+const reloadConfigV2 = `
+agents:
+  - id: "Scout"
+    type: "trend-scout"
+    config: { threshold: 90 }
+  - id: "Reporter"
+    type: "console-reporter"
+  - id: "Pager"
+    type: "console-reporter"
+missions:
+  - id: "m1"
+    name: "Watch"
+    trigger_topic: "sensor/cpu"
+    agents: ["Scout", "Pager"]
+`
+
+func TestConfigReloader_DiffAndSwap(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agents.yaml")
+	writeConfig(t, path, reloadConfigV1)
+
+	builds := 0
+	build := func(cfg domain.AgentConfig) (domain.Agent, error) {
+		if cfg.ID == "Broken" {
+			return nil, fmt.Errorf("cannot build agent")
+		}
+		builds++
+		return &RecordingAgent{id: cfg.ID}, nil
+	}
+
+	registry := NewAgentRegistry()
+	manager := NewMissionManager(registry, func(string, domain.CloudEvent) {})
+	reloader := NewConfigReloader(path, build, registry, manager)
+	ctx := context.Background()
+
+	if _, err := reloader.Reload(ctx); err != nil {
+		t.Fatalf("Initial reload failed: %v", err)
+	}
+	reporter, _ := registry.Get("Reporter")
+
+	writeConfig(t, path, reloadConfigV2)
+	result, err := reloader.Reload(ctx)
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if builds != 4 {
+		t.Errorf("Expected only new and changed agents to be rebuilt (4 builds), got %d", builds)
+	}
+	if fmt.Sprint(result.AgentsAdded, result.AgentsUpdated, result.MissionsUpdated) != "[Pager] [Scout] [m1]" {
+		t.Errorf("Unexpected diff: %+v", result)
+	}
+	if same, _ := registry.Get("Reporter"); same != reporter {
+		t.Errorf("Unchanged agent should keep its instance")
+	}
+	if active := manager.Missions(); len(active) != 1 || active[0].Agents[1] != "Pager" {
+		t.Errorf("Mission change not applied: %+v", active)
+	}
+
+	// Invalid config: the running configuration is kept
+	writeConfig(t, path, `
+agents:
+  - id: "Scout"
+    type: "trend-scout"
+missions:
+  - id: "m1"
+    trigger_topic: "sensor/cpu"
+    agents: ["Scout", "Ghost"]
+`)
+	if _, err := reloader.Reload(ctx); err == nil {
+		t.Fatalf("Expected reload to be refused")
+	}
+	if len(registry.List()) != 3 {
+		t.Errorf("Refused reload changed the registry: %d agents", len(registry.List()))
+	}
+
+	writeConfig(t, path, `
+agents:
+  - id: "Broken"
+    type: "trend-scout"
+`)
+	if _, err := reloader.Reload(ctx); err == nil {
+		t.Fatalf("Expected reload with an agent that fails to build to be refused")
+	}
+	if len(manager.Missions()) != 1 {
+		t.Errorf("Refused reload changed the missions")
+	}
+}
+
+func writeConfig(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// failingMissionStore fails to seed any mission.
+type failingMissionStore struct{ MemoryMissionStore }
+
+func (s *failingMissionStore) SeedMissions(ctx context.Context, missions []domain.Mission) error {
+	if len(missions) > 0 {
+		return fmt.Errorf("database is down")
+	}
+	return nil
+}
+
+func TestConfigReloader_RestoresAgentsWhenMissionsFail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agents.yaml")
+	writeConfig(t, path, `
+agents:
+  - id: "Scout"
+    type: "trend-scout"
+`)
+	build := func(cfg domain.AgentConfig) (domain.Agent, error) {
+		return &RecordingAgent{id: cfg.ID}, nil
+	}
+
+	registry := NewAgentRegistry()
+	manager := NewMissionManager(registry, func(string, domain.CloudEvent) {})
+	reloader := NewConfigReloader(path, build, registry, manager)
+	reloader.SetCatalog(NewMissionCatalog(&failingMissionStore{}, manager, registry))
+	ctx := context.Background()
+
+	if _, err := reloader.Reload(ctx); err != nil {
+		t.Fatalf("Initial reload failed: %v", err)
+	}
+	scout, _ := registry.Get("Scout")
+
+	writeConfig(t, path, reloadConfigV1)
+	if _, err := reloader.Reload(ctx); err == nil {
+		t.Fatalf("Expected reload to fail when missions cannot be stored")
+	}
+	if agents := registry.List(); len(agents) != 1 || agents[0] != scout {
+		t.Errorf("Expected the previous agents to be restored, got %d agents", len(agents))
+	}
+
+	// The next reload retries the whole change
+	if _, err := reloader.Reload(ctx); err == nil {
+		t.Fatalf("Expected the retried reload to fail again")
+	}
+}
+
+func TestConfigReloader_AppliesChangesMadeWhileDown(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agents.yaml")
+	writeConfig(t, path, `
+agents:
+  - { id: "Scout", type: "trend-scout" }
+  - { id: "Pager", type: "console-reporter" }
+missions:
+  - { id: "edited", trigger_topic: "sensor/a", agents: ["Scout"] }
+  - { id: "deleted", trigger_topic: "sensor/b", agents: ["Scout"] }
+  - { id: "api", trigger_topic: "sensor/c", agents: ["Scout"] }
+  - { id: "dropped", trigger_topic: "sensor/d", agents: ["Scout"] }
+`)
+	build := func(cfg domain.AgentConfig) (domain.Agent, error) {
+		return &RecordingAgent{id: cfg.ID}, nil
+	}
+	store := &MemoryMissionStore{}
+	ctx := context.Background()
+	start := func() (*ConfigReloader, *MissionCatalog) {
+		registry := NewAgentRegistry()
+		manager := NewMissionManager(registry, func(string, domain.CloudEvent) {})
+		catalog := NewMissionCatalog(store, manager, registry)
+		reloader := NewConfigReloader(path, build, registry, manager)
+		reloader.SetCatalog(catalog)
+		if _, err := reloader.Reload(ctx); err != nil {
+			t.Fatalf("Reload failed: %v", err)
+		}
+		return reloader, catalog
+	}
+
+	_, catalog := start()
+	// Changes through the API
+	if err := catalog.Delete(ctx, "deleted"); err != nil {
+		t.Fatal(err)
+	}
+	api, _ := catalog.Get(ctx, "api")
+	api.Name = "Renamed through the API"
+	if err := catalog.Update(ctx, *api); err != nil {
+		t.Fatal(err)
+	}
+
+	// While core is down, one mission is edited in the file and another removed
+	writeConfig(t, path, `
+agents:
+  - { id: "Scout", type: "trend-scout" }
+  - { id: "Pager", type: "console-reporter" }
+missions:
+  - { id: "edited", trigger_topic: "sensor/a", agents: ["Scout", "Pager"] }
+  - { id: "deleted", trigger_topic: "sensor/b", agents: ["Scout"] }
+  - { id: "api", trigger_topic: "sensor/c", agents: ["Scout"] }
+`)
+	reloader, catalog := start()
+
+	if m, err := catalog.Get(ctx, "edited"); err != nil || len(m.Agents) != 2 {
+		t.Errorf("Expected the edit made while down to be applied, got %+v (%v)", m, err)
+	}
+	if _, err := catalog.Get(ctx, "dropped"); !errors.Is(err, domain.ErrMissionNotFound) {
+		t.Errorf("Expected the mission removed while down to be deleted, got %v", err)
+	}
+	if _, err := catalog.Get(ctx, "deleted"); !errors.Is(err, domain.ErrMissionNotFound) {
+		t.Errorf("Expected the mission deleted through the API to stay deleted, got %v", err)
+	}
+	if m, _ := catalog.Get(ctx, "api"); m == nil || m.Name != "Renamed through the API" {
+		t.Errorf("Expected the API change to survive the restart, got %+v", m)
+	}
+
+	// Reloading the unchanged file changes nothing
+	result, err := reloader.Reload(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.MissionsAdded)+len(result.MissionsUpdated)+len(result.MissionsRemoved) != 0 {
+		t.Errorf("Unexpected mission changes on an unchanged file: %+v", result)
+	}
+}
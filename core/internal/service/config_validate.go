@@ -0,0 +1,269 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigError is a validation problem in agents.yaml, located by line.
+type ConfigError struct {
+	Line    int    // 1-based line in the file (0 if unknown)
+	Field   string // e.g. agents[2].type
+	Message string
+}
+
+func (e ConfigError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d: %s: %s", e.Line, e.Field, e.Message)
+	}
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ConfigErrors collects every problem found in a config file.
+type ConfigErrors []ConfigError
+
+func (e ConfigErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// ParseConfig strictly decodes and validates agents.yaml. Unknown fields, unknown agent
+// types, invalid safety patterns, duplicate IDs and missions referencing missing agents
+// are all reported (as ConfigErrors) instead of surfacing at runtime.
+func ParseConfig(data []byte) (*domain.SystemConfig, error) {
+	var sysCfg domain.SystemConfig
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&sysCfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+	var root *yaml.Node
+	if len(doc.Content) > 0 {
+		root = doc.Content[0]
+	}
+
+	v := &configValidator{}
+	v.validate(&sysCfg, root)
+	if len(v.errs) > 0 {
+		return nil, v.errs
+	}
+	return &sysCfg, nil
+}
+
+type configValidator struct {
+	errs ConfigErrors
+}
+
+func (v *configValidator) add(node *yaml.Node, field, format string, args ...interface{}) {
+	line := 0
+	if node != nil {
+		line = node.Line
+	}
+	v.errs = append(v.errs, ConfigError{Line: line, Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *configValidator) validate(cfg *domain.SystemConfig, root *yaml.Node) {
+	agentNodes := seqItems(mapValue(root, "agents"))
+	missionNodes := seqItems(mapValue(root, "missions"))
+
+	// Agents
+	agentLines := make(map[string]int)
+	for i, a := range cfg.Agents {
+		node := itemAt(agentNodes, i)
+		field := fmt.Sprintf("agents[%d]", i)
+
+		idNode := valueOr(node, "id")
+		switch {
+		case a.ID == "":
+			v.add(idNode, field+".id", "is required")
+		case seen(agentLines, a.ID):
+			v.add(idNode, field+".id", "duplicate agent id %q (first defined on line %d)", a.ID, agentLines[a.ID])
+		default:
+			agentLines[a.ID] = lineOf(idNode)
+		}
+
+		typeNode := valueOr(node, "type")
+		if a.Type == "" {
+			v.add(typeNode, field+".type", "is required")
+		} else if spec, ok := domain.LookupAgentType(a.Type); !ok {
+			v.add(typeNode, field+".type", "unknown agent type %q (known: %s)", a.Type, strings.Join(domain.AgentTypeNames(), ", "))
+		} else if _, err := spec.DecodeConfig(a.Config); err != nil {
+			v.typedConfig(node, field+".config", a.Type, err)
+		}
+
+		safety := mapValue(node, "safety")
+		v.patterns(seqItems(mapValue(safety, "allowed_patterns")), a.Security.AllowedPatterns, field+".safety.allowed_patterns", valueOr(safety, "allowed_patterns"))
+		v.patterns(seqItems(mapValue(safety, "denied_patterns")), a.Security.DeniedPatterns, field+".safety.denied_patterns", valueOr(safety, "denied_patterns"))
+	}
+
+	known := make(map[string]bool, len(agentLines))
+	for id := range agentLines {
+		known[id] = true
+	}
+
+	// Missions
+	missionLines := make(map[string]int)
+	for i, m := range cfg.Missions {
+		node := itemAt(missionNodes, i)
+		field := fmt.Sprintf("missions[%d]", i)
+		before := len(v.errs)
+
+		idNode := valueOr(node, "id")
+		switch {
+		case m.ID == "":
+			v.add(idNode, field+".id", "is required")
+		case seen(missionLines, m.ID):
+			v.add(idNode, field+".id", "duplicate mission id %q (first defined on line %d)", m.ID, missionLines[m.ID])
+		default:
+			missionLines[m.ID] = lineOf(idNode)
+		}
+
+		agentItems := seqItems(mapValue(node, "agents"))
+		for j, id := range m.Agents {
+			if !known[id] {
+				v.add(itemAt(agentItems, j), fmt.Sprintf("%s.agents[%d]", field, j), "unknown agent %q", id)
+			}
+		}
+		stepItems := seqItems(mapValue(node, "steps"))
+		for j, s := range m.Steps {
+			if s.Agent != "" && !known[s.Agent] {
+				v.add(valueOr(itemAt(stepItems, j), "agent"), fmt.Sprintf("%s.steps[%d].agent", field, j), "unknown agent %q", s.Agent)
+			}
+		}
+
+		// Graph, conditions and policies (only once the references above are sound)
+		if len(v.errs) == before {
+			if _, err := BuildMissions(&domain.SystemConfig{Missions: []domain.MissionConfig{m}}, known); err != nil {
+				v.add(node, field, "%v", err)
+			}
+		}
+	}
+}
+
+// patterns checks safety glob patterns. They are matched against file names, so
+// patterns with a path separator never match.
+func (v *configValidator) patterns(items []*yaml.Node, patterns []string, field string, parent *yaml.Node) {
+	for i, p := range patterns {
+		node := itemAt(items, i)
+		if node == nil {
+			node = parent
+		}
+		f := fmt.Sprintf("%s[%d]", field, i)
+		switch {
+		case strings.TrimSpace(p) == "":
+			v.add(node, f, "empty pattern")
+		case strings.ContainsAny(p, `/\`):
+			v.add(node, f, "pattern %q contains a path separator; patterns match file names only", p)
+		default:
+			if _, err := filepath.Match(p, ""); err != nil {
+				v.add(node, f, "invalid pattern %q: %v", p, err)
+			}
+		}
+	}
+}
+
+// typedConfig reports a per-type config error. Decoding errors name the offending field,
+// which is used to point at its line; the re-encoded YAML's own line numbers are dropped.
+func (v *configValidator) typedConfig(agent *yaml.Node, field, agentType string, err error) {
+	at := agent
+	if key := mapKey(agent, "config"); key != nil {
+		at = key
+	}
+
+	var typeErr *yaml.TypeError
+	if !errors.As(err, &typeErr) {
+		v.add(at, field, "invalid %s config: %v", agentType, err)
+		return
+	}
+	for _, msg := range typeErr.Errors {
+		msg = yamlLinePrefix.ReplaceAllString(msg, "")
+		fieldAt := at
+		if m := yamlUnknownField.FindStringSubmatch(msg); m != nil {
+			if key := mapKey(mapValue(agent, "config"), m[1]); key != nil {
+				fieldAt = key
+			}
+		}
+		v.add(fieldAt, field, "invalid %s config: %s", agentType, msg)
+	}
+}
+
+var (
+	yamlLinePrefix   = regexp.MustCompile(`^line \d+: `)
+	yamlUnknownField = regexp.MustCompile(`^field (\S+) not found`)
+)
+
+// YAML node helpers. All of them tolerate nil nodes so validation can continue on malformed input.
+
+func mapKey(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i]
+		}
+	}
+	return nil
+}
+
+func mapValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// valueOr returns the value node for key, or the node itself if the key is missing.
+func valueOr(node *yaml.Node, key string) *yaml.Node {
+	if v := mapValue(node, key); v != nil {
+		return v
+	}
+	return node
+}
+
+func seqItems(node *yaml.Node) []*yaml.Node {
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return node.Content
+}
+
+func itemAt(items []*yaml.Node, i int) *yaml.Node {
+	if i < len(items) {
+		return items[i]
+	}
+	return nil
+}
+
+func lineOf(node *yaml.Node) int {
+	if node == nil {
+		return 0
+	}
+	return node.Line
+}
+
+func seen(lines map[string]int, id string) bool {
+	_, ok := lines[id]
+	return ok
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseConfig_ReportsLineNumbers(t *testing.T) {
+	data := `agents:
+  - id: "Scout"
+    type: "trend-scout"
+  - id: "Scout"
+    type: "trend-scot"
+    safety:
+      denied_patterns: ["[", "secrets/.env"]
+missions:
+  - id: "m1"
+    trigger_topic: "sensor/cpu"
+    agents: ["Scout", "Ghost"]
+  - id: "m2"
+    trigger_topic: "sensor/mem"
+    steps:
+      - id: "a"
+        agent: "Scout"
+        needs: ["b"]
+      - id: "b"
+        agent: "Scout"
+        needs: ["a"]
+`
+	_, err := ParseConfig([]byte(data))
+	var cfgErrs ConfigErrors
+	if !errors.As(err, &cfgErrs) {
+		t.Fatalf("Expected ConfigErrors, got %v", err)
+	}
+
+	want := []struct {
+		line  int
+		field string
+		msg   string
+	}{
+		{4, "agents[1].id", "duplicate agent id"},
+		{5, "agents[1].type", "unknown agent type"},
+		{7, "agents[1].safety.denied_patterns[0]", "invalid pattern"},
+		{7, "agents[1].safety.denied_patterns[1]", "path separator"},
+		{11, "missions[0].agents[1]", `unknown agent "Ghost"`},
+		{12, "missions[1]", "cycle"},
+	}
+	if len(cfgErrs) != len(want) {
+		t.Fatalf("Expected %d errors, got %d:\n%v", len(want), len(cfgErrs), cfgErrs)
+	}
+	for i, w := range want {
+		got := cfgErrs[i]
+		if got.Line != w.line || got.Field != w.field || !strings.Contains(got.Message, w.msg) {
+			t.Errorf("Error %d: expected line %d %s (%s), got %v", i, w.line, w.field, w.msg, got)
+		}
+	}
+}
+
+func TestParseConfig_RejectsUnknownFields(t *testing.T) {
+	_, err := ParseConfig([]byte("agents:\n  - id: \"Scout\"\n    typ: \"trend-scout\"\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Fatalf("Expected unknown field error on line 3, got %v", err)
+	}
+}
+
+func TestParseConfig_Valid(t *testing.T) {
+	cfg, err := ParseConfig([]byte(reloadConfigV1))
+	if err != nil {
+		t.Fatalf("Expected valid config, got %v", err)
+	}
+	if len(cfg.Agents) != 2 || len(cfg.Missions) != 1 {
+		t.Errorf("Unexpected config: %+v", cfg)
+	}
+}
+
+func TestParseConfig_TypedAgentConfig(t *testing.T) {
+	data := `agents:
+  - id: "Scout"
+    type: "trend-scout"
+    config:
+      threshold: -1
+  - id: "Log"
+    type: "console-reporter"
+    config:
+      verbose: true
+`
+	_, err := ParseConfig([]byte(data))
+	var cfgErrs ConfigErrors
+	if !errors.As(err, &cfgErrs) || len(cfgErrs) != 2 {
+		t.Fatalf("Expected 2 config errors, got %v", err)
+	}
+	if cfgErrs[0].Line != 4 || !strings.Contains(cfgErrs[0].Message, "threshold must be positive") {
+		t.Errorf("Unexpected error: %v", cfgErrs[0])
+	}
+	if cfgErrs[1].Line != 9 || !strings.Contains(cfgErrs[1].Message, "verbose") {
+		t.Errorf("Unexpected error: %v", cfgErrs[1])
+	}
+}
@@ -4,73 +4,63 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/datacraft/catalyst/core/internal/adapter/agent"
-	"github.com/datacraft/catalyst/core/internal/adapter/workspace"
+	_ "github.com/datacraft/catalyst/core/internal/adapter/agent" // Built-in agent types
 	"github.com/datacraft/catalyst/core/internal/domain"
-	"github.com/point-unknown/catalyst/pkg/mcp"
-	"github.com/point-unknown/catalyst/pkg/vector"
-	"gopkg.in/yaml.v3"
 )
 
-// AgentFactory creates an agent instance from configuration.
-func AgentFactory(cfg domain.AgentConfig, llm domain.LLMProvider, registry mcp.Registry, vecStore vector.Store, resolver domain.ContextResolver) (domain.Agent, error) {
-	switch cfg.Type {
-	case "trend-scout":
-		threshold := 80.0
-		if v, ok := cfg.Config["threshold"].(float64); ok {
-			threshold = v
-		}
-		return agent.NewTrendScout(cfg.ID, threshold), nil
-
-	case "console-reporter":
-		return agent.NewConsoleReporter(cfg.ID), nil
-
-	case "engineer":
-		// Create Secure Workspace with Resolver (GitGuard)
-		ws := workspace.NewLocalWorkspace(cfg.Security, resolver)
-		return agent.NewEngineerAgent(cfg.ID, llm, ws, cfg.Security), nil
-
-	case "liaison":
-		// Inject Vector Store into Liaison
-		return agent.NewLiaisonAgent(cfg.ID, llm, registry, vecStore), nil
-
-	default:
+// AgentFactory creates an agent instance from configuration using the registered agent types
+// (see domain.RegisterAgentType). The agent's config block is decoded into the type's config struct.
+func AgentFactory(cfg domain.AgentConfig, deps domain.AgentDeps) (domain.Agent, error) {
+	spec, ok := domain.LookupAgentType(cfg.Type)
+	if !ok {
 		return nil, fmt.Errorf("unknown agent type: %s", cfg.Type)
 	}
-}
 
-// LoadAgents reads the YAML config and instantiates agents.
-func LoadAgents(path string, llm domain.LLMProvider, registry mcp.Registry, vecStore vector.Store, resolver domain.ContextResolver) ([]domain.Agent, []domain.Mission, error) {
-	data, err := os.ReadFile(path)
+	typed, err := spec.DecodeConfig(cfg.Config)
 	if err != nil {
-		return nil, nil, err
-	}
-
-	var sysCfg domain.SystemConfig
-	if err := yaml.Unmarshal(data, &sysCfg); err != nil {
-		return nil, nil, err
+		return nil, fmt.Errorf("invalid config for %s agent: %w", cfg.Type, err)
 	}
+	return spec.Build(cfg, typed, deps)
+}
 
-	var agents []domain.Agent
-	for _, cfg := range sysCfg.Agents {
-		// Use Factory
-		a, err := AgentFactory(cfg, llm, registry, vecStore, resolver)
-		if err != nil {
-			// fallback or skip
-			continue
-		}
-		agents = append(agents, a)
+// ReadConfig reads and strictly validates the YAML config (see ParseConfig).
+func ReadConfig(path string) (*domain.SystemConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
 	}
+	return ParseConfig(data)
+}
 
+// BuildMissions converts the configured missions and validates them against the known agent IDs.
+func BuildMissions(sysCfg *domain.SystemConfig, known map[string]bool) ([]domain.Mission, error) {
 	var missions []domain.Mission
 	for _, mCfg := range sysCfg.Missions {
-		missions = append(missions, domain.Mission{
+		mission := domain.Mission{
 			ID:           mCfg.ID,
 			Name:         mCfg.Name,
 			TriggerTopic: mCfg.TriggerTopic,
+			TriggerType:  mCfg.TriggerType,
 			Agents:       mCfg.Agents,
-		})
-	}
+			Defaults:     mCfg.Defaults,
+			Concurrency:  mCfg.Concurrency,
+			QueueDepth:   mCfg.QueueDepth,
+		}
+		for _, sCfg := range mCfg.Steps {
+			mission.Steps = append(mission.Steps, domain.MissionStep{
+				ID:         sCfg.ID,
+				Agent:      sCfg.Agent,
+				Needs:      sCfg.Needs,
+				When:       sCfg.When,
+				StepPolicy: sCfg.StepPolicy,
+			})
+		}
 
-	return agents, missions, nil
+		// Reject unknown agents and cyclic graphs at startup rather than at trigger time
+		if err := ValidateMission(mission, known); err != nil {
+			return nil, err
+		}
+		missions = append(missions, mission)
+	}
+	return missions, nil
 }
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+)
+
+// ErrInvalidMission wraps validation failures for missions submitted to the catalog.
+var ErrInvalidMission = errors.New("invalid mission")
+
+// DefaultMissionSyncInterval is how often the catalog re-reads the missions table.
+const DefaultMissionSyncInterval = 10 * time.Second
+
+// MissionCatalog keeps the MissionManager in sync with the missions table.
+// agents.yaml only seeds the table; after that the database is authoritative, so
+// enabling or disabling a mission takes effect without a restart.
+type MissionCatalog struct {
+	store    domain.MissionStore
+	manager  *MissionManager
+	registry *AgentRegistry
+
+	mu sync.Mutex // Serializes refreshes
+}
+
+func NewMissionCatalog(store domain.MissionStore, manager *MissionManager, registry *AgentRegistry) *MissionCatalog {
+	return &MissionCatalog{
+		store:    store,
+		manager:  manager,
+		registry: registry,
+	}
+}
+
+// Seed inserts missions that are not in the store yet.
+func (c *MissionCatalog) Seed(ctx context.Context, missions []domain.Mission) error {
+	return c.store.SeedMissions(ctx, missions)
+}
+
+// Refresh loads all missions from the store and applies them to the manager.
+// Missions that reference unknown agents or have an invalid graph are skipped.
+func (c *MissionCatalog) Refresh(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stored, err := c.store.ListMissions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list missions: %w", err)
+	}
+
+	known := c.knownAgents()
+	valid := make([]domain.Mission, 0, len(stored))
+	for _, m := range stored {
+		if err := ValidateMission(m, known); err != nil {
+			log.Printf("[MISSION] Skipping stored mission %s: %v", m.ID, err)
+			continue
+		}
+		valid = append(valid, m)
+	}
+	return c.manager.SyncMissions(valid)
+}
+
+// List returns every stored mission, including disabled ones.
+func (c *MissionCatalog) List(ctx context.Context) ([]domain.Mission, error) {
+	return c.store.ListMissions(ctx)
+}
+
+// Get returns a stored mission.
+func (c *MissionCatalog) Get(ctx context.Context, id string) (*domain.Mission, error) {
+	return c.store.GetMission(ctx, id)
+}
+
+// apply refreshes the manager after a change has been stored. A failed refresh is only
+// logged: the change is saved, and the periodic refresh (Run) will apply it.
+func (c *MissionCatalog) apply(ctx context.Context) {
+	if err := c.Refresh(ctx); err != nil {
+		log.Printf("[MISSION] Catalog refresh after change failed: %v", err)
+	}
+}
+
+// Create validates and stores a new mission, then loads it.
+func (c *MissionCatalog) Create(ctx context.Context, mission domain.Mission) error {
+	if err := c.validate(mission); err != nil {
+		return err
+	}
+	if err := c.store.CreateMission(ctx, mission); err != nil {
+		return err
+	}
+	c.apply(ctx)
+	return nil
+}
+
+// Update validates and replaces a stored mission, then reloads it. The stored enabled
+// state is kept: missions are only enabled or disabled through SetEnabled.
+func (c *MissionCatalog) Update(ctx context.Context, mission domain.Mission) error {
+	if err := c.validate(mission); err != nil {
+		return err
+	}
+	existing, err := c.store.GetMission(ctx, mission.ID)
+	if err != nil {
+		return err
+	}
+	mission.Disabled = existing.Disabled
+	if err := c.store.UpdateMission(ctx, mission); err != nil {
+		return err
+	}
+	c.apply(ctx)
+	return nil
+}
+
+// Delete removes a mission from the store and unloads it.
+func (c *MissionCatalog) Delete(ctx context.Context, id string) error {
+	if err := c.store.DeleteMission(ctx, id); err != nil {
+		return err
+	}
+	c.apply(ctx)
+	return nil
+}
+
+// Configured returns the agents.yaml missions as last applied to the store.
+func (c *MissionCatalog) Configured(ctx context.Context) ([]domain.Mission, error) {
+	return c.store.ConfiguredMissions(ctx)
+}
+
+// ApplyConfig propagates a change of the configured (agents.yaml) missions, relative to
+// Configured, to the store: new missions are seeded, changed ones are overwritten (keeping
+// their enabled state) and removed ones are deleted. Missions the change does not touch,
+// including those created or edited through the API, are left alone.
+func (c *MissionCatalog) ApplyConfig(ctx context.Context, added, changed []domain.Mission, removed []string) error {
+	if err := c.store.SeedMissions(ctx, added); err != nil {
+		return err
+	}
+	for _, configured := range changed {
+		m := configured
+		existing, err := c.store.GetMission(ctx, m.ID)
+		if errors.Is(err, domain.ErrMissionNotFound) {
+			err = c.store.CreateMission(ctx, m)
+		} else if err == nil {
+			m.Disabled = existing.Disabled
+			err = c.store.UpdateMission(ctx, m)
+		}
+		if err == nil {
+			err = c.store.SaveConfiguredMission(ctx, configured)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for _, id := range removed {
+		if err := c.store.DeleteMission(ctx, id); err != nil && !errors.Is(err, domain.ErrMissionNotFound) {
+			return err
+		}
+		if err := c.store.ForgetConfiguredMission(ctx, id); err != nil {
+			return err
+		}
+	}
+	c.apply(ctx)
+	return nil
+}
+
+// SetEnabled toggles a mission in the store and applies the change immediately.
+func (c *MissionCatalog) SetEnabled(ctx context.Context, id string, enabled bool) error {
+	if err := c.store.SetMissionEnabled(ctx, id, enabled); err != nil {
+		return err
+	}
+	c.apply(ctx)
+	return nil
+}
+
+// Run refreshes the catalog every interval until ctx is cancelled, picking up
+// changes made directly in the database (or by other replicas).
+func (c *MissionCatalog) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultMissionSyncInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.Refresh(ctx); err != nil {
+				log.Printf("[MISSION] Catalog refresh failed: %v", err)
+			}
+		}
+	}
+}
+
+func (c *MissionCatalog) validate(mission domain.Mission) error {
+	if mission.ID == "" {
+		return fmt.Errorf("%w: id is required", ErrInvalidMission)
+	}
+	if err := ValidateMission(mission, c.knownAgents()); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidMission, err)
+	}
+	return nil
+}
+
+func (c *MissionCatalog) knownAgents() map[string]bool {
+	known := make(map[string]bool)
+	for _, a := range c.registry.List() {
+		known[a.ID()] = true
+	}
+	return known
+}
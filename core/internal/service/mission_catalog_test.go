@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+)
+
+// MemoryMissionStore implements domain.MissionStore for testing
+type MemoryMissionStore struct {
+	mu       sync.Mutex
+	missions []domain.Mission
+	seeded   map[string]domain.Mission // Configured missions
+}
+
+func (s *MemoryMissionStore) SeedMissions(ctx context.Context, missions []domain.Mission) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.seeded == nil {
+		s.seeded = make(map[string]domain.Mission)
+	}
+next:
+	for _, m := range missions {
+		if _, ok := s.seeded[m.ID]; ok {
+			continue
+		}
+		s.seeded[m.ID] = m
+		for _, existing := range s.missions {
+			if existing.ID == m.ID {
+				continue next
+			}
+		}
+		s.missions = append(s.missions, m)
+	}
+	return nil
+}
+
+func (s *MemoryMissionStore) ConfiguredMissions(ctx context.Context) ([]domain.Mission, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	missions := make([]domain.Mission, 0, len(s.seeded))
+	for _, m := range s.seeded {
+		missions = append(missions, m)
+	}
+	return missions, nil
+}
+
+func (s *MemoryMissionStore) SaveConfiguredMission(ctx context.Context, mission domain.Mission) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.seeded == nil {
+		s.seeded = make(map[string]domain.Mission)
+	}
+	s.seeded[mission.ID] = mission
+	return nil
+}
+
+func (s *MemoryMissionStore) ForgetConfiguredMission(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.seeded, id)
+	return nil
+}
+
+func (s *MemoryMissionStore) ListMissions(ctx context.Context) ([]domain.Mission, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]domain.Mission(nil), s.missions...), nil
+}
+
+func (s *MemoryMissionStore) GetMission(ctx context.Context, id string) (*domain.Mission, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, m := range s.missions {
+		if m.ID == id {
+			return &m, nil
+		}
+	}
+	return nil, domain.ErrMissionNotFound
+}
+
+func (s *MemoryMissionStore) CreateMission(ctx context.Context, mission domain.Mission) error {
+	if _, err := s.GetMission(ctx, mission.ID); err == nil {
+		return domain.ErrMissionExists
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.missions = append(s.missions, mission)
+	return nil
+}
+
+func (s *MemoryMissionStore) UpdateMission(ctx context.Context, mission domain.Mission) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.missions {
+		if s.missions[i].ID == mission.ID {
+			s.missions[i] = mission
+			return nil
+		}
+	}
+	return domain.ErrMissionNotFound
+}
+
+func (s *MemoryMissionStore) DeleteMission(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.missions {
+		if s.missions[i].ID == id {
+			s.missions = append(s.missions[:i], s.missions[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ErrMissionNotFound
+}
+
+func (s *MemoryMissionStore) SetMissionEnabled(ctx context.Context, id string, enabled bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.missions {
+		if s.missions[i].ID == id {
+			s.missions[i].Disabled = !enabled
+			return nil
+		}
+	}
+	return domain.ErrMissionNotFound
+}
+
+func TestMissionCatalog_SeedAndToggle(t *testing.T) {
+	registry := NewAgentRegistry()
+	agent := &RecordingAgent{id: "Worker"}
+	registry.Register(agent)
+	manager := NewMissionManager(registry, func(string, domain.CloudEvent) {})
+
+	store := &MemoryMissionStore{}
+	// A row edited in the database wins over the seed
+	store.missions = []domain.Mission{{ID: "m1", Name: "Edited", TriggerTopic: "sensor/a", Agents: []string{"Worker"}}}
+
+	catalog := NewMissionCatalog(store, manager, registry)
+	ctx := context.Background()
+	err := catalog.Seed(ctx, []domain.Mission{
+		{ID: "m1", Name: "Seed", TriggerTopic: "sensor/a", Agents: []string{"Worker"}},
+		{ID: "m2", Name: "Second", TriggerTopic: "sensor/b", Agents: []string{"Worker"}},
+		{ID: "m3", Name: "Broken", TriggerTopic: "sensor/c", Agents: []string{"Ghost"}},
+	})
+	if err != nil {
+		t.Fatalf("Seed failed: %v", err)
+	}
+	if err := catalog.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	active := manager.Missions()
+	if len(active) != 2 {
+		t.Fatalf("Expected 2 active missions (unknown agent skipped), got %d", len(active))
+	}
+	for _, m := range active {
+		if m.ID == "m1" && m.Name != "Edited" {
+			t.Errorf("Seed overwrote stored mission: %q", m.Name)
+		}
+	}
+
+	// Disable takes effect without a restart
+	if err := catalog.SetEnabled(ctx, "m2", false); err != nil {
+		t.Fatalf("SetEnabled failed: %v", err)
+	}
+	evt, _ := domain.NewEvent("test", "sensor.b", nil)
+	manager.ProcessEvent("sensor/b", evt)
+	waitIdle(t, manager)
+	if len(agent.Calls()) != 0 {
+		t.Errorf("Disabled mission still ran")
+	}
+
+	// Re-enable
+	if err := catalog.SetEnabled(ctx, "m2", true); err != nil {
+		t.Fatalf("SetEnabled failed: %v", err)
+	}
+	manager.ProcessEvent("sensor/b", evt)
+	waitIdle(t, manager)
+	if len(agent.Calls()) != 1 {
+		t.Errorf("Expected re-enabled mission to run once, got %d", len(agent.Calls()))
+	}
+
+	if err := catalog.SetEnabled(ctx, "missing", true); err != domain.ErrMissionNotFound {
+		t.Errorf("Expected ErrMissionNotFound, got %v", err)
+	}
+
+	// A deleted mission is not seeded again (e.g. by the next start)
+	if err := catalog.Delete(ctx, "m2"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := catalog.Seed(ctx, []domain.Mission{{ID: "m2", Name: "Second", TriggerTopic: "sensor/b", Agents: []string{"Worker"}}}); err != nil {
+		t.Fatalf("Seed failed: %v", err)
+	}
+	if _, err := catalog.Get(ctx, "m2"); !errors.Is(err, domain.ErrMissionNotFound) {
+		t.Errorf("Expected the deleted mission to stay deleted, got %v", err)
+	}
+}
+
+func TestMissionCatalog_CRUDAndTrigger(t *testing.T) {
+	registry := NewAgentRegistry()
+	agent := &RecordingAgent{id: "Worker"}
+	registry.Register(agent)
+	manager := NewMissionManager(registry, func(string, domain.CloudEvent) {})
+	catalog := NewMissionCatalog(&MemoryMissionStore{}, manager, registry)
+	ctx := context.Background()
+
+	mission := domain.Mission{ID: "m1", Name: "One", TriggerTopic: "sensor/a", Agents: []string{"Worker"}}
+	if err := catalog.Create(ctx, mission); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if err := catalog.Create(ctx, mission); !errors.Is(err, domain.ErrMissionExists) {
+		t.Errorf("Expected ErrMissionExists, got %v", err)
+	}
+	bad := domain.Mission{ID: "m2", TriggerTopic: "sensor/b", Agents: []string{"Ghost"}}
+	if err := catalog.Create(ctx, bad); !errors.Is(err, ErrInvalidMission) {
+		t.Errorf("Expected ErrInvalidMission, got %v", err)
+	}
+
+	// Manual trigger bypasses the topic filter
+	evt, _ := domain.NewEvent("test", "manual.test", nil)
+	if err := manager.Trigger("m1", evt); err != nil {
+		t.Fatalf("Trigger failed: %v", err)
+	}
+	waitIdle(t, manager)
+	if len(agent.Calls()) != 1 {
+		t.Errorf("Expected 1 run, got %d", len(agent.Calls()))
+	}
+
+	mission.TriggerTopic = "sensor/changed"
+	if err := catalog.Update(ctx, mission); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if active := manager.Missions(); len(active) != 1 || active[0].TriggerTopic != "sensor/changed" {
+		t.Errorf("Update not applied: %+v", active)
+	}
+
+	if err := catalog.Delete(ctx, "m1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := manager.Trigger("m1", evt); !errors.Is(err, domain.ErrMissionNotFound) {
+		t.Errorf("Expected ErrMissionNotFound after delete, got %v", err)
+	}
+}
+
+func TestMissionManager_SyncMissionsKeepsUnchanged(t *testing.T) {
+	registry := NewAgentRegistry()
+	registry.Register(&RecordingAgent{id: "Worker"})
+	manager := NewMissionManager(registry, func(string, domain.CloudEvent) {})
+
+	missions := []domain.Mission{
+		{ID: "m1", Name: "One", TriggerTopic: "sensor/a", Agents: []string{"Worker"}},
+		{ID: "m2", Name: "Two", TriggerTopic: "sensor/b", Agents: []string{"Worker"}},
+	}
+	if err := manager.SyncMissions(missions); err != nil {
+		t.Fatalf("SyncMissions failed: %v", err)
+	}
+	before := manager.queues[0]
+
+	if err := manager.SyncMissions(missions[:1]); err != nil {
+		t.Fatalf("SyncMissions failed: %v", err)
+	}
+	if len(manager.queues) != 1 || manager.queues[0] != before {
+		t.Errorf("Expected m1 to keep its queue and m2 to be unloaded")
+	}
+}
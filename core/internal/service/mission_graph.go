@@ -0,0 +1,146 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+)
+
+// MissionSteps returns the execution graph of a mission.
+// Linear missions (Agents) are expanded into a chain where each step needs the previous one.
+func MissionSteps(mission domain.Mission) []domain.MissionStep {
+	if len(mission.Steps) > 0 {
+		steps := make([]domain.MissionStep, len(mission.Steps))
+		for i, s := range mission.Steps {
+			if s.ID == "" {
+				s.ID = s.Agent
+			}
+			steps[i] = s
+		}
+		return steps
+	}
+
+	steps := make([]domain.MissionStep, 0, len(mission.Agents))
+	seen := make(map[string]int)
+	prev := ""
+	for _, agentID := range mission.Agents {
+		// The same agent may appear twice in a pipeline; keep step IDs unique.
+		id := agentID
+		if n := seen[agentID]; n > 0 {
+			id = fmt.Sprintf("%s#%d", agentID, n+1)
+		}
+		seen[agentID]++
+
+		step := domain.MissionStep{ID: id, Agent: agentID}
+		if prev != "" {
+			step.Needs = []string{prev}
+		}
+		steps = append(steps, step)
+		prev = id
+	}
+	return steps
+}
+
+// ValidateMission checks the mission graph for duplicate or dangling step references,
+// malformed "when" conditions, invalid step policies and cycles.
+// If knownAgents is non-nil, every step must also reference one of those agent IDs.
+func ValidateMission(mission domain.Mission, knownAgents map[string]bool) error {
+	if len(mission.Agents) > 0 && len(mission.Steps) > 0 {
+		return fmt.Errorf("mission %s: use either 'agents' or 'steps', not both", mission.ID)
+	}
+
+	if mission.Concurrency < 0 || mission.QueueDepth < 0 {
+		return fmt.Errorf("mission %s: concurrency and queue_depth must not be negative", mission.ID)
+	}
+	if err := mission.Defaults.Validate(); err != nil {
+		return fmt.Errorf("mission %s: defaults: %w", mission.ID, err)
+	}
+
+	steps := MissionSteps(mission)
+	if len(steps) == 0 {
+		return fmt.Errorf("mission %s: no agents or steps defined", mission.ID)
+	}
+
+	byID := make(map[string]domain.MissionStep, len(steps))
+	for _, s := range steps {
+		if s.Agent == "" {
+			return fmt.Errorf("mission %s: step %q has no agent", mission.ID, s.ID)
+		}
+		if _, dup := byID[s.ID]; dup {
+			return fmt.Errorf("mission %s: duplicate step id %q", mission.ID, s.ID)
+		}
+		if _, err := ParseCondition(s.When); err != nil {
+			return fmt.Errorf("mission %s: step %q: %w", mission.ID, s.ID, err)
+		}
+		if err := s.StepPolicy.Validate(); err != nil {
+			return fmt.Errorf("mission %s: step %q: %w", mission.ID, s.ID, err)
+		}
+		if knownAgents != nil && !knownAgents[s.Agent] {
+			return fmt.Errorf("mission %s: step %q references unknown agent %q", mission.ID, s.ID, s.Agent)
+		}
+		fallback, ok := s.StepPolicy.Merge(mission.Defaults).FallbackAgent()
+		if ok && knownAgents != nil && !knownAgents[fallback] {
+			return fmt.Errorf("mission %s: step %q falls back to unknown agent %q", mission.ID, s.ID, fallback)
+		}
+		byID[s.ID] = s
+	}
+
+	for _, s := range steps {
+		for _, need := range s.Needs {
+			if _, ok := byID[need]; !ok {
+				return fmt.Errorf("mission %s: step %q needs unknown step %q", mission.ID, s.ID, need)
+			}
+		}
+	}
+
+	if cycle := findCycle(steps, byID); cycle != nil {
+		return fmt.Errorf("mission %s: cycle detected: %s", mission.ID, strings.Join(cycle, " -> "))
+	}
+	return nil
+}
+
+// findCycle runs a DFS over the "needs" edges and returns the first cycle found (or nil).
+func findCycle(steps []domain.MissionStep, byID map[string]domain.MissionStep) []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(steps))
+	var path []string
+
+	var visit func(id string) []string
+	visit = func(id string) []string {
+		switch state[id] {
+		case visiting:
+			// Slice the path from the first occurrence of id to close the loop
+			for i, p := range path {
+				if p == id {
+					return append(append([]string{}, path[i:]...), id)
+				}
+			}
+			return []string{id, id}
+		case visited:
+			return nil
+		}
+
+		state[id] = visiting
+		path = append(path, id)
+		for _, need := range byID[id].Needs {
+			if cycle := visit(need); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = visited
+		return nil
+	}
+
+	for _, s := range steps {
+		if cycle := visit(s.ID); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+)
+
+func TestMissionSteps_LinearExpansion(t *testing.T) {
+	steps := MissionSteps(domain.Mission{ID: "m", Agents: []string{"A", "B", "A"}})
+
+	if len(steps) != 3 {
+		t.Fatalf("Expected 3 steps, got %d", len(steps))
+	}
+	if steps[2].ID != "A#2" || steps[2].Agent != "A" {
+		t.Errorf("Expected repeated agent to get a unique step id, got %+v", steps[2])
+	}
+	if len(steps[1].Needs) != 1 || steps[1].Needs[0] != "A" {
+		t.Errorf("Expected B to need A, got %v", steps[1].Needs)
+	}
+	if len(steps[2].Needs) != 1 || steps[2].Needs[0] != "B" {
+		t.Errorf("Expected A#2 to need B, got %v", steps[2].Needs)
+	}
+}
+
+func TestValidateMission(t *testing.T) {
+	known := map[string]bool{"A": true, "B": true, "C": true}
+
+	cases := []struct {
+		name    string
+		mission domain.Mission
+		wantErr string
+	}{
+		{
+			name:    "linear ok",
+			mission: domain.Mission{ID: "m", Agents: []string{"A", "B"}},
+		},
+		{
+			name: "diamond ok",
+			mission: domain.Mission{ID: "m", Steps: []domain.MissionStep{
+				{ID: "root", Agent: "A"},
+				{ID: "left", Agent: "B", Needs: []string{"root"}},
+				{ID: "right", Agent: "C", Needs: []string{"root"}},
+				{ID: "join", Agent: "A", Needs: []string{"left", "right"}},
+			}},
+		},
+		{
+			name:    "empty",
+			mission: domain.Mission{ID: "m"},
+			wantErr: "no agents or steps",
+		},
+		{
+			name:    "unknown agent",
+			mission: domain.Mission{ID: "m", Agents: []string{"A", "Ghost"}},
+			wantErr: "unknown agent \"Ghost\"",
+		},
+		{
+			name: "unknown need",
+			mission: domain.Mission{ID: "m", Steps: []domain.MissionStep{
+				{ID: "a", Agent: "A", Needs: []string{"nope"}},
+			}},
+			wantErr: "needs unknown step",
+		},
+		{
+			name: "duplicate step",
+			mission: domain.Mission{ID: "m", Steps: []domain.MissionStep{
+				{Agent: "A"},
+				{Agent: "A"},
+			}},
+			wantErr: "duplicate step id",
+		},
+		{
+			name: "cycle",
+			mission: domain.Mission{ID: "m", Steps: []domain.MissionStep{
+				{ID: "a", Agent: "A", Needs: []string{"c"}},
+				{ID: "b", Agent: "B", Needs: []string{"a"}},
+				{ID: "c", Agent: "C", Needs: []string{"b"}},
+			}},
+			wantErr: "cycle detected",
+		},
+	}
+
+	for _, c := range cases {
+		err := ValidateMission(c.mission, known)
+		if c.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", c.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), c.wantErr) {
+			t.Errorf("%s: expected error containing %q, got %v", c.name, c.wantErr, err)
+		}
+	}
+}
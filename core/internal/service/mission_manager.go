@@ -2,78 +2,525 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"reflect"
+	"sync"
+	"time"
 
 	"github.com/datacraft/catalyst/core/internal/domain"
 )
 
 // MissionManager coordinates the execution of Missions based on triggers.
+// Triggers are queued per mission and executed by a bounded worker pool,
+// so ProcessEvent never blocks the EventBus on agent work.
 type MissionManager struct {
 	registry *AgentRegistry
-	missions []domain.Mission
 	publish  func(topic string, event domain.CloudEvent)
+	runs     domain.MissionRunStore // Optional run history
+	durable  domain.MissionQueue    // Optional durable queue (otherwise in-memory)
+
+	mu      sync.RWMutex
+	queues  []runQueue
+	retired []runQueue // Replaced or unloaded queues still draining; removed once drained
+	closed  bool
 }
 
 func NewMissionManager(registry *AgentRegistry, publisher func(topic string, event domain.CloudEvent)) *MissionManager {
 	return &MissionManager{
 		registry: registry,
-		missions: make([]domain.Mission, 0),
+		queues:   make([]runQueue, 0),
 		publish:  publisher,
 	}
 }
 
-// LoadMission adds a mission configuration to the active set.
-func (m *MissionManager) LoadMission(mission domain.Mission) {
-	m.missions = append(m.missions, mission)
-	log.Printf("[MISSION] Loaded: %s (Trigger: %s)", mission.Name, mission.TriggerTopic)
+// SetRunStore enables persistence of mission run history.
+func (m *MissionManager) SetRunStore(store domain.MissionRunStore) {
+	m.runs = store
+}
+
+// SetQueue makes missions loaded afterwards use a durable queue, so pending and in-flight
+// runs survive restarts and can be shared between replicas.
+func (m *MissionManager) SetQueue(queue domain.MissionQueue) {
+	m.durable = queue
+}
+
+// LoadMission adds a mission configuration to the active set, replacing any mission with the same ID.
+// Missions with an invalid graph (dangling needs, cycles) are rejected; disabled missions are unloaded.
+func (m *MissionManager) LoadMission(mission domain.Mission) error {
+	if err := ValidateMission(mission, nil); err != nil {
+		log.Printf("[MISSION] Rejected: %v", err)
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return fmt.Errorf("mission manager is shut down")
+	}
+
+	// A replaced queue finishes its backlog (with the old configuration) before the new
+	// workers start, so the mission never runs more than its concurrency at once
+	previous := m.unloadLocked(mission.ID)
+	if mission.Disabled {
+		log.Printf("[MISSION] Disabled: %s", mission.Name)
+		return nil
+	}
+
+	var q runQueue = newMemoryQueue(mission)
+	if m.durable != nil {
+		q = newDurableQueue(mission, m.durable)
+	}
+	q.start(m.executeMission, previous)
+	m.queues = append(m.queues, q)
+	log.Printf("[MISSION] Loaded: %s (Trigger: %s %s, Workers: %d)", mission.Name, mission.TriggerTopic, mission.TriggerType, missionConcurrency(mission))
+	return nil
+}
+
+// UnloadMission stops triggering a mission. Already queued runs still complete.
+func (m *MissionManager) UnloadMission(id string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.unloadLocked(id) != nil
+}
+
+// unloadLocked closes the queue of a mission and retires it until it is drained. It returns
+// a channel closed once the queue is drained, or nil if the mission was not loaded.
+func (m *MissionManager) unloadLocked(id string) <-chan struct{} {
+	for i, q := range m.queues {
+		if q.mission().ID == id {
+			q.close()
+			m.retired = append(m.retired, q)
+			m.queues = append(m.queues[:i], m.queues[i+1:]...)
+
+			drained := make(chan struct{})
+			go func() {
+				q.wait()
+				m.mu.Lock()
+				for j, r := range m.retired {
+					if r == q {
+						m.retired = append(m.retired[:j], m.retired[j+1:]...)
+						break
+					}
+				}
+				m.mu.Unlock()
+				close(drained)
+			}()
+			return drained
+		}
+	}
+	return nil
+}
+
+// SyncMissions makes the active set match missions: new or changed missions are (re)loaded,
+// unchanged ones keep their queues, and missions no longer present are unloaded.
+func (m *MissionManager) SyncMissions(missions []domain.Mission) error {
+	desired := make(map[string]bool, len(missions))
+	current := make(map[string]domain.Mission)
+	for _, mission := range m.Missions() {
+		current[mission.ID] = mission
+	}
+
+	var errs []error
+	for _, mission := range missions {
+		desired[mission.ID] = !mission.Disabled
+		if existing, ok := current[mission.ID]; ok && reflect.DeepEqual(existing, mission) {
+			continue
+		}
+		if err := m.LoadMission(mission); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for id := range current {
+		if !desired[id] {
+			if m.UnloadMission(id) {
+				log.Printf("[MISSION] Unloaded: %s", id)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Missions returns the active (enabled) missions.
+func (m *MissionManager) Missions() []domain.Mission {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	list := make([]domain.Mission, 0, len(m.queues))
+	for _, q := range m.queues {
+		list = append(list, q.mission())
+	}
+	return list
 }
 
 // ProcessEvent is the main entrypoint for the EventBus.
-// It checks if the event matches any Mission triggers and executes the flow.
-func (m *MissionManager) ProcessEvent(event domain.CloudEvent) {
-	for _, mission := range m.missions {
-		// Basic Topic Match (Exact match for now, wildcard support later)
-		// Simulating wildcard match for "sensor/#"
-		if mission.TriggerTopic == "#" || mission.TriggerTopic == event.Type || (mission.TriggerTopic == "sensor/#" && event.Source != "") {
-			m.executeMission(mission, event)
+// It checks if the event (received on topic) matches any Mission triggers and enqueues a run.
+// It never blocks: triggers for a mission whose queue is full are dropped (see Stats).
+func (m *MissionManager) ProcessEvent(topic string, event domain.CloudEvent) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return
+	}
+
+	for _, q := range m.queues {
+		if matchesTrigger(q.mission(), topic, event) {
+			q.enqueue(event)
 		}
 	}
 }
 
-func (m *MissionManager) executeMission(mission domain.Mission, trigger domain.CloudEvent) {
-	ctx := context.Background()
+// Trigger enqueues a run of an active mission with the given event, bypassing the
+// trigger filters. It returns domain.ErrMissionNotFound if the mission is not loaded
+// (unknown or disabled) and domain.ErrQueueFull if the trigger was dropped.
+func (m *MissionManager) Trigger(missionID string, event domain.CloudEvent) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return fmt.Errorf("mission manager is shut down")
+	}
+
+	for _, q := range m.queues {
+		if q.mission().ID == missionID {
+			if !q.enqueue(event) {
+				return domain.ErrQueueFull
+			}
+			return nil
+		}
+	}
+	return domain.ErrMissionNotFound
+}
+
+// Stats returns a snapshot of every mission's queue.
+func (m *MissionManager) Stats() []QueueStats {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	stats := make([]QueueStats, 0, len(m.queues))
+	for _, q := range m.queues {
+		stats = append(stats, q.stats())
+	}
+	return stats
+}
+
+// Shutdown stops accepting triggers and waits for queued and in-flight runs to finish,
+// or for ctx to expire (in which case the remaining runs are abandoned).
+// With a durable queue only in-flight runs are awaited; queued runs stay in the store.
+func (m *MissionManager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil
+	}
+	m.closed = true
+	for _, q := range m.queues {
+		q.close()
+	}
+	queues := append(append([]runQueue{}, m.queues...), m.retired...)
+	m.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		for _, q := range queues {
+			q.wait()
+		}
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		log.Printf("[MISSION] All mission queues drained")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("mission drain interrupted: %w", ctx.Err())
+	}
+}
+
+// matchesTrigger checks the mission's topic filter (MQTT wildcards) and type pattern (glob).
+// When both are set, both must match. A mission without any trigger never fires.
+func matchesTrigger(mission domain.Mission, topic string, event domain.CloudEvent) bool {
+	if mission.TriggerTopic == "" && mission.TriggerType == "" {
+		return false
+	}
+	if mission.TriggerTopic != "" && !MatchTopic(mission.TriggerTopic, topic) {
+		return false
+	}
+	if mission.TriggerType != "" && !MatchType(mission.TriggerType, event.Type) {
+		return false
+	}
+	return true
+}
+
+// DefaultStepTimeout bounds a single agent attempt when neither the step nor the mission sets a timeout,
+// so a stuck agent (e.g. a slow LLM call) cannot block the mission forever.
+const DefaultStepTimeout = 2 * time.Minute
+
+// stepRun tracks a single step during a mission execution.
+// out and ok are written before done is closed, so dependents may read them after <-done.
+type stepRun struct {
+	step   domain.MissionStep
+	done   chan struct{}
+	out    domain.CloudEvent // Event handed to dependents
+	ok     bool              // False if the step failed or was skipped
+	record domain.MissionStepRun
+}
+
+// executeMission runs the mission graph. Steps start as soon as all of their
+// upstream steps have completed, so independent branches run in parallel.
+func (m *MissionManager) executeMission(mission domain.Mission, runID string, trigger domain.CloudEvent) {
+	// Cancelled when a step fails with on_error: abort
+	ctx, abort := context.WithCancel(context.Background())
+	defer abort()
 	log.Printf("[ORCHESTRATOR] Triggering Mission: %s", mission.Name)
 
-	currentPayload := trigger
+	record := domain.MissionRun{
+		ID:             runID,
+		MissionID:      mission.ID,
+		TriggerEventID: trigger.ID,
+		TriggerType:    trigger.Type,
+		Status:         domain.RunStatusRunning,
+		StartedAt:      time.Now().UTC(),
+	}
 
-	for _, agentID := range mission.Agents {
-		agent, err := m.registry.Get(agentID)
-		if err != nil {
-			log.Printf("[ERROR] Mission Failed: %v", err)
+	steps := MissionSteps(mission)
+	runs := make(map[string]*stepRun, len(steps))
+	for _, s := range steps {
+		runs[s.ID] = &stepRun{
+			step:   s,
+			done:   make(chan struct{}),
+			record: domain.MissionStepRun{StepID: s.ID, AgentID: s.Agent, Status: domain.RunStatusSkipped},
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, run := range runs {
+		wg.Add(1)
+		go func(run *stepRun) {
+			defer wg.Done()
+			defer close(run.done)
+			m.executeStep(ctx, abort, mission, run, runs, trigger)
+		}(run)
+	}
+	wg.Wait()
+
+	// Aborting cancels the context; an untouched context means the mission ran to completion
+	record.Status = domain.RunStatusSucceeded
+	for _, s := range steps {
+		step := runs[s.ID].record
+		record.Steps = append(record.Steps, step)
+		if ctx.Err() != nil && step.Status == domain.RunStatusFailed && record.Error == "" {
+			record.Status = domain.RunStatusFailed
+			record.Error = fmt.Sprintf("step %s: %s", step.StepID, step.Error)
+		}
+	}
+	record.FinishedAt = time.Now().UTC()
+	log.Printf("[ORCHESTRATOR] Mission '%s' %s in %s", mission.ID, record.Status, record.FinishedAt.Sub(record.StartedAt))
+
+	m.saveRun(record)
+}
+
+func (m *MissionManager) saveRun(run domain.MissionRun) {
+	if m.runs == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := m.runs.SaveMissionRun(ctx, run); err != nil {
+		log.Printf("[WARN] Failed to save run %s of mission %s: %v", run.ID, run.MissionID, err)
+	}
+}
+
+func (m *MissionManager) executeStep(ctx context.Context, abort context.CancelFunc, mission domain.Mission, run *stepRun, runs map[string]*stepRun, trigger domain.CloudEvent) {
+	// 1. Wait for upstream steps
+	for _, need := range run.step.Needs {
+		<-runs[need].done
+	}
+	for _, need := range run.step.Needs {
+		if !runs[need].ok {
+			log.Printf("[EXEC] Step '%s' skipped (upstream '%s' did not complete)", run.step.ID, need)
 			return
 		}
+	}
+	if ctx.Err() != nil {
+		log.Printf("[EXEC] Step '%s' skipped (mission aborted)", run.step.ID)
+		return
+	}
+
+	rec := &run.record
+	rec.StartedAt = time.Now().UTC()
+	defer func() { rec.DurationMs = time.Since(rec.StartedAt).Milliseconds() }()
+	fail := func(err error) {
+		rec.Status = domain.RunStatusFailed
+		rec.Error = err.Error()
+	}
 
-		log.Printf("[EXEC] Agent '%s' starting...", agent.ID())
-		output, err := agent.Execute(ctx, currentPayload)
-		if err != nil {
-			log.Printf("[ERROR] Agent '%s' failed: %v", agent.ID(), err)
+	input, err := stepInput(mission, run.step, runs, trigger)
+	if err != nil {
+		log.Printf("[ERROR] Step '%s' failed: %v", run.step.ID, err)
+		fail(err)
+		abort()
+		return
+	}
+	rec.Input = &input
+
+	// 2. Condition: a step whose "when" does not hold is skipped (and so are its dependents)
+	cond, err := ParseCondition(run.step.When)
+	if err != nil {
+		log.Printf("[ERROR] Step '%s' failed: %v", run.step.ID, err)
+		fail(err)
+		abort()
+		return
+	}
+	// An expression that cannot be evaluated fails the step rather than reading as false
+	ok, err := cond.Eval(input)
+	if err != nil {
+		err = fmt.Errorf("evaluating when %q: %w", cond, err)
+		log.Printf("[ERROR] Step '%s' failed: %v", run.step.ID, err)
+		fail(err)
+		abort()
+		return
+	}
+	if !ok {
+		log.Printf("[EXEC] Step '%s' skipped (when: %s)", run.step.ID, cond)
+		return
+	}
+
+	// 3. Execute (with retries), then apply on_error
+	policy := run.step.StepPolicy.Merge(mission.Defaults)
+	agentID := run.step.Agent
+	output, attempts, err := m.runWithRetries(ctx, agentID, input, policy)
+	rec.Attempts = attempts
+	if err != nil {
+		log.Printf("[ERROR] Agent '%s' failed: %v", agentID, err)
+		rec.Error = err.Error()
+
+		fallback, isFallback := policy.FallbackAgent()
+		switch {
+		case policy.OnError == domain.OnErrorContinue:
+			log.Printf("[EXEC] Step '%s' continuing after error (on_error: continue)", run.step.ID)
+			output = nil
+		case isFallback && ctx.Err() == nil:
+			log.Printf("[EXEC] Step '%s' falling back to agent '%s'", run.step.ID, fallback)
+			agentID = fallback
+			rec.AgentID = fallback
+			rec.Attempts++
+			output, err = m.runAttempt(ctx, agentID, input, policy.AttemptTimeout())
+			if err != nil {
+				log.Printf("[ERROR] Fallback agent '%s' failed: %v", agentID, err)
+				fail(err)
+				abort()
+				return
+			}
+		default:
+			log.Printf("[ERROR] Mission '%s' aborted at step '%s'", mission.ID, run.step.ID)
+			fail(err)
+			abort()
 			return
 		}
+	}
+
+	// 4. Pipeline: Output becomes input for dependents (otherwise the input passes through)
+	rec.Status = domain.RunStatusSucceeded
+	rec.Output = output
+	run.out = input
+	run.ok = true
+	if output != nil {
+		run.out = *output
+		log.Printf("[EXEC] Agent '%s' produced output type: %s", agentID, output.Type)
+
+		// Publish the output side-effect
+		topic := fmt.Sprintf("agent/%s/output", agentID)
+		if output.Type == domain.ToolCallEventType {
+			topic = "tool/call"
+		}
+		m.publish(topic, *output)
+	}
+}
 
-		// Pipeline: Output becomes input for next agent (if exists)
-		if output != nil {
-			currentPayload = *output
-			log.Printf("[EXEC] Agent '%s' produced output type: %s", agent.ID(), output.Type)
-
-			// Publish the output side-effect
-			topic := output.Type // Default fallback
-			if output.Type == "tool.call" {
-				topic = "tool/call"
-			} else {
-				topic = fmt.Sprintf("agent/%s/output", agent.ID())
+// runWithRetries executes an agent up to 1+Retries times with exponential backoff between attempts.
+// It returns the number of attempts made.
+func (m *MissionManager) runWithRetries(ctx context.Context, agentID string, input domain.CloudEvent, policy domain.StepPolicy) (*domain.CloudEvent, int, error) {
+	backoff := policy.RetryBackoff()
+	var err error
+	for attempt := 0; attempt <= policy.RetryLimit(); attempt++ {
+		if attempt > 0 {
+			log.Printf("[EXEC] Retrying agent '%s' (attempt %d/%d) in %s", agentID, attempt+1, policy.RetryLimit()+1, backoff)
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return nil, attempt, fmt.Errorf("mission aborted: %w", err)
 			}
-			m.publish(topic, *output)
+			backoff *= 2
+		}
+
+		var output *domain.CloudEvent
+		output, err = m.runAttempt(ctx, agentID, input, policy.AttemptTimeout())
+		if err == nil {
+			return output, attempt + 1, nil
+		}
+	}
+	return nil, policy.RetryLimit() + 1, err
+}
+
+// runAttempt executes an agent once under a deadline. The agent runs in its own goroutine,
+// so the mission is released on timeout even if the agent ignores its context.
+func (m *MissionManager) runAttempt(ctx context.Context, agentID string, input domain.CloudEvent, timeout time.Duration) (*domain.CloudEvent, error) {
+	agent, err := m.registry.Get(agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := timeout
+	if limit <= 0 {
+		limit = DefaultStepTimeout
+	}
+	attemptCtx, cancel := context.WithTimeout(ctx, limit)
+	defer cancel()
+
+	type result struct {
+		output *domain.CloudEvent
+		err    error
+	}
+	done := make(chan result, 1)
+
+	log.Printf("[EXEC] Agent '%s' starting...", agent.ID())
+	go func() {
+		output, err := agent.Execute(attemptCtx, input)
+		done <- result{output, err}
+	}()
+
+	select {
+	case r := <-done:
+		m.registry.RecordExecution(agentID, r.err)
+		return r.output, r.err
+	case <-attemptCtx.Done():
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("mission aborted: %w", ctx.Err())
 		}
+		err := fmt.Errorf("agent '%s' timed out after %s", agentID, limit)
+		m.registry.RecordExecution(agentID, err)
+		return nil, err
+	}
+}
+
+// stepInput builds the event a step receives: the trigger for root steps,
+// the upstream output for single dependencies, or a join event for fan-in.
+func stepInput(mission domain.Mission, step domain.MissionStep, runs map[string]*stepRun, trigger domain.CloudEvent) (domain.CloudEvent, error) {
+	switch len(step.Needs) {
+	case 0:
+		return trigger, nil
+	case 1:
+		return runs[step.Needs[0]].out, nil
+	}
+
+	inputs := make(map[string]domain.CloudEvent, len(step.Needs))
+	for _, need := range step.Needs {
+		inputs[need] = runs[need].out
 	}
+	return domain.NewEvent(fmt.Sprintf("mission/%s", mission.ID), domain.JoinEventType, map[string]interface{}{
+		"inputs":  inputs,
+		"trigger": trigger.ID,
+	})
 }
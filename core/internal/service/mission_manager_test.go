@@ -2,7 +2,12 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/datacraft/catalyst/core/internal/domain"
 )
@@ -27,6 +32,25 @@ func (m *MockAgent) Execute(ctx context.Context, input domain.CloudEvent) (*doma
 	return m.outputEvent, nil
 }
 
+// waitIdle blocks until every mission queue is empty and no run is in flight.
+func waitIdle(t *testing.T, m *MissionManager) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		busy := false
+		for _, s := range m.Stats() {
+			if s.Queued > 0 || s.InFlight > 0 {
+				busy = true
+			}
+		}
+		if !busy {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("Missions did not become idle")
+}
+
 func TestMissionManager_ProcessEvent(t *testing.T) {
 	// Setup Registry
 	registry := NewAgentRegistry()
@@ -49,13 +73,14 @@ func TestMissionManager_ProcessEvent(t *testing.T) {
 	})
 
 	// Scenario 1: Matching Event
-	evt1, _ := domain.NewEvent("source1", "sensor/test", nil)
-	manager.ProcessEvent(evt1)
+	evt1, _ := domain.NewEvent("source1", "sensor.test", nil)
+	manager.ProcessEvent("sensor/test", evt1)
+	waitIdle(t, manager)
 
 	if !mockAgent.executed {
 		t.Errorf("Expected agent to execute for matching triggering topic")
 	}
-	if mockAgent.lastInput.Type != "sensor/test" {
+	if mockAgent.lastInput.Type != "sensor.test" {
 		t.Errorf("Expected agent to receive the event")
 	}
 
@@ -63,10 +88,598 @@ func TestMissionManager_ProcessEvent(t *testing.T) {
 	mockAgent.executed = false
 
 	// Scenario 2: Non-matching Event
-	evt2, _ := domain.NewEvent("source1", "sensor/other", nil)
-	manager.ProcessEvent(evt2)
+	evt2, _ := domain.NewEvent("source1", "sensor.other", nil)
+	manager.ProcessEvent("sensor/other", evt2)
+	waitIdle(t, manager)
 
 	if mockAgent.executed {
 		t.Errorf("Agent executed for non-matching topic")
 	}
 }
+
+func TestMissionManager_WildcardTriggers(t *testing.T) {
+	registry := NewAgentRegistry()
+	cpuAgent := &MockAgent{id: "CPUAgent"}
+	repoAgent := &MockAgent{id: "RepoAgent"}
+	typeAgent := &MockAgent{id: "TypeAgent"}
+	registry.Register(cpuAgent)
+	registry.Register(repoAgent)
+	registry.Register(typeAgent)
+
+	manager := NewMissionManager(registry, func(topic string, event domain.CloudEvent) {})
+	manager.LoadMission(domain.Mission{ID: "cpu", TriggerTopic: "sensor/cpu/+", Agents: []string{"CPUAgent"}})
+	manager.LoadMission(domain.Mission{ID: "repo", TriggerTopic: "repo/+/event", Agents: []string{"RepoAgent"}})
+	manager.LoadMission(domain.Mission{ID: "type", TriggerType: "repo.*", Agents: []string{"TypeAgent"}})
+
+	temp, _ := domain.NewEvent("device-mock", "sensor.cpu.temp", nil)
+	manager.ProcessEvent("sensor/cpu/temp", temp)
+	waitIdle(t, manager)
+	if !cpuAgent.executed || repoAgent.executed || typeAgent.executed {
+		t.Errorf("Expected only CPUAgent to run for sensor/cpu/temp")
+	}
+
+	push, _ := domain.NewEvent("repo-watcher", "repo.push", nil)
+	manager.ProcessEvent("repo/catalyst/event", push)
+	waitIdle(t, manager)
+	if !repoAgent.executed {
+		t.Errorf("Expected RepoAgent to run for repo/catalyst/event")
+	}
+	if !typeAgent.executed {
+		t.Errorf("Expected TypeAgent to run for type repo.push")
+	}
+
+	cpuAgent.executed = false
+	deep, _ := domain.NewEvent("device-mock", "sensor.cpu.core.temp", nil)
+	manager.ProcessEvent("sensor/cpu/core/temp", deep)
+	waitIdle(t, manager)
+	if cpuAgent.executed {
+		t.Errorf("'+' must not match multiple levels")
+	}
+}
+
+// RecordingAgent records its input and emits an event of its own ID as type.
+type RecordingAgent struct {
+	id    string
+	mu    sync.Mutex
+	calls []domain.CloudEvent
+}
+
+func (r *RecordingAgent) ID() string             { return r.id }
+func (r *RecordingAgent) Type() domain.AgentType { return domain.AgentTypeCommunicator }
+func (r *RecordingAgent) Execute(ctx context.Context, input domain.CloudEvent) (*domain.CloudEvent, error) {
+	r.mu.Lock()
+	r.calls = append(r.calls, input)
+	r.mu.Unlock()
+	out, _ := domain.NewEvent(r.id, "out."+r.id, nil)
+	return &out, nil
+}
+
+func (r *RecordingAgent) Calls() []domain.CloudEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]domain.CloudEvent{}, r.calls...)
+}
+
+func TestMissionManager_DAGFanOutAndJoin(t *testing.T) {
+	registry := NewAgentRegistry()
+	agents := map[string]*RecordingAgent{}
+	for _, id := range []string{"Root", "Left", "Right", "Join"} {
+		agents[id] = &RecordingAgent{id: id}
+		registry.Register(agents[id])
+	}
+
+	var mu sync.Mutex
+	published := map[string]int{}
+	manager := NewMissionManager(registry, func(topic string, event domain.CloudEvent) {
+		mu.Lock()
+		published[topic]++
+		mu.Unlock()
+	})
+
+	err := manager.LoadMission(domain.Mission{
+		ID:           "diamond",
+		TriggerTopic: "sensor/#",
+		Steps: []domain.MissionStep{
+			{ID: "root", Agent: "Root"},
+			{ID: "left", Agent: "Left", Needs: []string{"root"}},
+			{ID: "right", Agent: "Right", Needs: []string{"root"}},
+			{ID: "join", Agent: "Join", Needs: []string{"left", "right"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to load mission: %v", err)
+	}
+
+	trigger, _ := domain.NewEvent("device-mock", "sensor.cpu.temp", nil)
+	manager.ProcessEvent("sensor/cpu/temp", trigger)
+	waitIdle(t, manager)
+
+	if calls := agents["Left"].Calls(); len(calls) != 1 || calls[0].Type != "out.Root" {
+		t.Errorf("Expected Left to receive Root's output, got %+v", calls)
+	}
+	if calls := agents["Right"].Calls(); len(calls) != 1 || calls[0].Type != "out.Root" {
+		t.Errorf("Expected Right to receive Root's output, got %+v", calls)
+	}
+
+	calls := agents["Join"].Calls()
+	if len(calls) != 1 {
+		t.Fatalf("Expected Join to execute once, got %d", len(calls))
+	}
+	if calls[0].Type != domain.JoinEventType {
+		t.Fatalf("Expected join event, got %s", calls[0].Type)
+	}
+	var data struct {
+		Inputs map[string]domain.CloudEvent `json:"inputs"`
+	}
+	if err := json.Unmarshal(calls[0].Data, &data); err != nil {
+		t.Fatalf("Failed to decode join data: %v", err)
+	}
+	if data.Inputs["left"].Type != "out.Left" || data.Inputs["right"].Type != "out.Right" {
+		t.Errorf("Expected join inputs from left and right, got %+v", data.Inputs)
+	}
+
+	if published["agent/Join/output"] != 1 {
+		t.Errorf("Expected Join output to be published, got %v", published)
+	}
+}
+
+func TestMissionManager_RejectsCycles(t *testing.T) {
+	manager := NewMissionManager(NewAgentRegistry(), func(topic string, event domain.CloudEvent) {})
+	err := manager.LoadMission(domain.Mission{
+		ID: "loop",
+		Steps: []domain.MissionStep{
+			{ID: "a", Agent: "A", Needs: []string{"b"}},
+			{ID: "b", Agent: "B", Needs: []string{"a"}},
+		},
+	})
+	if err == nil {
+		t.Fatalf("Expected cyclic mission to be rejected")
+	}
+}
+
+// AlertAgent emits a security alert with the given severity.
+type AlertAgent struct {
+	id       string
+	severity string
+}
+
+func (a *AlertAgent) ID() string             { return a.id }
+func (a *AlertAgent) Type() domain.AgentType { return domain.AgentTypeCommunicator }
+func (a *AlertAgent) Execute(ctx context.Context, input domain.CloudEvent) (*domain.CloudEvent, error) {
+	out, _ := domain.NewEvent(a.id, "swarm.security.alert", map[string]string{"severity": a.severity})
+	return &out, nil
+}
+
+func TestMissionManager_ConditionalSteps(t *testing.T) {
+	registry := NewAgentRegistry()
+	scout := &AlertAgent{id: "Scout", severity: "warning"}
+	engineer := &RecordingAgent{id: "Engineer"}
+	reporter := &RecordingAgent{id: "Reporter"}
+	registry.Register(scout)
+	registry.Register(engineer)
+	registry.Register(reporter)
+
+	manager := NewMissionManager(registry, func(topic string, event domain.CloudEvent) {})
+	err := manager.LoadMission(domain.Mission{
+		ID:           "gated",
+		TriggerTopic: "sensor/#",
+		Steps: []domain.MissionStep{
+			{ID: "scout", Agent: "Scout"},
+			{ID: "fix", Agent: "Engineer", Needs: []string{"scout"}, When: `type == "swarm.security.alert" && data.severity == "critical"`},
+			{ID: "report", Agent: "Reporter", Needs: []string{"fix"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to load mission: %v", err)
+	}
+
+	trigger, _ := domain.NewEvent("device-mock", "sensor.cpu.temp", nil)
+	manager.ProcessEvent("sensor/cpu/temp", trigger)
+	waitIdle(t, manager)
+	if len(engineer.Calls()) != 0 || len(reporter.Calls()) != 0 {
+		t.Errorf("Expected gated step and its dependents to be skipped for a warning")
+	}
+
+	scout.severity = "critical"
+	manager.ProcessEvent("sensor/cpu/temp", trigger)
+	waitIdle(t, manager)
+	if len(engineer.Calls()) != 1 {
+		t.Errorf("Expected Engineer to run for a critical alert")
+	}
+	if len(reporter.Calls()) != 1 {
+		t.Errorf("Expected Reporter to run after Engineer")
+	}
+}
+
+func TestMissionManager_ConditionErrorFailsStep(t *testing.T) {
+	registry := NewAgentRegistry()
+	registry.Register(&AlertAgent{id: "Scout", severity: "critical"})
+	engineer := &RecordingAgent{id: "Engineer"}
+	registry.Register(engineer)
+
+	runs := &MemoryRunStore{}
+	manager := NewMissionManager(registry, func(string, domain.CloudEvent) {})
+	manager.SetRunStore(runs)
+	manager.LoadMission(domain.Mission{ID: "m", TriggerTopic: "#", Steps: []domain.MissionStep{
+		{ID: "scout", Agent: "Scout"},
+		// severity is a string: the comparison cannot be evaluated
+		{ID: "fix", Agent: "Engineer", Needs: []string{"scout"}, When: `data.severity > 3`},
+	}})
+
+	trigger, _ := domain.NewEvent("device-mock", "sensor.cpu.temp", nil)
+	manager.ProcessEvent("sensor/cpu/temp", trigger)
+	waitIdle(t, manager)
+
+	saved, _ := runs.ListMissionRuns(context.Background(), "m", 10)
+	if len(saved) != 1 || saved[0].Status != domain.RunStatusFailed {
+		t.Fatalf("Expected a failed run, got %+v", saved)
+	}
+	for _, s := range saved[0].Steps {
+		if s.StepID == "fix" && (s.Status != domain.RunStatusFailed || s.Error == "") {
+			t.Errorf("Expected the gated step to fail, got %+v", s)
+		}
+	}
+	if len(engineer.Calls()) != 0 {
+		t.Errorf("Engineer ran although its condition failed")
+	}
+}
+
+func TestMissionManager_RejectsBadConditions(t *testing.T) {
+	manager := NewMissionManager(NewAgentRegistry(), func(topic string, event domain.CloudEvent) {})
+	err := manager.LoadMission(domain.Mission{
+		ID:    "bad",
+		Steps: []domain.MissionStep{{ID: "a", Agent: "A", When: `data.severity ==`}},
+	})
+	if err == nil {
+		t.Fatalf("Expected mission with malformed condition to be rejected")
+	}
+}
+
+// FlakyAgent fails the first `failures` calls, optionally blocking until its context is done.
+type FlakyAgent struct {
+	id       string
+	failures int
+	block    bool
+	mu       sync.Mutex
+	calls    int
+}
+
+func (f *FlakyAgent) ID() string             { return f.id }
+func (f *FlakyAgent) Type() domain.AgentType { return domain.AgentTypeExpressor }
+func (f *FlakyAgent) Execute(ctx context.Context, input domain.CloudEvent) (*domain.CloudEvent, error) {
+	f.mu.Lock()
+	f.calls++
+	n := f.calls
+	f.mu.Unlock()
+
+	if f.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if n <= f.failures {
+		return nil, fmt.Errorf("transient failure %d", n)
+	}
+	out, _ := domain.NewEvent(f.id, "out."+f.id, nil)
+	return &out, nil
+}
+
+func (f *FlakyAgent) Calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+func TestMissionManager_StepPolicies(t *testing.T) {
+	ms := domain.Duration(time.Millisecond)
+
+	t.Run("retries with backoff", func(t *testing.T) {
+		registry := NewAgentRegistry()
+		flaky := &FlakyAgent{id: "Flaky", failures: 2}
+		next := &RecordingAgent{id: "Next"}
+		registry.Register(flaky)
+		registry.Register(next)
+
+		manager := NewMissionManager(registry, func(string, domain.CloudEvent) {})
+		manager.LoadMission(domain.Mission{ID: "m", TriggerTopic: "#", Steps: []domain.MissionStep{
+			{ID: "flaky", Agent: "Flaky", StepPolicy: domain.StepPolicy{Retries: ptr(2), Backoff: ptr(ms)}},
+			{ID: "next", Agent: "Next", Needs: []string{"flaky"}},
+		}})
+
+		evt, _ := domain.NewEvent("test", "test", nil)
+		manager.ProcessEvent("test", evt)
+		waitIdle(t, manager)
+
+		if flaky.Calls() != 3 {
+			t.Errorf("Expected 3 attempts, got %d", flaky.Calls())
+		}
+		if calls := next.Calls(); len(calls) != 1 || calls[0].Type != "out.Flaky" {
+			t.Errorf("Expected Next to receive Flaky's output after retries, got %+v", calls)
+		}
+	})
+
+	t.Run("timeout then continue", func(t *testing.T) {
+		registry := NewAgentRegistry()
+		slow := &FlakyAgent{id: "Slow", block: true}
+		next := &RecordingAgent{id: "Next"}
+		registry.Register(slow)
+		registry.Register(next)
+
+		manager := NewMissionManager(registry, func(string, domain.CloudEvent) {})
+		manager.LoadMission(domain.Mission{ID: "m", TriggerTopic: "#", Steps: []domain.MissionStep{
+			{ID: "slow", Agent: "Slow", StepPolicy: domain.StepPolicy{Timeout: ptr(20 * ms), OnError: domain.OnErrorContinue}},
+			{ID: "next", Agent: "Next", Needs: []string{"slow"}},
+		}})
+
+		evt, _ := domain.NewEvent("test", "trigger", nil)
+		start := time.Now()
+		manager.ProcessEvent("test", evt)
+		waitIdle(t, manager)
+
+		if time.Since(start) > time.Second {
+			t.Errorf("Expected step timeout to release the mission")
+		}
+		if calls := next.Calls(); len(calls) != 1 || calls[0].Type != "trigger" {
+			t.Errorf("Expected Next to receive the passthrough input, got %+v", calls)
+		}
+	})
+
+	t.Run("fallback agent", func(t *testing.T) {
+		registry := NewAgentRegistry()
+		broken := &FlakyAgent{id: "Broken", failures: 100}
+		backup := &RecordingAgent{id: "Backup"}
+		next := &RecordingAgent{id: "Next"}
+		registry.Register(broken)
+		registry.Register(backup)
+		registry.Register(next)
+
+		manager := NewMissionManager(registry, func(string, domain.CloudEvent) {})
+		manager.LoadMission(domain.Mission{ID: "m", TriggerTopic: "#", Steps: []domain.MissionStep{
+			{ID: "work", Agent: "Broken", StepPolicy: domain.StepPolicy{OnError: "fallback:Backup"}},
+			{ID: "next", Agent: "Next", Needs: []string{"work"}},
+		}})
+
+		evt, _ := domain.NewEvent("test", "trigger", nil)
+		manager.ProcessEvent("test", evt)
+		waitIdle(t, manager)
+
+		if len(backup.Calls()) != 1 {
+			t.Errorf("Expected fallback agent to run once")
+		}
+		if calls := next.Calls(); len(calls) != 1 || calls[0].Type != "out.Backup" {
+			t.Errorf("Expected Next to receive the fallback output, got %+v", calls)
+		}
+	})
+
+	t.Run("abort cancels running branches", func(t *testing.T) {
+		registry := NewAgentRegistry()
+		broken := &FlakyAgent{id: "Broken", failures: 100}
+		slow := &FlakyAgent{id: "Slow", block: true}
+		after := &RecordingAgent{id: "After"}
+		registry.Register(broken)
+		registry.Register(slow)
+		registry.Register(after)
+
+		manager := NewMissionManager(registry, func(string, domain.CloudEvent) {})
+		manager.LoadMission(domain.Mission{ID: "m", TriggerTopic: "#", Steps: []domain.MissionStep{
+			{ID: "broken", Agent: "Broken"},
+			{ID: "slow", Agent: "Slow"},
+			{ID: "after", Agent: "After", Needs: []string{"slow"}},
+		}})
+
+		evt, _ := domain.NewEvent("test", "trigger", nil)
+		start := time.Now()
+		manager.ProcessEvent("test", evt)
+		waitIdle(t, manager)
+
+		if time.Since(start) > time.Second {
+			t.Errorf("Expected abort to cancel the slow branch")
+		}
+		if len(after.Calls()) != 0 {
+			t.Errorf("Expected dependents of an aborted mission to be skipped")
+		}
+	})
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestValidateMission_Policies(t *testing.T) {
+	known := map[string]bool{"A": true}
+	bad := []domain.Mission{
+		{ID: "m", Agents: []string{"A"}, Defaults: domain.StepPolicy{OnError: "explode"}},
+		{ID: "m", Steps: []domain.MissionStep{{Agent: "A", StepPolicy: domain.StepPolicy{Retries: ptr(-1)}}}},
+		{ID: "m", Steps: []domain.MissionStep{{Agent: "A", StepPolicy: domain.StepPolicy{OnError: "fallback:Ghost"}}}},
+	}
+	for i, m := range bad {
+		if err := ValidateMission(m, known); err == nil {
+			t.Errorf("case %d: expected invalid policy to be rejected", i)
+		}
+	}
+}
+
+// MemoryRunStore records saved runs for assertions.
+type MemoryRunStore struct {
+	mu   sync.Mutex
+	runs []domain.MissionRun
+}
+
+func (s *MemoryRunStore) SaveMissionRun(ctx context.Context, run domain.MissionRun) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runs = append(s.runs, run)
+	return nil
+}
+
+func (s *MemoryRunStore) ListMissionRuns(ctx context.Context, missionID string, limit int) ([]domain.MissionRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]domain.MissionRun{}, s.runs...), nil
+}
+
+func TestMissionManager_RecordsRuns(t *testing.T) {
+	registry := NewAgentRegistry()
+	registry.Register(&RecordingAgent{id: "Scout"})
+	registry.Register(&FlakyAgent{id: "Broken", failures: 100})
+	registry.Register(&RecordingAgent{id: "Never"})
+
+	runs := &MemoryRunStore{}
+	manager := NewMissionManager(registry, func(string, domain.CloudEvent) {})
+	manager.SetRunStore(runs)
+	manager.LoadMission(domain.Mission{ID: "m", TriggerTopic: "#", Steps: []domain.MissionStep{
+		{ID: "scout", Agent: "Scout"},
+		{ID: "broken", Agent: "Broken", Needs: []string{"scout"}, StepPolicy: domain.StepPolicy{Retries: ptr(1)}},
+		{ID: "never", Agent: "Never", Needs: []string{"broken"}},
+	}})
+
+	trigger, _ := domain.NewEvent("device-mock", "sensor.cpu.temp", nil)
+	manager.ProcessEvent("sensor/cpu/temp", trigger)
+	waitIdle(t, manager)
+
+	saved, _ := runs.ListMissionRuns(context.Background(), "m", 10)
+	if len(saved) != 1 {
+		t.Fatalf("Expected 1 recorded run, got %d", len(saved))
+	}
+	run := saved[0]
+	if run.MissionID != "m" || run.TriggerEventID != trigger.ID || run.Status != domain.RunStatusFailed {
+		t.Errorf("Unexpected run record: %+v", run)
+	}
+
+	byStep := map[string]domain.MissionStepRun{}
+	for _, s := range run.Steps {
+		byStep[s.StepID] = s
+	}
+	if s := byStep["scout"]; s.Status != domain.RunStatusSucceeded || s.Output == nil || s.Input.ID != trigger.ID {
+		t.Errorf("Unexpected scout record: %+v", s)
+	}
+	if s := byStep["broken"]; s.Status != domain.RunStatusFailed || s.Attempts != 2 || s.Error == "" {
+		t.Errorf("Unexpected broken record: %+v", s)
+	}
+	if s := byStep["never"]; s.Status != domain.RunStatusSkipped {
+		t.Errorf("Expected never to be skipped, got %+v", s)
+	}
+}
+
+// GateAgent blocks every execution until release is closed.
+type GateAgent struct {
+	id      string
+	release chan struct{}
+	running atomic.Int32
+	peak    atomic.Int32
+}
+
+func (g *GateAgent) ID() string             { return g.id }
+func (g *GateAgent) Type() domain.AgentType { return domain.AgentTypeReporter }
+func (g *GateAgent) Execute(ctx context.Context, input domain.CloudEvent) (*domain.CloudEvent, error) {
+	n := g.running.Add(1)
+	for {
+		peak := g.peak.Load()
+		if n <= peak || g.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	<-g.release
+	g.running.Add(-1)
+	return nil, nil
+}
+
+func TestMissionManager_WorkerPool(t *testing.T) {
+	registry := NewAgentRegistry()
+	gate := &GateAgent{id: "Gate", release: make(chan struct{})}
+	registry.Register(gate)
+
+	manager := NewMissionManager(registry, func(string, domain.CloudEvent) {})
+	manager.LoadMission(domain.Mission{ID: "pool", TriggerTopic: "#", Agents: []string{"Gate"}, Concurrency: 2, QueueDepth: 3})
+
+	evt, _ := domain.NewEvent("test", "trigger", nil)
+	manager.ProcessEvent("test", evt)
+	manager.ProcessEvent("test", evt)
+
+	// Wait for both workers to pick up a run
+	deadline := time.Now().Add(5 * time.Second)
+	for gate.running.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Workers are busy: 3 triggers fit in the queue, the rest are dropped
+	start := time.Now()
+	for i := 0; i < 8; i++ {
+		manager.ProcessEvent("test", evt)
+	}
+	if time.Since(start) > time.Second {
+		t.Fatalf("ProcessEvent must not block on busy agents")
+	}
+
+	stats := manager.Stats()[0]
+	if stats.InFlight != 2 || stats.Queued != 3 || stats.Dropped != 5 {
+		t.Errorf("Unexpected stats with 2 workers and depth 3: %+v", stats)
+	}
+
+	// Shutdown drains the backlog once the agent is released
+	close(gate.release)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := manager.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	stats = manager.Stats()[0]
+	if stats.Processed != 5 {
+		t.Errorf("Expected 5 processed runs after drain, got %+v", stats)
+	}
+	if gate.peak.Load() > 2 {
+		t.Errorf("Expected at most 2 concurrent runs, saw %d", gate.peak.Load())
+	}
+
+	// Triggers after shutdown are ignored
+	manager.ProcessEvent("test", evt)
+	if manager.Stats()[0].Queued != 0 {
+		t.Errorf("Expected no triggers to be queued after shutdown")
+	}
+}
+
+func TestMissionManager_ReloadWaitsForReplacedQueue(t *testing.T) {
+	registry := NewAgentRegistry()
+	gate := &GateAgent{id: "Gate", release: make(chan struct{})}
+	registry.Register(gate)
+
+	manager := NewMissionManager(registry, func(string, domain.CloudEvent) {})
+	mission := domain.Mission{ID: "m", Name: "v1", TriggerTopic: "#", Agents: []string{"Gate"}}
+	manager.LoadMission(mission)
+
+	evt, _ := domain.NewEvent("test", "trigger", nil)
+	manager.ProcessEvent("test", evt)
+	manager.ProcessEvent("test", evt)
+	deadline := time.Now().Add(5 * time.Second)
+	for gate.running.Load() < 1 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	// The new queue holds its trigger until the old one has drained
+	mission.Name = "v2"
+	manager.LoadMission(mission)
+	manager.ProcessEvent("test", evt)
+	time.Sleep(50 * time.Millisecond)
+	if n := gate.running.Load(); n != 1 {
+		t.Errorf("Expected 1 run while the old queue drains, got %d", n)
+	}
+
+	close(gate.release)
+	waitIdle(t, manager)
+	for {
+		manager.mu.RLock()
+		retired := len(manager.retired)
+		manager.mu.RUnlock()
+		if retired == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Drained queue still retired")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if gate.peak.Load() != 1 {
+		t.Errorf("Expected at most 1 concurrent run across the reload, saw %d", gate.peak.Load())
+	}
+	if stats := manager.Stats()[0]; stats.Processed != 1 {
+		t.Errorf("Expected the new queue to run its trigger, got %+v", stats)
+	}
+}
@@ -0,0 +1,369 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+	"github.com/google/uuid"
+)
+
+// Defaults for missions that do not configure their worker pool.
+const (
+	DefaultMissionConcurrency = 1
+	DefaultMissionQueueDepth  = 100
+)
+
+// Durable queue tuning.
+const (
+	DurableQueuePollInterval = 2 * time.Second
+	DurableQueueLease        = time.Minute    // Renewed every Lease/3 while a run executes
+	DurableQueueRetention    = 24 * time.Hour // Finished runs kept for de-duplication across replicas
+	durableQueuePruneEvery   = 10 * time.Minute
+)
+
+// QueueStats is a snapshot of a mission's worker pool.
+type QueueStats struct {
+	MissionID   string `json:"mission_id"`
+	Durable     bool   `json:"durable"`
+	Concurrency int    `json:"concurrency"`
+	Capacity    int    `json:"capacity"`
+	Queued      int    `json:"queued"`    // Runs waiting for a worker
+	InFlight    int    `json:"in_flight"` // Runs currently executing
+	Processed   uint64 `json:"processed"` // Runs completed since start
+	Dropped     uint64 `json:"dropped"`   // Triggers rejected because the queue was full
+}
+
+// runFunc executes one mission run.
+type runFunc func(mission domain.Mission, runID string, trigger domain.CloudEvent)
+
+// runQueue feeds triggers of one mission to a fixed set of workers.
+type runQueue interface {
+	mission() domain.Mission
+	// start launches the workers. They take no run before after is closed (if not nil), so
+	// that a replaced queue of the same mission finishes first.
+	start(execute runFunc, after <-chan struct{})
+	// enqueue adds a trigger without waiting for a worker. It returns false if the trigger was dropped.
+	enqueue(trigger domain.CloudEvent) bool
+	// close stops intake; wait returns once the workers have exited.
+	close()
+	wait()
+	stats() QueueStats
+}
+
+func missionConcurrency(m domain.Mission) int {
+	if m.Concurrency <= 0 {
+		return DefaultMissionConcurrency
+	}
+	return m.Concurrency
+}
+
+func missionQueueDepth(m domain.Mission) int {
+	if m.QueueDepth <= 0 {
+		return DefaultMissionQueueDepth
+	}
+	return m.QueueDepth
+}
+
+// --- In-memory queue ---
+
+// memoryQueue is a bounded channel of triggers. Queued runs are lost if the process exits.
+type memoryQueue struct {
+	m       domain.Mission
+	jobs    chan domain.CloudEvent
+	workers sync.WaitGroup
+
+	pending   atomic.Int64 // Queued + in flight
+	processed atomic.Uint64
+	dropped   atomic.Uint64
+}
+
+func newMemoryQueue(mission domain.Mission) *memoryQueue {
+	return &memoryQueue{
+		m:    mission,
+		jobs: make(chan domain.CloudEvent, missionQueueDepth(mission)),
+	}
+}
+
+func (q *memoryQueue) mission() domain.Mission { return q.m }
+
+func (q *memoryQueue) start(execute runFunc, after <-chan struct{}) {
+	for i := 0; i < missionConcurrency(q.m); i++ {
+		q.workers.Add(1)
+		go func() {
+			defer q.workers.Done()
+			if after != nil {
+				<-after
+			}
+			for trigger := range q.jobs {
+				execute(q.m, uuid.NewString(), trigger)
+				q.processed.Add(1)
+				q.pending.Add(-1)
+			}
+		}()
+	}
+}
+
+func (q *memoryQueue) enqueue(trigger domain.CloudEvent) bool {
+	q.pending.Add(1)
+	select {
+	case q.jobs <- trigger:
+		return true
+	default:
+		q.pending.Add(-1)
+		q.dropped.Add(1)
+		log.Printf("[MISSION] Queue full for %s (depth %d). Dropping trigger %s", q.m.ID, cap(q.jobs), trigger.ID)
+		return false
+	}
+}
+
+// close stops intake; workers exit once the backlog is drained.
+func (q *memoryQueue) close() { close(q.jobs) }
+
+func (q *memoryQueue) wait() { q.workers.Wait() }
+
+func (q *memoryQueue) stats() QueueStats {
+	queued := len(q.jobs)
+	inFlight := int(q.pending.Load()) - queued
+	if inFlight < 0 {
+		inFlight = 0
+	}
+	return QueueStats{
+		MissionID:   q.m.ID,
+		Concurrency: missionConcurrency(q.m),
+		Capacity:    cap(q.jobs),
+		Queued:      queued,
+		InFlight:    inFlight,
+		Processed:   q.processed.Load(),
+		Dropped:     q.dropped.Load(),
+	}
+}
+
+// --- Durable queue ---
+
+// durableQueue stores triggers in a domain.MissionQueue and lets workers claim them with a lease.
+// Runs survive restarts, and several replicas may drain the same queue. Triggers are written
+// to the store by an intake goroutine, so enqueue never waits for the database.
+type durableQueue struct {
+	m       domain.Mission
+	store   domain.MissionQueue
+	intake  chan domain.CloudEvent // Triggers not yet written to the store
+	wake    chan struct{}          // Nudges idle workers after a local enqueue
+	stop    chan struct{}
+	workers sync.WaitGroup
+
+	backlog   atomic.Int64 // Refreshed from the store on every poll
+	inFlight  atomic.Int64
+	processed atomic.Uint64
+	dropped   atomic.Uint64
+}
+
+func newDurableQueue(mission domain.Mission, store domain.MissionQueue) *durableQueue {
+	return &durableQueue{
+		m:      mission,
+		store:  store,
+		intake: make(chan domain.CloudEvent, missionQueueDepth(mission)),
+		wake:   make(chan struct{}, missionConcurrency(mission)),
+		stop:   make(chan struct{}),
+	}
+}
+
+func (q *durableQueue) mission() domain.Mission { return q.m }
+
+func (q *durableQueue) start(execute runFunc, after <-chan struct{}) {
+	for i := 0; i < missionConcurrency(q.m); i++ {
+		q.workers.Add(1)
+		go func() {
+			defer q.workers.Done()
+			if after != nil {
+				select {
+				case <-after:
+				case <-q.stop:
+					return
+				}
+			}
+			q.work(execute)
+		}()
+	}
+
+	q.workers.Add(2)
+	go func() {
+		defer q.workers.Done()
+		q.housekeep()
+	}()
+	go func() {
+		defer q.workers.Done()
+		q.receive()
+	}()
+}
+
+func (q *durableQueue) work(execute runFunc) {
+	for {
+		select {
+		case <-q.stop:
+			return
+		default:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		job, err := q.store.Claim(ctx, q.m.ID, DurableQueueLease)
+		cancel()
+		if err != nil {
+			log.Printf("[MISSION] Failed to claim run for %s: %v", q.m.ID, err)
+		}
+		if err != nil || job == nil {
+			select {
+			case <-q.stop:
+				return
+			case <-q.wake:
+			case <-time.After(DurableQueuePollInterval):
+			}
+			continue
+		}
+
+		if job.Attempts > 1 {
+			log.Printf("[MISSION] Resuming run %s of %s (attempt %d)", job.ID, q.m.ID, job.Attempts)
+		}
+		if q.backlog.Add(-1) < 0 {
+			q.backlog.Store(0)
+		}
+		q.inFlight.Add(1)
+		q.run(job, execute)
+		q.inFlight.Add(-1)
+		q.processed.Add(1)
+	}
+}
+
+// run executes a claimed job while keeping its lease alive.
+func (q *durableQueue) run(job *domain.MissionJob, execute runFunc) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(DurableQueueLease / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				if err := q.store.Extend(ctx, job.ID, DurableQueueLease); err != nil {
+					log.Printf("[MISSION] Failed to extend lease of run %s: %v", job.ID, err)
+				}
+				cancel()
+			}
+		}
+	}()
+
+	execute(q.m, job.ID, job.Trigger)
+	close(done)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := q.store.Complete(ctx, job.ID); err != nil {
+		log.Printf("[MISSION] Failed to complete run %s: %v", job.ID, err)
+	}
+}
+
+// housekeep refreshes the backlog gauge and prunes old finished runs.
+func (q *durableQueue) housekeep() {
+	lastPrune := time.Time{}
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if n, err := q.store.Backlog(ctx, q.m.ID); err == nil {
+			q.backlog.Store(int64(n))
+		}
+		if time.Since(lastPrune) > durableQueuePruneEvery {
+			if err := q.store.Prune(ctx, q.m.ID, DurableQueueRetention); err != nil {
+				log.Printf("[MISSION] Failed to prune queue of %s: %v", q.m.ID, err)
+			}
+			lastPrune = time.Now()
+		}
+		cancel()
+
+		select {
+		case <-q.stop:
+			return
+		case <-time.After(DurableQueuePollInterval):
+		}
+	}
+}
+
+func (q *durableQueue) enqueue(trigger domain.CloudEvent) bool {
+	select {
+	case q.intake <- trigger:
+		return true
+	default:
+		q.dropped.Add(1)
+		log.Printf("[MISSION] Queue full for %s (depth %d). Dropping trigger %s", q.m.ID, cap(q.intake), trigger.ID)
+		return false
+	}
+}
+
+// receive writes accepted triggers to the store. On close, triggers still in intake
+// are written before it returns.
+func (q *durableQueue) receive() {
+	for {
+		select {
+		case trigger := <-q.intake:
+			q.insert(trigger)
+		case <-q.stop:
+			for {
+				select {
+				case trigger := <-q.intake:
+					q.insert(trigger)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (q *durableQueue) insert(trigger domain.CloudEvent) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ok, err := q.store.Enqueue(ctx, q.m.ID, trigger, missionQueueDepth(q.m))
+	if err != nil {
+		q.dropped.Add(1)
+		if errors.Is(err, domain.ErrQueueFull) {
+			log.Printf("[MISSION] Queue full for %s (depth %d). Dropping trigger %s", q.m.ID, missionQueueDepth(q.m), trigger.ID)
+		} else {
+			log.Printf("[MISSION] Failed to enqueue trigger %s for %s: %v", trigger.ID, q.m.ID, err)
+		}
+		return
+	}
+	if !ok {
+		// Already queued (e.g. another replica received the same message)
+		return
+	}
+
+	q.backlog.Add(1)
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// close stops the workers after their current run. Queued runs stay in the store, and
+// triggers not yet written are written first.
+func (q *durableQueue) close() { close(q.stop) }
+
+func (q *durableQueue) wait() { q.workers.Wait() }
+
+func (q *durableQueue) stats() QueueStats {
+	return QueueStats{
+		MissionID:   q.m.ID,
+		Durable:     true,
+		Concurrency: missionConcurrency(q.m),
+		Capacity:    missionQueueDepth(q.m),
+		Queued:      int(q.backlog.Load()) + len(q.intake),
+		InFlight:    int(q.inFlight.Load()),
+		Processed:   q.processed.Load(),
+		Dropped:     q.dropped.Load(),
+	}
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+	"github.com/google/uuid"
+)
+
+// MemoryMissionQueue is an in-process domain.MissionQueue with lease semantics.
+type MemoryMissionQueue struct {
+	mu   sync.Mutex
+	jobs []*memoryJob
+}
+
+type memoryJob struct {
+	domain.MissionJob
+	status      string
+	lockedUntil time.Time
+}
+
+func (q *MemoryMissionQueue) Enqueue(ctx context.Context, missionID string, trigger domain.CloudEvent, maxPending int) (bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	pending := 0
+	for _, j := range q.jobs {
+		if j.MissionID == missionID && j.Trigger.ID == trigger.ID {
+			return false, nil
+		}
+		if j.MissionID == missionID && j.status == "pending" {
+			pending++
+		}
+	}
+	if pending >= maxPending {
+		return false, domain.ErrQueueFull
+	}
+	q.jobs = append(q.jobs, &memoryJob{
+		MissionJob: domain.MissionJob{ID: uuid.NewString(), MissionID: missionID, Trigger: trigger},
+		status:     "pending",
+	})
+	return true, nil
+}
+
+func (q *MemoryMissionQueue) Claim(ctx context.Context, missionID string, lease time.Duration) (*domain.MissionJob, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, j := range q.jobs {
+		abandoned := j.status == "running" && time.Now().After(j.lockedUntil)
+		if j.MissionID == missionID && (j.status == "pending" || abandoned) {
+			j.status = "running"
+			j.Attempts++
+			j.lockedUntil = time.Now().Add(lease)
+			job := j.MissionJob
+			return &job, nil
+		}
+	}
+	return nil, nil
+}
+
+func (q *MemoryMissionQueue) Extend(ctx context.Context, jobID string, lease time.Duration) error {
+	return nil
+}
+
+func (q *MemoryMissionQueue) Complete(ctx context.Context, jobID string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, j := range q.jobs {
+		if j.ID == jobID {
+			j.status = "done"
+		}
+	}
+	return nil
+}
+
+func (q *MemoryMissionQueue) Backlog(ctx context.Context, missionID string) (int, error) {
+	return q.count(missionID, "pending"), nil
+}
+
+func (q *MemoryMissionQueue) Prune(ctx context.Context, missionID string, olderThan time.Duration) error {
+	return nil
+}
+
+func (q *MemoryMissionQueue) count(missionID, status string) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	n := 0
+	for _, j := range q.jobs {
+		if j.MissionID == missionID && j.status == status {
+			n++
+		}
+	}
+	return n
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestMissionManager_DurableQueue(t *testing.T) {
+	queue := &MemoryMissionQueue{}
+	trigger, _ := domain.NewEvent("device-mock", "sensor.cpu.temp", nil)
+
+	// A run that was in flight when the previous process died (lease already expired)
+	queue.jobs = append(queue.jobs, &memoryJob{
+		MissionJob:  domain.MissionJob{ID: "abandoned", MissionID: "m", Trigger: trigger, Attempts: 1},
+		status:      "running",
+		lockedUntil: time.Now().Add(-time.Second),
+	})
+
+	registry := NewAgentRegistry()
+	agent := &RecordingAgent{id: "Worker"}
+	registry.Register(agent)
+	runs := &MemoryRunStore{}
+
+	manager := NewMissionManager(registry, func(string, domain.CloudEvent) {})
+	manager.SetRunStore(runs)
+	manager.SetQueue(queue)
+	if err := manager.LoadMission(domain.Mission{ID: "m", TriggerTopic: "sensor/#", Agents: []string{"Worker"}}); err != nil {
+		t.Fatalf("Failed to load mission: %v", err)
+	}
+
+	// 1. The abandoned run resumes on startup
+	waitFor(t, func() bool { return len(agent.Calls()) == 1 })
+
+	// 2. New triggers go through the queue; duplicates (other replicas) are ignored
+	fresh, _ := domain.NewEvent("device-mock", "sensor.cpu.temp", nil)
+	manager.ProcessEvent("sensor/cpu/temp", fresh)
+	manager.ProcessEvent("sensor/cpu/temp", fresh)
+	waitFor(t, func() bool { return queue.count("m", "done") == 2 })
+
+	if calls := agent.Calls(); len(calls) != 2 || calls[1].ID != fresh.ID {
+		t.Errorf("Expected the fresh trigger to run exactly once, got %d calls", len(calls))
+	}
+
+	// Run history uses the queue job ID, so a resumed run overwrites its earlier record
+	saved, _ := runs.ListMissionRuns(context.Background(), "m", 10)
+	if len(saved) != 2 || saved[0].ID != "abandoned" {
+		t.Errorf("Expected run records keyed by job ID, got %+v", saved)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := manager.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if stats := manager.Stats()[0]; !stats.Durable || stats.Processed != 2 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+// slowMissionQueue holds every Enqueue until release is closed.
+type slowMissionQueue struct {
+	*MemoryMissionQueue
+	release chan struct{}
+}
+
+func (q *slowMissionQueue) Enqueue(ctx context.Context, missionID string, trigger domain.CloudEvent, maxPending int) (bool, error) {
+	<-q.release
+	return q.MemoryMissionQueue.Enqueue(ctx, missionID, trigger, maxPending)
+}
+
+func TestMissionManager_DurableEnqueueDoesNotWaitForStore(t *testing.T) {
+	queue := &slowMissionQueue{MemoryMissionQueue: &MemoryMissionQueue{}, release: make(chan struct{})}
+	registry := NewAgentRegistry()
+	agent := &RecordingAgent{id: "Worker"}
+	registry.Register(agent)
+
+	manager := NewMissionManager(registry, func(string, domain.CloudEvent) {})
+	manager.SetQueue(queue)
+	if err := manager.LoadMission(domain.Mission{ID: "m", TriggerTopic: "sensor/#", Agents: []string{"Worker"}}); err != nil {
+		t.Fatalf("Failed to load mission: %v", err)
+	}
+
+	trigger, _ := domain.NewEvent("device-mock", "sensor.cpu.temp", nil)
+	done := make(chan struct{})
+	go func() {
+		manager.ProcessEvent("sensor/cpu/temp", trigger)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("ProcessEvent waited for the store")
+	}
+	close(queue.release)
+	waitFor(t, func() bool { return len(agent.Calls()) == 1 })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := manager.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+}
@@ -1,29 +1,204 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/datacraft/catalyst/core/internal/domain"
 )
 
+// Lifecycle call limits, so one stuck agent cannot block a reload or shutdown.
+const (
+	AgentStartTimeout  = 30 * time.Second
+	AgentStopTimeout   = 10 * time.Second
+	AgentHealthTimeout = 5 * time.Second
+
+	DefaultHeartbeatInterval = 30 * time.Second
+)
+
 // AgentRegistry manages the lifecycle and lookup of available Agents.
+// Agents implementing domain.Lifecycle are started on registration and stopped
+// when they are replaced, removed or the registry shuts down.
 type AgentRegistry struct {
 	mu     sync.RWMutex
 	agents map[string]domain.Agent
+	meta   map[string]*agentMeta // Config and execution stats by agent ID
+}
+
+type agentMeta struct {
+	config     *domain.AgentConfig
+	executions int64
+	errors     int64
+	lastExec   time.Time
+	lastError  string
+}
+
+// AgentInfo describes a running agent for the API.
+type AgentInfo struct {
+	ID             string                 `json:"id"`
+	Type           string                 `json:"type,omitempty"` // agents.yaml type, e.g. "trend-scout"
+	Description    string                 `json:"description,omitempty"`
+	Spectrum       domain.AgentType       `json:"spectrum"`
+	Safety         domain.SecurityConfig  `json:"safety"`
+	Config         map[string]interface{} `json:"config,omitempty"`
+	Health         domain.AgentHealth     `json:"health"`
+	Executions     int64                  `json:"executions"`
+	Errors         int64                  `json:"errors"`
+	LastExecutedAt *time.Time             `json:"last_executed_at,omitempty"`
+	LastError      string                 `json:"last_error,omitempty"`
+}
+
+// AgentStatus is the health of one registered agent.
+type AgentStatus struct {
+	ID     string             `json:"id"`
+	Type   domain.AgentType   `json:"type"`
+	Health domain.AgentHealth `json:"health"`
 }
 
 func NewAgentRegistry() *AgentRegistry {
 	return &AgentRegistry{
 		agents: make(map[string]domain.Agent),
+		meta:   make(map[string]*agentMeta),
 	}
 }
 
-// Register adds an agent to the registry.
-func (r *AgentRegistry) Register(agent domain.Agent) {
+// Register starts (if needed) and adds an agent to the registry, replacing and
+// stopping any agent with the same ID.
+func (r *AgentRegistry) Register(agent domain.Agent) error {
+	if err := startAgent(agent); err != nil {
+		return err
+	}
+
 	r.mu.Lock()
-	defer r.mu.Unlock()
+	old, exists := r.agents[agent.ID()]
 	r.agents[agent.ID()] = agent
+	r.mu.Unlock()
+
+	if exists && old != agent {
+		stopAgent(old)
+	}
+	return nil
+}
+
+// Replace atomically swaps the registered agents for the given set. Agents that are
+// new to the registry are started first; if any fails to start, the ones already
+// started are stopped and the registry is left unchanged. Agents that are no longer
+// registered are stopped after the swap.
+func (r *AgentRegistry) Replace(agents []domain.Agent) error {
+	r.mu.RLock()
+	current := make(map[domain.Agent]bool, len(r.agents))
+	for _, a := range r.agents {
+		current[a] = true
+	}
+	r.mu.RUnlock()
+
+	var started []domain.Agent
+	for _, a := range agents {
+		if current[a] {
+			continue
+		}
+		if err := startAgent(a); err != nil {
+			for _, s := range started {
+				stopAgent(s)
+			}
+			return err
+		}
+		started = append(started, a)
+	}
+
+	next := make(map[string]domain.Agent, len(agents))
+	kept := make(map[domain.Agent]bool, len(agents))
+	for _, a := range agents {
+		next[a.ID()] = a
+		kept[a] = true
+	}
+
+	r.mu.Lock()
+	previous := r.agents
+	r.agents = next
+	r.mu.Unlock()
+
+	for _, a := range previous {
+		if !kept[a] {
+			stopAgent(a)
+		}
+	}
+	return nil
+}
+
+// SetConfig records the configuration an agent was built from (reported by Describe).
+func (r *AgentRegistry) SetConfig(cfg domain.AgentConfig) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.metaLocked(cfg.ID).config = &cfg
+}
+
+// Description returns the configured description of an agent, or "" if it has none.
+func (r *AgentRegistry) Description(id string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if meta, ok := r.meta[id]; ok && meta.config != nil {
+		return meta.config.Description
+	}
+	return ""
+}
+
+// RecordExecution counts an agent execution and its outcome.
+func (r *AgentRegistry) RecordExecution(id string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	meta := r.metaLocked(id)
+	meta.executions++
+	meta.lastExec = time.Now().UTC()
+	if err != nil {
+		meta.errors++
+		meta.lastError = err.Error()
+	}
+}
+
+func (r *AgentRegistry) metaLocked(id string) *agentMeta {
+	meta, ok := r.meta[id]
+	if !ok {
+		meta = &agentMeta{}
+		r.meta[id] = meta
+	}
+	return meta
+}
+
+// Describe returns every registered agent with its configuration, health and execution stats, sorted by ID.
+func (r *AgentRegistry) Describe(ctx context.Context) []AgentInfo {
+	statuses := r.Health(ctx)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	infos := make([]AgentInfo, 0, len(statuses))
+	for _, st := range statuses {
+		info := AgentInfo{ID: st.ID, Spectrum: st.Type, Health: st.Health}
+		if meta, ok := r.meta[st.ID]; ok {
+			if meta.config != nil {
+				info.Type = meta.config.Type
+				info.Description = meta.config.Description
+				info.Safety = meta.config.Security
+				info.Config = meta.config.Config
+			}
+			info.Executions = meta.executions
+			info.Errors = meta.errors
+			info.LastError = meta.lastError
+			if !meta.lastExec.IsZero() {
+				last := meta.lastExec
+				info.LastExecutedAt = &last
+			}
+		}
+		infos = append(infos, info)
+	}
+	return infos
 }
 
 // Get retrieves an agent by ID.
@@ -49,3 +224,120 @@ func (r *AgentRegistry) List() []domain.Agent {
 	}
 	return list
 }
+
+// Health checks every registered agent, sorted by ID.
+func (r *AgentRegistry) Health(ctx context.Context) []AgentStatus {
+	agents := r.List()
+	sort.Slice(agents, func(i, j int) bool { return agents[i].ID() < agents[j].ID() })
+
+	statuses := make([]AgentStatus, len(agents))
+	var wg sync.WaitGroup
+	for i, a := range agents {
+		wg.Add(1)
+		go func(i int, a domain.Agent) {
+			defer wg.Done()
+			statuses[i] = AgentStatus{ID: a.ID(), Type: a.Type(), Health: checkAgent(ctx, a)}
+		}(i, a)
+	}
+	wg.Wait()
+	return statuses
+}
+
+// RunHeartbeats publishes every agent's health on agent/<id>/heartbeat each interval until ctx is cancelled.
+func (r *AgentRegistry) RunHeartbeats(ctx context.Context, interval time.Duration, publish func(topic string, event domain.CloudEvent)) {
+	if interval <= 0 {
+		interval = DefaultHeartbeatInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for _, status := range r.Health(ctx) {
+			event, err := domain.NewEvent("agent/"+status.ID, domain.HeartbeatEventType, status)
+			if err != nil {
+				continue
+			}
+			publish("agent/"+status.ID+"/heartbeat", event)
+		}
+	}
+}
+
+// Shutdown stops every registered agent and empties the registry.
+func (r *AgentRegistry) Shutdown(ctx context.Context) error {
+	r.mu.Lock()
+	agents := r.agents
+	r.agents = make(map[string]domain.Agent)
+	r.mu.Unlock()
+
+	var errs []error
+	for _, a := range agents {
+		lc, ok := a.(domain.Lifecycle)
+		if !ok {
+			continue
+		}
+		stopCtx, cancel := context.WithTimeout(ctx, AgentStopTimeout)
+		if err := lc.Stop(stopCtx); err != nil {
+			errs = append(errs, fmt.Errorf("agent %s: %w", a.ID(), err))
+		}
+		cancel()
+	}
+	return errors.Join(errs...)
+}
+
+func startAgent(a domain.Agent) error {
+	lc, ok := a.(domain.Lifecycle)
+	if !ok {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), AgentStartTimeout)
+	defer cancel()
+	if err := lc.Start(ctx); err != nil {
+		return fmt.Errorf("agent %s failed to start: %w", a.ID(), err)
+	}
+	return nil
+}
+
+func stopAgent(a domain.Agent) {
+	lc, ok := a.(domain.Lifecycle)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), AgentStopTimeout)
+	defer cancel()
+	if err := lc.Stop(ctx); err != nil {
+		log.Printf("[REGISTRY] Failed to stop agent %s: %v", a.ID(), err)
+	}
+}
+
+func checkAgent(ctx context.Context, a domain.Agent) domain.AgentHealth {
+	lc, ok := a.(domain.Lifecycle)
+	if !ok {
+		return domain.AgentHealth{Status: domain.HealthOK, CheckedAt: time.Now().UTC()}
+	}
+	ctx, cancel := context.WithTimeout(ctx, AgentHealthTimeout)
+	defer cancel()
+
+	// Do not trust the agent to honour ctx
+	result := make(chan domain.AgentHealth, 1)
+	go func() { result <- lc.Health(ctx) }()
+	var health domain.AgentHealth
+	select {
+	case health = <-result:
+	case <-ctx.Done():
+		health = domain.AgentHealth{Status: domain.HealthDown, Message: "health check timed out"}
+	}
+
+	if health.Status == "" {
+		health.Status = domain.HealthOK
+	}
+	if health.CheckedAt.IsZero() {
+		health.CheckedAt = time.Now().UTC()
+	}
+	return health
+}
@@ -2,9 +2,11 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/datacraft/catalyst/core/internal/domain"
 )
@@ -25,7 +27,9 @@ func TestAgentRegistry_Lifecycle(t *testing.T) {
 	agent := &RegistryAgent{id: "TestAgent"}
 
 	// 1. Register
-	reg.Register(agent)
+	if err := reg.Register(agent); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
 
 	// 2. Get
 	got, err := reg.Get("TestAgent")
@@ -59,7 +63,9 @@ func TestAgentRegistry_Concurrency(t *testing.T) {
 		go func(n int) {
 			defer wg.Done()
 			id := fmt.Sprintf("Agent-%d", n)
-			reg.Register(&RegistryAgent{id: id})
+			if err := reg.Register(&RegistryAgent{id: id}); err != nil {
+				t.Errorf("Register %s failed: %v", id, err)
+			}
 		}(i)
 	}
 
@@ -80,3 +86,136 @@ func TestAgentRegistry_Concurrency(t *testing.T) {
 		t.Errorf("Expected 100 agents after concurrent writes, got %d", len(list))
 	}
 }
+
+// LifecycleAgent records lifecycle calls
+type LifecycleAgent struct {
+	MockAgent
+	mu       sync.Mutex
+	started  int
+	stopped  int
+	startErr error
+	status   domain.HealthStatus
+}
+
+func (a *LifecycleAgent) Start(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.startErr != nil {
+		return a.startErr
+	}
+	a.started++
+	return nil
+}
+
+func (a *LifecycleAgent) Stop(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.stopped++
+	return nil
+}
+
+func (a *LifecycleAgent) Health(ctx context.Context) domain.AgentHealth {
+	return domain.AgentHealth{Status: a.status, Message: "checked"}
+}
+
+func (a *LifecycleAgent) counts() (int, int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.started, a.stopped
+}
+
+func TestAgentRegistry_StartStop(t *testing.T) {
+	registry := NewAgentRegistry()
+	a := &LifecycleAgent{MockAgent: MockAgent{id: "A"}, status: domain.HealthDegraded}
+	b := &LifecycleAgent{MockAgent: MockAgent{id: "B"}}
+	if err := registry.Register(a); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	// Kept agents are not restarted; new ones are started
+	if err := registry.Replace([]domain.Agent{a, b}); err != nil {
+		t.Fatalf("Replace failed: %v", err)
+	}
+	if started, _ := a.counts(); started != 1 {
+		t.Errorf("Expected A to be started once, got %d", started)
+	}
+
+	// A failing start leaves the registry untouched and stops what was started
+	c := &LifecycleAgent{MockAgent: MockAgent{id: "C"}}
+	bad := &LifecycleAgent{MockAgent: MockAgent{id: "D"}, startErr: errors.New("no")}
+	if err := registry.Replace([]domain.Agent{c, bad}); err == nil {
+		t.Fatalf("Expected Replace to fail")
+	}
+	if _, stopped := c.counts(); stopped != 1 {
+		t.Errorf("Expected C to be stopped after the failed replace")
+	}
+	if len(registry.List()) != 2 {
+		t.Errorf("Failed replace changed the registry")
+	}
+
+	// Removed agents are stopped
+	if err := registry.Replace([]domain.Agent{a}); err != nil {
+		t.Fatalf("Replace failed: %v", err)
+	}
+	if _, stopped := b.counts(); stopped != 1 {
+		t.Errorf("Expected B to be stopped")
+	}
+
+	statuses := registry.Health(context.Background())
+	if len(statuses) != 1 || statuses[0].Health.Status != domain.HealthDegraded || statuses[0].Health.CheckedAt.IsZero() {
+		t.Errorf("Unexpected health: %+v", statuses)
+	}
+
+	if err := registry.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if _, stopped := a.counts(); stopped != 1 {
+		t.Errorf("Expected A to be stopped on shutdown")
+	}
+}
+
+func TestAgentRegistry_Heartbeats(t *testing.T) {
+	registry := NewAgentRegistry()
+	registry.Register(&MockAgent{id: "Plain"})
+
+	events := make(chan string, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go registry.RunHeartbeats(ctx, 10*time.Millisecond, func(topic string, event domain.CloudEvent) {
+		if event.Type == domain.HeartbeatEventType {
+			events <- topic
+		}
+	})
+
+	select {
+	case topic := <-events:
+		if topic != "agent/Plain/heartbeat" {
+			t.Errorf("Unexpected topic %s", topic)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("No heartbeat published")
+	}
+}
+
+func TestAgentRegistry_Describe(t *testing.T) {
+	registry := NewAgentRegistry()
+	registry.Register(&MockAgent{id: "Scout"})
+	registry.SetConfig(domain.AgentConfig{ID: "Scout", Type: "trend-scout", Security: domain.SecurityConfig{ReadOnly: true}})
+	registry.RecordExecution("Scout", nil)
+	registry.RecordExecution("Scout", errors.New("sensor offline"))
+
+	infos := registry.Describe(context.Background())
+	if len(infos) != 1 {
+		t.Fatalf("Expected 1 agent, got %d", len(infos))
+	}
+	info := infos[0]
+	if info.Type != "trend-scout" || info.Spectrum != domain.AgentTypeReporter || !info.Safety.ReadOnly {
+		t.Errorf("Unexpected description: %+v", info)
+	}
+	if info.Executions != 2 || info.Errors != 1 || info.LastError != "sensor offline" || info.LastExecutedAt == nil {
+		t.Errorf("Unexpected stats: %+v", info)
+	}
+	if info.Health.Status != domain.HealthOK {
+		t.Errorf("Expected agents without lifecycle to be healthy, got %s", info.Health.Status)
+	}
+}
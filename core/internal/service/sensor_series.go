@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+)
+
+// Sensor series defaults.
+const (
+	DefaultSensorRollupInterval = time.Minute
+	MaxSeriesPoints             = 10000 // Largest (to-from)/step a query may ask for
+)
+
+// ErrInvalidSeriesQuery is returned for series queries with a bad range or step.
+var ErrInvalidSeriesQuery = errors.New("invalid series query")
+
+// SensorRetention is how long each resolution is kept (0 keeps it forever).
+type SensorRetention struct {
+	Raw    time.Duration
+	Minute time.Duration
+	Hour   time.Duration
+}
+
+// DefaultSensorRetention keeps raw readings for a week, 1m rollups for a month and 1h rollups for a year.
+var DefaultSensorRetention = SensorRetention{
+	Raw:    7 * 24 * time.Hour,
+	Minute: 30 * 24 * time.Hour,
+	Hour:   365 * 24 * time.Hour,
+}
+
+// resolution is one stored level of a sensor series, finest first.
+type resolution struct {
+	name   string
+	width  time.Duration // Smallest step it can serve
+	retain time.Duration
+}
+
+// SensorSeries records `sensor.*` readings and serves them as aggregated series.
+// Raw readings are rolled up into 1m and 1h buckets by Run, which also enforces
+// retention; queries read the coarsest resolution that still fits the step.
+type SensorSeries struct {
+	store       domain.SensorStore
+	resolutions []resolution
+	now         func() time.Time
+}
+
+// Series is the result of a series query.
+type Series struct {
+	Type       string               `json:"type"`
+	Source     string               `json:"source,omitempty"`
+	From       time.Time            `json:"from"`
+	To         time.Time            `json:"to"`
+	Step       string               `json:"step"`       // Bucket width actually used
+	Resolution string               `json:"resolution"` // Stored resolution that was read
+	Points     []domain.SeriesPoint `json:"points"`
+}
+
+func NewSensorSeries(store domain.SensorStore, retention SensorRetention) *SensorSeries {
+	return &SensorSeries{
+		store: store,
+		resolutions: []resolution{
+			{domain.ResolutionRaw, 0, retention.Raw},
+			{domain.ResolutionMinute, time.Minute, retention.Minute},
+			{domain.ResolutionHour, time.Hour, retention.Hour},
+		},
+		now: func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Record stores the reading of a `sensor.*` event. Other events and unparseable data are ignored.
+func (s *SensorSeries) Record(ctx context.Context, event domain.CloudEvent) error {
+	if !domain.IsSensorEvent(event.Type) {
+		return nil
+	}
+	reading, ok := domain.ParseSensorReading(event)
+	if !ok {
+		return nil
+	}
+	return s.store.SaveSensorReading(ctx, reading)
+}
+
+// Query returns the series of a sensor type in [from, to) with buckets of step. The step
+// is widened when the range reaches past the retention of the finer resolutions.
+func (s *SensorSeries) Query(ctx context.Context, sensorType, source string, from, to time.Time, step time.Duration) (*Series, error) {
+	if sensorType == "" {
+		return nil, fmt.Errorf("%w: sensor type is required", ErrInvalidSeriesQuery)
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidSeriesQuery)
+	}
+	if step < time.Second {
+		return nil, fmt.Errorf("%w: step must be at least 1s", ErrInvalidSeriesQuery)
+	}
+
+	res, ok := s.pick(from, step)
+	if !ok {
+		return nil, fmt.Errorf("%w: from is older than the longest retention", ErrInvalidSeriesQuery)
+	}
+	if step < res.width {
+		step = res.width
+	}
+	if step%max(res.width, time.Second) != 0 {
+		return nil, fmt.Errorf("%w: step must be a multiple of %s at this range", ErrInvalidSeriesQuery, res.width)
+	}
+	if to.Sub(from)/step > MaxSeriesPoints {
+		return nil, fmt.Errorf("%w: more than %d points, use a larger step", ErrInvalidSeriesQuery, MaxSeriesPoints)
+	}
+
+	points, err := s.store.QuerySensorSeries(ctx, domain.SeriesQuery{
+		Type: sensorType, Source: source, From: from, To: to, Step: step, Resolution: res.name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Series{
+		Type: sensorType, Source: source, From: from, To: to,
+		Step: step.String(), Resolution: res.name, Points: points,
+	}, nil
+}
+
+// pick returns the coarsest resolution whose buckets divide step and whose retention
+// covers from, or else the finest resolution that covers from.
+func (s *SensorSeries) pick(from time.Time, step time.Duration) (resolution, bool) {
+	now := s.now()
+	var (
+		best  resolution
+		found bool
+	)
+	for _, r := range s.resolutions {
+		if r.retain > 0 && from.Before(now.Add(-r.retain)) {
+			continue
+		}
+		if !found || (r.width <= step && step%max(r.width, time.Second) == 0) {
+			best, found = r, true
+		}
+		if r.width >= step {
+			break
+		}
+	}
+	return best, found
+}
+
+// Run rolls up recent readings and prunes expired data every interval until ctx is cancelled.
+func (s *SensorSeries) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultSensorRollupInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := s.Maintain(ctx, interval); err != nil {
+			log.Printf("[SENSORS] Maintenance failed: %v", err)
+		}
+	}
+}
+
+// Maintain recomputes the rollup buckets touched since the last interval (including
+// the current, partial ones) and deletes data past its retention.
+func (s *SensorSeries) Maintain(ctx context.Context, interval time.Duration) error {
+	now := s.now()
+	// Late readings up to one interval old still land in their bucket
+	since := now.Add(-2 * interval)
+
+	var errs []error
+	for _, r := range s.resolutions[1:] {
+		if err := s.store.RollupSensorReadings(ctx, r.name, since.Truncate(r.width)); err != nil {
+			errs = append(errs, fmt.Errorf("rollup %s: %w", r.name, err))
+		}
+	}
+	for _, r := range s.resolutions {
+		if r.retain <= 0 {
+			continue
+		}
+		if err := s.store.PruneSensorReadings(ctx, r.name, now.Add(-r.retain)); err != nil {
+			errs = append(errs, fmt.Errorf("prune %s: %w", r.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+)
+
+// MemorySensorStore records readings and the queries, rollups and prunes it is asked for.
+type MemorySensorStore struct {
+	mu       sync.Mutex
+	readings []domain.SensorReading
+	queries  []domain.SeriesQuery
+	rollups  map[string]time.Time
+	prunes   map[string]time.Time
+}
+
+func NewMemorySensorStore() *MemorySensorStore {
+	return &MemorySensorStore{rollups: make(map[string]time.Time), prunes: make(map[string]time.Time)}
+}
+
+func (s *MemorySensorStore) SaveSensorReading(_ context.Context, r domain.SensorReading) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.readings = append(s.readings, r)
+	return nil
+}
+
+// QuerySensorSeries buckets the raw readings, whatever the requested resolution.
+func (s *MemorySensorStore) QuerySensorSeries(_ context.Context, q domain.SeriesQuery) ([]domain.SeriesPoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queries = append(s.queries, q)
+
+	var points []domain.SeriesPoint
+	for _, r := range s.readings {
+		if r.Type != q.Type || (q.Source != "" && r.Source != q.Source) || r.Time.Before(q.From) || !r.Time.Before(q.To) {
+			continue
+		}
+		bucket := r.Time.Truncate(q.Step)
+		if n := len(points); n == 0 || !points[n-1].Time.Equal(bucket) {
+			points = append(points, domain.SeriesPoint{Time: bucket, Min: r.Value, Max: r.Value})
+		}
+		p := &points[len(points)-1]
+		p.Avg = (p.Avg*float64(p.Count) + r.Value) / float64(p.Count+1)
+		p.Count++
+		p.Min = min(p.Min, r.Value)
+		p.Max = max(p.Max, r.Value)
+	}
+	return points, nil
+}
+
+func (s *MemorySensorStore) RollupSensorReadings(_ context.Context, resolution string, since time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rollups[resolution] = since
+	return nil
+}
+
+func (s *MemorySensorStore) PruneSensorReadings(_ context.Context, resolution string, before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prunes[resolution] = before
+	return nil
+}
+
+var sensorNow = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestSensorSeries() (*SensorSeries, *MemorySensorStore) {
+	store := NewMemorySensorStore()
+	series := NewSensorSeries(store, DefaultSensorRetention)
+	series.now = func() time.Time { return sensorNow }
+	return series, store
+}
+
+func TestSensorSeries_RecordAndQuery(t *testing.T) {
+	series, _ := newTestSensorSeries()
+	ctx := context.Background()
+
+	record := func(eventType, data string, at time.Time) {
+		t.Helper()
+		event := domain.CloudEvent{Source: "dev-1", Type: eventType, Data: []byte(data), Time: at}
+		if err := series.Record(ctx, event); err != nil {
+			t.Fatalf("record failed: %v", err)
+		}
+	}
+	start := sensorNow.Add(-10 * time.Minute)
+	record("sensor.cpu.temp", `{"value": 40, "unit": "C", "label": "CPU"}`, start)
+	record("sensor.cpu.temp", `50`, start.Add(10*time.Second))
+	record("sensor.cpu.temp", `{"status": "ok"}`, start.Add(20*time.Second)) // No value: ignored
+	record("agent.output", `99`, start.Add(30*time.Second))                  // Not a sensor: ignored
+	record("sensor.cpu.temp", `"60"`, start.Add(2*time.Minute))
+
+	got, err := series.Query(ctx, "sensor.cpu.temp", "", start, sensorNow, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Points) != 2 {
+		t.Fatalf("expected 2 buckets, got %+v", got.Points)
+	}
+	if p := got.Points[0]; p.Count != 2 || p.Min != 40 || p.Max != 50 || p.Avg != 45 {
+		t.Errorf("unexpected first bucket: %+v", p)
+	}
+	if got.Step != "1m0s" {
+		t.Errorf("expected step 1m0s, got %s", got.Step)
+	}
+}
+
+func TestSensorSeries_PicksResolution(t *testing.T) {
+	series, store := newTestSensorSeries()
+	ctx := context.Background()
+
+	cases := []struct {
+		name     string
+		from     time.Time
+		step     time.Duration
+		wantRes  string
+		wantStep time.Duration
+	}{
+		{"fine step on recent data", sensorNow.Add(-time.Hour), 10 * time.Second, domain.ResolutionRaw, 10 * time.Second},
+		{"minute step", sensorNow.Add(-time.Hour), 5 * time.Minute, domain.ResolutionMinute, 5 * time.Minute},
+		{"hour step", sensorNow.Add(-48 * time.Hour), 2 * time.Hour, domain.ResolutionHour, 2 * time.Hour},
+		{"step not a multiple of 1m", sensorNow.Add(-time.Hour), 90 * time.Second, domain.ResolutionRaw, 90 * time.Second},
+		{"past raw retention", sensorNow.Add(-10 * 24 * time.Hour), 10 * time.Second, domain.ResolutionMinute, time.Minute},
+		{"past 1m retention", sensorNow.Add(-60 * 24 * time.Hour), time.Minute, domain.ResolutionHour, time.Hour},
+	}
+	for _, c := range cases {
+		got, err := series.Query(ctx, "sensor.x", "", c.from, c.from.Add(time.Hour), c.step)
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		q := store.queries[len(store.queries)-1]
+		if q.Resolution != c.wantRes || q.Step != c.wantStep || got.Resolution != c.wantRes {
+			t.Errorf("%s: expected %s/%s, got %s/%s", c.name, c.wantRes, c.wantStep, q.Resolution, q.Step)
+		}
+	}
+}
+
+func TestSensorSeries_InvalidQueries(t *testing.T) {
+	series, _ := newTestSensorSeries()
+	ctx := context.Background()
+	from := sensorNow.Add(-time.Hour)
+
+	cases := map[string]func() error{
+		"reversed range": func() error { _, err := series.Query(ctx, "sensor.x", "", sensorNow, from, time.Minute); return err },
+		"tiny step": func() error {
+			_, err := series.Query(ctx, "sensor.x", "", from, sensorNow, time.Millisecond)
+			return err
+		},
+		"too many points": func() error {
+			_, err := series.Query(ctx, "sensor.x", "", sensorNow.Add(-6*24*time.Hour), sensorNow, time.Second)
+			return err
+		},
+		"odd step past raw retention": func() error {
+			old := sensorNow.Add(-10 * 24 * time.Hour)
+			_, err := series.Query(ctx, "sensor.x", "", old, old.Add(time.Hour), 90*time.Second)
+			return err
+		},
+		"beyond retention": func() error {
+			_, err := series.Query(ctx, "sensor.x", "", sensorNow.Add(-400*24*time.Hour), sensorNow, time.Hour)
+			return err
+		},
+	}
+	for name, query := range cases {
+		if err := query(); !errors.Is(err, ErrInvalidSeriesQuery) {
+			t.Errorf("%s: expected ErrInvalidSeriesQuery, got %v", name, err)
+		}
+	}
+}
+
+func TestSensorSeries_Maintain(t *testing.T) {
+	series, store := newTestSensorSeries()
+	if err := series.Maintain(context.Background(), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := store.rollups[domain.ResolutionMinute]; !got.Equal(sensorNow.Add(-2 * time.Minute)) {
+		t.Errorf("unexpected 1m rollup start: %v", got)
+	}
+	if got := store.rollups[domain.ResolutionHour]; !got.Equal(sensorNow.Add(-time.Hour)) {
+		t.Errorf("unexpected 1h rollup start: %v", got)
+	}
+	if got := store.prunes[domain.ResolutionRaw]; !got.Equal(sensorNow.Add(-DefaultSensorRetention.Raw)) {
+		t.Errorf("unexpected raw prune cutoff: %v", got)
+	}
+	if len(store.prunes) != 3 {
+		t.Errorf("expected every resolution to be pruned, got %v", store.prunes)
+	}
+}
@@ -0,0 +1,111 @@
+// Package state implements domain.StateProvider: agent state cached in memory and,
+// when a store is configured, written through to it.
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+)
+
+// Manager caches agent state in memory. Each key is loaded from the store on first
+// use and written through on every update; updates to one key are serialized.
+type Manager struct {
+	store domain.AgentStateStore // Optional; nil keeps state in memory only
+
+	mu      sync.Mutex
+	entries map[entryKey]*entry
+}
+
+type entryKey struct {
+	agentID string
+	key     string
+}
+
+type entry struct {
+	mu     sync.Mutex
+	loaded bool
+	value  json.RawMessage
+}
+
+func NewManager(store domain.AgentStateStore) *Manager {
+	return &Manager{
+		store:   store,
+		entries: make(map[entryKey]*entry),
+	}
+}
+
+// For implements domain.StateProvider.
+func (m *Manager) For(agentID string) domain.AgentState {
+	return &agentState{manager: m, agentID: agentID}
+}
+
+func (m *Manager) entry(agentID, key string) *entry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	k := entryKey{agentID, key}
+	e, ok := m.entries[k]
+	if !ok {
+		e = &entry{}
+		m.entries[k] = e
+	}
+	return e
+}
+
+// load fills the entry from the store on first use. Callers hold e.mu.
+func (m *Manager) load(ctx context.Context, agentID, key string, e *entry) error {
+	if e.loaded {
+		return nil
+	}
+	if m.store != nil {
+		value, err := m.store.LoadAgentState(ctx, agentID, key)
+		if err != nil {
+			return err
+		}
+		e.value = value
+	}
+	e.loaded = true
+	return nil
+}
+
+type agentState struct {
+	manager *Manager
+	agentID string
+}
+
+func (s *agentState) Get(ctx context.Context, key string) (json.RawMessage, error) {
+	e := s.manager.entry(s.agentID, key)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err := s.manager.load(ctx, s.agentID, key, e); err != nil {
+		return nil, err
+	}
+	return append(json.RawMessage(nil), e.value...), nil
+}
+
+func (s *agentState) Update(ctx context.Context, key string, fn func(current json.RawMessage) (json.RawMessage, error)) error {
+	e := s.manager.entry(s.agentID, key)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err := s.manager.load(ctx, s.agentID, key, e); err != nil {
+		return err
+	}
+	next, err := fn(append(json.RawMessage(nil), e.value...))
+	if err != nil {
+		return err
+	}
+
+	// The cache only changes once the store accepted the value
+	if s.manager.store != nil {
+		if err := s.manager.store.SaveAgentState(ctx, s.agentID, key, next); err != nil {
+			return err
+		}
+	}
+	e.value = next
+	return nil
+}
@@ -0,0 +1,145 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/datacraft/catalyst/core/internal/domain"
+)
+
+// memoryStore is an in-memory domain.AgentStateStore that counts loads.
+type memoryStore struct {
+	mu      sync.Mutex
+	values  map[string]json.RawMessage
+	loads   int
+	failing bool
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{values: make(map[string]json.RawMessage)}
+}
+
+func (s *memoryStore) LoadAgentState(_ context.Context, agentID, key string) (json.RawMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loads++
+	return s.values[agentID+"/"+key], nil
+}
+
+func (s *memoryStore) SaveAgentState(_ context.Context, agentID, key string, value json.RawMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failing {
+		return errors.New("db down")
+	}
+	s.values[agentID+"/"+key] = value
+	return nil
+}
+
+type counter struct {
+	N int `json:"n"`
+}
+
+func TestManager_ConcurrentUpdates(t *testing.T) {
+	store := newMemoryStore()
+	st := NewManager(store).For("Scout")
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := domain.UpdateState(ctx, st, "sensor/a", func(c *counter) error {
+				c.N++
+				return nil
+			})
+			if err != nil {
+				t.Errorf("update failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	raw, err := st.Get(ctx, "sensor/a")
+	if err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	var c counter
+	if err := json.Unmarshal(raw, &c); err != nil {
+		t.Fatalf("invalid state %s: %v", raw, err)
+	}
+	if c.N != 50 {
+		t.Errorf("expected 50 updates, got %d", c.N)
+	}
+	if store.loads != 1 {
+		t.Errorf("expected a single load from the store, got %d", store.loads)
+	}
+}
+
+func TestManager_KeysAreScopedPerAgent(t *testing.T) {
+	m := NewManager(nil)
+	ctx := context.Background()
+
+	if err := m.For("A").Update(ctx, "k", func(json.RawMessage) (json.RawMessage, error) {
+		return json.RawMessage(`1`), nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := m.For("B").Get(ctx, "k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw != nil {
+		t.Errorf("expected agent B to see no state, got %s", raw)
+	}
+}
+
+func TestManager_SurvivesRestart(t *testing.T) {
+	store := newMemoryStore()
+	ctx := context.Background()
+
+	if err := domain.UpdateState(ctx, NewManager(store).For("Scout"), "k", func(c *counter) error {
+		c.N = 7
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A fresh manager (new process) loads the persisted value
+	raw, err := NewManager(store).For("Scout").Get(ctx, "k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(raw) != `{"n":7}` {
+		t.Errorf("expected persisted state, got %s", raw)
+	}
+}
+
+func TestManager_FailedSaveKeepsCache(t *testing.T) {
+	store := newMemoryStore()
+	st := NewManager(store).For("Scout")
+	ctx := context.Background()
+
+	set := func(n int) error {
+		return domain.UpdateState(ctx, st, "k", func(c *counter) error {
+			c.N = n
+			return nil
+		})
+	}
+	if err := set(1); err != nil {
+		t.Fatal(err)
+	}
+	store.failing = true
+	if err := set(2); err == nil {
+		t.Fatal("expected the save error to be returned")
+	}
+
+	raw, _ := st.Get(ctx, "k")
+	if string(raw) != `{"n":1}` {
+		t.Errorf("expected the cache to keep the last saved value, got %s", raw)
+	}
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"path"
+	"strings"
+)
+
+// MatchTopic reports whether an MQTT topic matches a subscription filter.
+// It implements the MQTT 3.1.1 wildcard rules:
+//   - "+" matches exactly one topic level ("sensor/+/temp")
+//   - "#" matches any number of levels, including the parent ("sensor/#")
+//   - filters starting with a wildcard never match "$"-prefixed system topics
+func MatchTopic(filter, topic string) bool {
+	if filter == "" || topic == "" {
+		return false
+	}
+	if strings.HasPrefix(topic, "$") && (filter[0] == '+' || filter[0] == '#') {
+		return false
+	}
+
+	filterLevels := strings.Split(filter, "/")
+	topicLevels := strings.Split(topic, "/")
+
+	for i, level := range filterLevels {
+		if level == "#" {
+			// "#" is only valid as the last level
+			return i == len(filterLevels)-1
+		}
+		if i >= len(topicLevels) {
+			return false
+		}
+		if level != "+" && level != topicLevels[i] {
+			return false
+		}
+	}
+
+	return len(filterLevels) == len(topicLevels)
+}
+
+// MatchType reports whether a CloudEvent type matches a glob pattern
+// (e.g. "repo.*" matches "repo.push" and "repo.issue.command").
+// Malformed patterns never match.
+func MatchType(pattern, eventType string) bool {
+	if pattern == "" || eventType == "" {
+		return false
+	}
+	matched, err := path.Match(pattern, eventType)
+	return err == nil && matched
+}
@@ -0,0 +1,52 @@
+package service
+
+import "testing"
+
+func TestMatchTopic(t *testing.T) {
+	cases := []struct {
+		filter, topic string
+		want          bool
+	}{
+		{"#", "sensor/cpu/temp", true},
+		{"sensor/#", "sensor/cpu/temp", true},
+		{"sensor/#", "sensor", true},
+		{"sensor/+", "sensor/cpu", true},
+		{"sensor/+", "sensor/cpu/temp", false},
+		{"sensor/cpu/+", "sensor/cpu/temp", true},
+		{"repo/+/event", "repo/catalyst/event", true},
+		{"repo/+/event", "repo/catalyst/push", false},
+		{"+/+", "agent/signal", true},
+		{"agent/signal", "agent/signal", true},
+		{"agent/signal", "agent/signals", false},
+		{"sensor/#/temp", "sensor/cpu/temp", false},
+		{"#", "$SYS/broker/uptime", false},
+		{"$SYS/#", "$SYS/broker/uptime", true},
+		{"", "sensor/cpu", false},
+	}
+
+	for _, c := range cases {
+		if got := MatchTopic(c.filter, c.topic); got != c.want {
+			t.Errorf("MatchTopic(%q, %q) = %v, want %v", c.filter, c.topic, got, c.want)
+		}
+	}
+}
+
+func TestMatchType(t *testing.T) {
+	cases := []struct {
+		pattern, eventType string
+		want               bool
+	}{
+		{"repo.*", "repo.push", true},
+		{"repo.*", "repo.issue.command", true},
+		{"repo.*", "sensor.cpu.temp", false},
+		{"sensor.*.temp", "sensor.cpu.temp", true},
+		{"swarm.security.alert", "swarm.security.alert", true},
+		{"[", "repo.push", false},
+	}
+
+	for _, c := range cases {
+		if got := MatchType(c.pattern, c.eventType); got != c.want {
+			t.Errorf("MatchType(%q, %q) = %v, want %v", c.pattern, c.eventType, got, c.want)
+		}
+	}
+}
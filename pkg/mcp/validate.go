@@ -0,0 +1,133 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// schema is the subset of JSON Schema used to describe tool parameters.
+type schema struct {
+	Type                 string             `json:"type"`
+	Properties           map[string]*schema `json:"properties"`
+	Required             []string           `json:"required"`
+	Items                *schema            `json:"items"`
+	Enum                 []interface{}      `json:"enum"`
+	AdditionalProperties *bool              `json:"additionalProperties"`
+}
+
+// ValidateArguments checks call arguments against the tool's parameter schema:
+// required properties, property types (string, number, integer, boolean, array, object),
+// enums, and unknown properties when additionalProperties is false.
+func (t Tool) ValidateArguments(args map[string]interface{}) error {
+	if len(t.Parameters) == 0 {
+		return nil
+	}
+	var s schema
+	if err := json.Unmarshal(t.Parameters, &s); err != nil {
+		return fmt.Errorf("tool %s has an invalid parameter schema: %w", t.Name, err)
+	}
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+	return s.validate("arguments", args)
+}
+
+func (s *schema) validate(path string, v interface{}) error {
+	switch s.Type {
+	case "", "object":
+		if s.Type == "" && s.Properties == nil {
+			break
+		}
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected object, got %s", path, typeName(v))
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				return fmt.Errorf("%s: missing required property %q", path, name)
+			}
+		}
+		names := make([]string, 0, len(obj))
+		for name := range obj {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			prop, ok := s.Properties[name]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					return fmt.Errorf("%s: unknown property %q", path, name)
+				}
+				continue
+			}
+			if err := prop.validate(path+"."+name, obj[name]); err != nil {
+				return err
+			}
+		}
+	case "string":
+		if _, ok := v.(string); !ok {
+			return fmt.Errorf("%s: expected string, got %s", path, typeName(v))
+		}
+	case "number":
+		if _, ok := v.(float64); !ok {
+			return fmt.Errorf("%s: expected number, got %s", path, typeName(v))
+		}
+	case "integer":
+		f, ok := v.(float64)
+		if !ok || f != math.Trunc(f) {
+			return fmt.Errorf("%s: expected integer, got %s", path, typeName(v))
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%s: expected boolean, got %s", path, typeName(v))
+		}
+	case "array":
+		items, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected array, got %s", path, typeName(v))
+		}
+		if s.Items != nil {
+			for i, item := range items {
+				if err := s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if len(s.Enum) > 0 {
+		for _, allowed := range s.Enum {
+			if allowed == v {
+				return nil
+			}
+		}
+		opts := make([]string, len(s.Enum))
+		for i, e := range s.Enum {
+			opts[i] = fmt.Sprint(e)
+		}
+		return fmt.Errorf("%s: must be one of %s", path, strings.Join(opts, ", "))
+	}
+	return nil
+}
+
+// typeName names the JSON type of a decoded value.
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
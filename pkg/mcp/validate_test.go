@@ -0,0 +1,53 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestValidateArguments(t *testing.T) {
+	tool := Tool{
+		Name: "git_create_issue",
+		Parameters: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"title": {"type": "string"},
+				"priority": {"type": "integer"},
+				"labels": {"type": "array", "items": {"type": "string"}},
+				"state": {"type": "string", "enum": ["open", "draft"]}
+			},
+			"required": ["title"],
+			"additionalProperties": false
+		}`),
+	}
+
+	tests := []struct {
+		name    string
+		args    string
+		wantErr bool
+	}{
+		{"valid", `{"title": "Fix login", "priority": 2, "labels": ["bug"], "state": "open"}`, false},
+		{"missing required", `{"priority": 2}`, true},
+		{"wrong type", `{"title": 42}`, true},
+		{"not an integer", `{"title": "x", "priority": 1.5}`, true},
+		{"bad array item", `{"title": "x", "labels": ["bug", 3]}`, true},
+		{"not in enum", `{"title": "x", "state": "closed"}`, true},
+		{"unknown property", `{"title": "x", "assignee": "bob"}`, true},
+	}
+	for _, tt := range tests {
+		var args map[string]interface{}
+		if err := json.Unmarshal([]byte(tt.args), &args); err != nil {
+			t.Fatal(err)
+		}
+		err := tool.ValidateArguments(args)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: ValidateArguments() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateArguments_NoSchema(t *testing.T) {
+	if err := (Tool{Name: "ping"}).ValidateArguments(nil); err != nil {
+		t.Errorf("expected tools without a schema to accept anything, got %v", err)
+	}
+}
@@ -3,6 +3,7 @@ import { Badge } from "@/components/ui/badge";
 import { ScrollArea } from "@/components/ui/scroll-area";
 import { Agent } from "@/lib/api";
 import { Terminal, Shield, Cpu, Activity, Lock } from "lucide-react";
+import { healthBadge } from "./health";
 
 interface AgentDetailDialogProps {
     agent: Agent | null;
@@ -24,13 +25,13 @@ export function AgentDetailDialog({ agent, isOpen, onClose, isAdmin }: AgentDeta
                         </div>
                         <div>
                             <DialogTitle className="text-xl font-bold flex items-center gap-2">
-                                {agent.service}
-                                <Badge variant="secondary" className="bg-emerald-100 text-emerald-700 hover:bg-emerald-100">
-                                    Active
+                                {agent.id}
+                                <Badge variant="secondary" className={healthBadge[agent.health.status]}>
+                                    {agent.health.status}
                                 </Badge>
                             </DialogTitle>
                             <DialogDescription className="font-mono text-xs mt-1">
-                                ID: {agent.id}
+                                Type: {agent.type} · {agent.spectrum}
                             </DialogDescription>
                         </div>
                     </div>
@@ -44,20 +45,28 @@ export function AgentDetailDialog({ agent, isOpen, onClose, isAdmin }: AgentDeta
                                 <Activity className="h-3 w-3" /> Runtime Status
                             </h4>
                             <div className="space-y-2 text-sm">
-                                <div className="flex justify-between">
-                                    <span className="text-slate-500">Role</span>
-                                    <span className="font-medium font-mono">{agent.role}</span>
-                                </div>
                                 <div className="flex justify-between">
                                     <span className="text-slate-500">Security Level</span>
                                     <span className="font-medium flex items-center gap-1 text-emerald-600">
-                                        <Shield className="h-3 w-3" /> Standard
+                                        <Shield className="h-3 w-3" /> {agent.safety.read_only ? "Read Only" : "Read/Write"}
                                     </span>
                                 </div>
                                 <div className="flex justify-between">
-                                    <span className="text-slate-500">Uptime</span>
-                                    <span className="font-medium font-mono">14m 22s</span>
+                                    <span className="text-slate-500">Executions</span>
+                                    <span className="font-medium font-mono">{agent.executions} ({agent.errors} errors)</span>
+                                </div>
+                                <div className="flex justify-between">
+                                    <span className="text-slate-500">Last Run</span>
+                                    <span className="font-medium font-mono">
+                                        {agent.last_executed_at ? new Date(agent.last_executed_at).toLocaleString() : "never"}
+                                    </span>
                                 </div>
+                                {agent.health.message && (
+                                    <p className="text-xs text-slate-500">{agent.health.message}</p>
+                                )}
+                                {agent.last_error && (
+                                    <p className="text-xs text-red-600 font-mono break-all">{agent.last_error}</p>
+                                )}
                             </div>
                         </div>
 
@@ -91,11 +100,11 @@ export function AgentDetailDialog({ agent, isOpen, onClose, isAdmin }: AgentDeta
 
                         <ScrollArea className="flex-1 w-full bg-slate-900 rounded-lg p-4 h-[200px] text-xs font-mono text-emerald-400 shadow-inner">
                             <pre>
-                                {JSON.stringify(agent.config || {}, null, 2)}
+                                {JSON.stringify({ config: agent.config || {}, safety: agent.safety }, null, 2)}
                             </pre>
                         </ScrollArea>
                         <p className="text-[10px] text-slate-400 mt-2 text-right">
-                            Configuration Source: Database
+                            Configuration Source: agents.yaml
                         </p>
                     </div>
                 </div>
@@ -4,6 +4,7 @@ import { Terminal, Shield, AlertTriangle, Settings, Eye } from "lucide-react";
 import { Switch } from "@/components/ui/switch";
 import { Button } from "@/components/ui/button";
 import { AgentDetailDialog } from "./AgentDetailDialog";
+import { healthBadge } from "./health";
 
 export function AgentSwarmGrid() {
     const [agents, setAgents] = useState<Agent[]>([]);
@@ -57,17 +58,17 @@ export function AgentSwarmGrid() {
                             <div className="p-3 bg-indigo-50 group-hover:bg-indigo-100 transition-colors rounded-lg text-indigo-600">
                                 <Terminal className="h-6 w-6" />
                             </div>
-                            <span className="px-2 py-1 bg-emerald-100 text-emerald-700 text-xs font-bold rounded uppercase tracking-wide">Active</span>
+                            <span className={`px-2 py-1 text-xs font-bold rounded uppercase tracking-wide ${healthBadge[agent.health.status]}`}>{agent.health.status}</span>
                         </div>
 
                         <div>
-                            <h3 className="font-bold text-lg text-slate-800 group-hover:text-indigo-700 transition-colors">{agent.service}</h3>
-                            <p className="text-sm text-slate-500 font-mono">{agent.role}</p>
+                            <h3 className="font-bold text-lg text-slate-800 group-hover:text-indigo-700 transition-colors">{agent.id}</h3>
+                            <p className="text-sm text-slate-500 font-mono">{agent.type} · {agent.spectrum}</p>
                         </div>
 
                         <div className="mt-auto pt-4 border-t border-slate-100 flex items-center justify-between">
                             <div className="flex items-center gap-1 text-xs text-slate-400 font-mono">
-                                <Shield className="h-3 w-3" /> Secure
+                                <Shield className="h-3 w-3" /> {agent.safety.read_only ? "Read Only" : "Read/Write"}
                             </div>
 
                             {/* Hover Actions */}
@@ -0,0 +1,8 @@
+import { AgentHealth } from "../../lib/api";
+
+// Badge colours by agent health status
+export const healthBadge: Record<AgentHealth["status"], string> = {
+    ok: "bg-emerald-100 text-emerald-700",
+    degraded: "bg-amber-100 text-amber-700",
+    down: "bg-red-100 text-red-700",
+};
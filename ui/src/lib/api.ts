@@ -13,11 +13,30 @@ export interface Context {
     local_path?: string;
 }
 
+export interface AgentHealth {
+    status: "ok" | "degraded" | "down";
+    message?: string;
+    checked_at: string;
+}
+
+export interface AgentSafety {
+    sandbox_path?: string;
+    allowed_patterns?: string[];
+    denied_patterns?: string[];
+    read_only: boolean;
+}
+
 export interface Agent {
     id: string;
-    service: string;
-    role: string;
-    config: any;
+    type?: string;
+    spectrum: "reporter" | "communicator" | "expressor";
+    safety: AgentSafety;
+    config?: any;
+    health: AgentHealth;
+    executions: number;
+    errors: number;
+    last_executed_at?: string;
+    last_error?: string;
 }
 
 const API_BASE = "http://localhost:8080/api";